@@ -0,0 +1,54 @@
+CREATE TABLE "networks" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "code" character varying NOT NULL,
+  "name" character varying NOT NULL,
+  "address_regex" character varying NOT NULL DEFAULT '',
+  "memo_regex" character varying NOT NULL DEFAULT ''
+);
+
+CREATE TABLE "exchange_coin_networks" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "exchange_coin_id" uuid NOT NULL,
+  "network_id" uuid NOT NULL,
+  "code" character varying NOT NULL,
+  "deposit_enabled" boolean NOT NULL,
+  "withdraw_enabled" boolean NOT NULL,
+  "withdraw_fee" numeric NOT NULL,
+  "withdraw_min" numeric NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "networks"
+ADD PRIMARY KEY ("id");
+
+ALTER TABLE "exchange_coin_networks"
+ADD PRIMARY KEY ("id");
+
+ALTER TABLE "networks"
+ADD CONSTRAINT "network_code_unique"
+UNIQUE ("code");
+
+ALTER TABLE "exchange_coin_networks"
+ADD CONSTRAINT "FK_EXCHANGE_COIN_ID"
+FOREIGN KEY ("exchange_coin_id") REFERENCES "exchange_coins" ("id");
+
+ALTER TABLE "exchange_coin_networks"
+ADD CONSTRAINT "FK_NETWORK_ID"
+FOREIGN KEY ("network_id") REFERENCES "networks" ("id");
+
+ALTER TABLE "exchange_coin_networks"
+ADD CONSTRAINT "exchange_coin_network_unique"
+UNIQUE ("exchange_coin_id", "network_id");
+
+INSERT INTO "networks" ("code", "name", "address_regex", "memo_regex") VALUES
+('BTC', 'Bitcoin', '^[13][a-km-zA-HJ-NP-Z1-9]{25,34}$|^(bc1)[0-9A-Za-z]{39,59}$', ''),
+('ETH', 'Ethereum (ERC20)', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('BSC', 'BNB Smart Chain (BEP20)', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('TRX', 'Tron (TRC20)', '^T[1-9A-HJ-NP-Za-km-z]{33}$', ''),
+('SOL', 'Solana', '^[1-9A-HJ-NP-Za-km-z]{32,44}$', ''),
+('MATIC', 'Polygon', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('ARBITRUM', 'Arbitrum One', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('OPTIMISM', 'Optimism', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('AVAXC', 'AVAX C-Chain', '^(0x)[0-9A-Fa-f]{40}$', ''),
+('XRP', 'Ripple', '^r[1-9A-HJ-NP-Za-km-z]{25,34}$', '^[0-9]{1,10}$'),
+('TON', 'The Open Network', '^[A-Za-z0-9_-]{48}$', '^[0-9A-Za-z\-_]{1,120}$');
@@ -0,0 +1,30 @@
+-- name: UpsertNetwork :one
+-- the regexes are only replaced by non-empty ones, as few exchanges give them
+INSERT INTO "networks" ("code", "name", "address_regex", "memo_regex")
+VALUES ($1, $2, $3, $4)
+ON CONFLICT ("code") DO UPDATE SET
+  "address_regex" = COALESCE(NULLIF(EXCLUDED.address_regex, ''), networks.address_regex),
+  "memo_regex" = COALESCE(NULLIF(EXCLUDED.memo_regex, ''), networks.memo_regex)
+RETURNING id;
+
+-- name: SelectNetworks :many
+SELECT * FROM networks;
+
+-- name: UpsertExchangeCoinNetwork :exec
+INSERT INTO "exchange_coin_networks" ("exchange_coin_id", "network_id", "code", "deposit_enabled", "withdraw_enabled", "withdraw_fee", "withdraw_min")
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT ("exchange_coin_id", "network_id") DO UPDATE SET
+  "code" = EXCLUDED.code,
+  "deposit_enabled" = EXCLUDED.deposit_enabled,
+  "withdraw_enabled" = EXCLUDED.withdraw_enabled,
+  "withdraw_fee" = EXCLUDED.withdraw_fee,
+  "withdraw_min" = EXCLUDED.withdraw_min,
+  "updated_at" = now();
+
+-- name: SelectExchangeCoinNetworks :many
+SELECT ecn.id, e.name AS exchange_name, ec.base, ecn.code, n.code AS network, n.address_regex, n.memo_regex,
+  ecn.deposit_enabled, ecn.withdraw_enabled, ecn.withdraw_fee, ecn.withdraw_min, ecn.updated_at
+FROM "exchange_coin_networks" ecn
+JOIN "exchange_coins" ec ON ec.id = ecn.exchange_coin_id
+JOIN "exchanges" e ON e.id = ec.exchange_id
+JOIN "networks" n ON n.id = ecn.network_id;
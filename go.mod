@@ -2,20 +2,23 @@ module github.com/ArbitrageCoin/crypto-sdk
 
 go 1.22.0
 
+require (
+	github.com/antihax/optional v1.0.0
+	github.com/jackc/pgx/v5 v5.5.5
+	github.com/shopspring/decimal v1.4.0
+)
+
 require (
 	github.com/Masterminds/semver/v3 v3.2.1 // indirect
-	github.com/antihax/optional v1.0.0 // indirect
 	github.com/elazarl/goproxy v0.0.0-20231117061959-7cc037d33fb5 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
-	github.com/jackc/pgx/v5 v5.5.5 // indirect
 	github.com/jackc/puddle/v2 v2.2.1 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
-	github.com/shopspring/decimal v1.4.0 // indirect
 	github.com/smartystreets/goconvey v1.8.1 // indirect
-	golang.org/x/crypto v0.22.0 // indirect
+	golang.org/x/crypto v0.22.0
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
@@ -28,7 +31,5 @@ require (
 	github.com/bitly/go-simplejson v0.5.0 // indirect
 	github.com/gateio/gateapi-go/v6 v6.67.0
 	github.com/google/uuid v1.6.0
-	github.com/gorilla/websocket v1.5.0 // indirect
-	github.com/parnurzeal/gorequest v0.2.16
-	github.com/solos/requests v0.0.0-20170614095436-95c11f6f3d3c
+	github.com/gorilla/websocket v1.5.0
 )
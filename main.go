@@ -7,20 +7,24 @@ import (
 	"log"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/aggregator"
+	"github.com/ArbitrageCoin/crypto-sdk/src/arbitrage"
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
-	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/secrets"
 	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/coingecko"
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
 )
 
 type config struct {
-	Brokers map[string]broker.Config
+	// Brokers are the brokers to enable, keyed by the name of their exchange in the exchanges table
+	Brokers broker.BrokersConfig
+	// Arbitrage sets the thresholds of the arbitrage engine, its brokers being the ones above
+	Arbitrage arbitrage.Config
+	// Secrets is the provider of the keys and the secrets referenced by the brokers through KeyRef and SecretRef
+	Secrets *secrets.Config
 }
 
 func getAllCoinsInfo(exchanges map[string]database.Exchange) (broker.CoinsMap, map[string]broker.ExchangeCoinsMap, map[string]broker.ExchangeTickersMap) {
@@ -84,249 +88,177 @@ func getAllCoinsInfo(exchanges map[string]database.Exchange) (broker.CoinsMap, m
 	return coinsMap, exchangeToExchangeCoins, tickerToExchangeTickers
 }
 
-func loadBrokers() map[string]broker.IBroker {
-	brokers := make(map[string]broker.IBroker)
-
+// loadConfig reads config.json, the startup being aborted when it is invalid
+func loadConfig() config {
 	data, err := os.ReadFile("config.json")
 	if err != nil {
-		panic(err)
+		log.Fatalf("unable to read config.json: %v", err)
 	}
 	var c config
 	if err := json.Unmarshal(data, &c); err != nil {
-		panic(err)
+		log.Fatalf("invalid config.json: %v", err)
 	}
-
-	binance, _ := broker.NewBinance(c.Brokers["Binance"])
-	brokers[binance.GetBrokerName()] = binance
-
-	mexc, _ := broker.NewMEXC(c.Brokers["MEXC"])
-	brokers[mexc.GetBrokerName()] = mexc
-
-	gate, _ := broker.NewGate(c.Brokers["Gate"])
-	brokers[gate.GetBrokerName()] = gate
-
-	xt, _ := broker.NewXT(c.Brokers["XT"])
-	brokers[xt.GetBrokerName()] = xt
-
-	bitrue, _ := broker.NewBitrue(c.Brokers["Bitrue"])
-	brokers[bitrue.GetBrokerName()] = bitrue
-
-	return brokers
-}
-
-var (
-	brokers                               = loadBrokers()
-	exchanges                             = getExchanges()
-	coins, exchangeCoins, exchangeTickers = getAllCoinsInfo(exchanges)
-)
-
-func main() {
-	//mergeNetworks()
-	//populateDb()
-	getOpportunities()
+	return c
 }
 
-type arbitrageResult struct {
-	quantityToBuy decimal.Decimal
-	usdForBuying  decimal.Decimal
-	usdForSelling decimal.Decimal
-}
+// loadBrokers builds the brokers enabled in config.json, the startup being aborted when one of them cannot be
+// built. It returns the broker giving the market data of each exchange, and the brokers of its accounts in the
+// order of config.json, the first one being the former.
+func loadBrokers() (map[string]broker.IBroker, map[string][]broker.IBroker) {
+	c := loadConfig()
 
-func calculateArbitrage(asks, bids coin.OrderBook) arbitrageResult {
-	results := arbitrageResult{
-		quantityToBuy: decimal.Zero,
-		usdForBuying:  decimal.Zero,
-		usdForSelling: decimal.Zero,
+	var provider secrets.Provider
+	var err error
+	if c.Secrets != nil {
+		if provider, err = secrets.New(*c.Secrets); err != nil {
+			log.Fatalf("unable to open the secrets: %v", err)
+		}
+	}
+	brokersConfig, err := c.Brokers.Resolve(context.Background(), provider)
+	if err != nil {
+		log.Fatalf("unable to resolve the secrets of the brokers: %v", err)
 	}
 
-	minProfitabilityPercent, _ := decimal.NewFromString("1.1")
-	maximumToSpend, _ := decimal.NewFromString("1000")
-
-	askIndex := 0
-out:
-	for _, bid := range bids.Bids {
-		for askIndex < len(asks.Asks) {
-			ask := asks.Asks[askIndex]
-
-			// If the buyer is offering a price lower than the price we can buy it, taking into account the minimum profitability, then stop
-			if bid.Price.GreaterThan(ask.Price.Mul(minProfitabilityPercent)) {
-				break out
-			}
-
-			canSpend := maximumToSpend.Sub(results.usdForBuying)
-
-			// If there are not enough quantity in the current bid for the slot we want to buy
-			if bid.Quantity.LessThan(ask.Quantity) {
-				// We take the quantity of the bid, but the price of the ask, as the bid price is
-				// the price we well it to. The price we pay for 1 of quantity is ask.Price
-				askTotalPrice := ask.Price.Mul(bid.Quantity)
-				// If we cannot buy the whole bid
-				if askTotalPrice.GreaterThan(canSpend) {
-					qty := canSpend.Div(ask.Price)
-					results.quantityToBuy = results.quantityToBuy.Add(qty)
-					results.usdForBuying = results.usdForBuying.Add(ask.Price.Mul(qty))
-					results.usdForSelling = results.usdForSelling.Add(bid.Price.Mul(qty))
-					break out
-				}
-
-				// If we can buy the whole bid, buy it and check the next one
-				results.quantityToBuy = results.quantityToBuy.Add(bid.Quantity)
-				results.usdForBuying = results.usdForBuying.Add(ask.Price.Mul(bid.Quantity))
-				results.usdForSelling = results.usdForSelling.Add(bid.Price.Mul(bid.Quantity))
-				asks.Asks[askIndex].Quantity = ask.Quantity.Sub(bid.Quantity)
-				break // Move to another bid
-			}
+	loaded, err := broker.DefaultRegistry.Load(brokersConfig)
+	if err != nil {
+		log.Fatalf("unable to load the brokers: %v", err)
+	}
 
-			askTotalPrice := ask.Price.Mul(ask.Quantity)
-			//If we cannot buy the entire offer, buy a chunk and stop
-			if askTotalPrice.GreaterThanOrEqual(canSpend) {
-				qty := canSpend.Div(ask.Price)
-				results.usdForBuying = results.usdForBuying.Add(qty.Mul(ask.Price))
-				results.usdForSelling = results.usdForSelling.Add(qty.Mul(bid.Price))
-				results.quantityToBuy = results.quantityToBuy.Add(qty)
-				break out
-			}
+	brokers := make(map[string]broker.IBroker)
+	accounts := make(map[string][]broker.IBroker)
+	for _, account := range loaded {
+		b := useClock(account.Broker)
+		if _, ok := brokers[account.Exchange]; !ok {
+			b = useStream(account.Exchange, b, account.Config)
+		}
+		b = usePaper(b, account.Config)
 
-			// Otherwise just buy the entire ask and move to another ask
-			results.quantityToBuy = results.quantityToBuy.Add(ask.Quantity)
-			results.usdForBuying = results.usdForBuying.Add(askTotalPrice)
-			results.usdForSelling = results.usdForSelling.Add(ask.Quantity.Mul(bid.Price))
-			bid.Quantity = bid.Quantity.Sub(ask.Quantity)
-			askIndex++
+		if _, ok := brokers[account.Exchange]; !ok {
+			brokers[account.Exchange] = b
 		}
+		accounts[account.Exchange] = append(accounts[account.Exchange], b)
 	}
-
-	return results
-}
-
-type analyzeResult struct {
-	ExchangeBuy  string
-	ExchangeSell string
-	Ticker       coin.TickerPair
-	Results      arbitrageResult
+	return brokers, accounts
 }
 
-func realAnalyze(tickerPair coin.TickerPair, exchanges map[string]broker.CoinAllInfo) analyzeResult {
-	minProfit, _ := decimal.NewFromString("1.1")
-	for brokerName, tickerValues := range exchanges {
-		for brokerName2, tickerValues2 := range exchanges {
-			if brokerName == brokerName2 {
-				continue
-			}
-
-			if tickerValues.Values.HighestBid.GreaterThan(tickerValues2.Values.LowestAsk.Mul(minProfit)) {
-				ob1, err := brokers[brokerName].GetOrderBooks(context.Background(), tickerValues.ExchangeTicker)
-				if err != nil {
-					panic(err)
-				}
-				ob2, err := brokers[brokerName2].GetOrderBooks(context.Background(), tickerValues2.ExchangeTicker)
-				if err != nil {
-					panic(err)
-				}
-
-				results := calculateArbitrage(ob1, ob2)
-				if results.quantityToBuy == decimal.Zero || results.usdForBuying == decimal.Zero || results.usdForSelling == decimal.Zero {
-					return analyzeResult{}
-				}
+// useStream starts streaming the order books of b, trading on exchange, when the broker is configured for it
+func useStream(exchange string, b broker.IBroker, config broker.Config) broker.IBroker {
+	if !config.Stream {
+		return b
+	}
 
-				return analyzeResult{
-					ExchangeBuy:  brokerName,
-					ExchangeSell: brokerName2,
-					Results:      results,
-					Ticker:       tickerPair,
-				}
-			}
-		}
+	streamer, ok := b.(broker.IMarketDataStreamer)
+	if !ok {
+		panic(fmt.Sprintf("%v cannot stream its order books", b.GetBrokerName()))
 	}
-	return analyzeResult{}
+	streams[exchange] = streamer.NewMarketDataStream()
+	return b
 }
 
-func analyze(tickers map[coin.TickerPair]map[string]broker.CoinAllInfo) {
-	ch := make(chan analyzeResult)
-
-	go func() {
-		wg := sync.WaitGroup{}
-		wg.Add(len(tickers))
-
-		for tickerPair, exchanges := range tickers {
-			if len(exchanges) < 2 {
-				wg.Done()
-				continue
-			}
+const (
+	// clockSyncInterval is how often the clocks of the exchanges are synchronized
+	clockSyncInterval = 5 * time.Minute
+	// maxClockDrift is the drift of the local clock above which it is reported at each scan
+	maxClockDrift = time.Second
+)
 
-			go func(exchanges map[string]broker.CoinAllInfo, tickerPair coin.TickerPair) {
-				defer wg.Done()
-				res := realAnalyze(tickerPair, exchanges)
-				if res.ExchangeBuy == "" || res.ExchangeSell == "" {
-					return
-				}
+// useClock keeps the clock of b synchronized with the one of the exchange, when b timestamps its requests
+func useClock(b broker.IBroker) broker.IBroker {
+	synchronizer, ok := b.(broker.IClockSynchronizer)
+	if !ok || synchronizer.Clock() == nil {
+		return b
+	}
+	clocks[b.GetBrokerName()] = synchronizer.Clock()
+	go synchronizer.Clock().Run(context.Background(), clockSyncInterval)
+	return b
+}
 
-				res.Ticker = tickerPair
-				ch <- res
-			}(exchanges, tickerPair)
+// reportClocks prints the exchanges whose clock drifts from the local one, or could not be synchronized
+func reportClocks() {
+	for brokerName, clock := range clocks {
+		health := clock.Health()
+		if health.Err != nil {
+			fmt.Println("the clock of", brokerName, "could not be synchronized:", health.Err)
+		} else if health.Offset.Abs() > maxClockDrift {
+			fmt.Println("the local clock drifts from", brokerName, "by", health.Offset, "(RTT", health.RTT.String()+")")
 		}
+	}
+}
 
-		wg.Wait()
-		close(ch)
-	}()
+// usePaper wraps b into a PaperBroker when the broker is configured for paper trading
+func usePaper(b broker.IBroker, config broker.Config) broker.IBroker {
+	if !config.Paper {
+		return b
+	}
 
-	var results []analyzeResult
-	for res := range ch {
-		results = append(results, res)
+	paper, err := broker.NewPaperBroker(config, b)
+	if err != nil {
+		panic(err)
 	}
+	return paper
+}
 
-	for _, res := range results {
-		ticker := tickers[res.Ticker]
-		tickerBuy := ticker[res.ExchangeBuy]
-		tickerSell := ticker[res.ExchangeSell]
-		if err := brokers[res.ExchangeBuy].CanBuyAndWithdraw(context.Background(), tickerBuy.ExchangeTicker); err != nil {
-			continue
-		}
-		if err := brokers[res.ExchangeSell].CanDepositAndSell(context.Background(), tickerSell.ExchangeTicker); err != nil {
-			continue
-		}
+var (
+	streams                               = make(map[string]*broker.MarketDataStream)
+	clocks                                = make(map[string]*broker.Clock)
+	brokers, accounts                     = loadBrokers()
+	exchanges                             = getExchanges()
+	coins, exchangeCoins, exchangeTickers = getAllCoinsInfo(exchanges)
+)
 
-		fmt.Println(tickerBuy.ExchangeCoinBase.Base, "_", tickerBuy.ExchangeCoinQuote.Base, "Buying from", res.ExchangeBuy, "Selling on", res.ExchangeSell, res.Results.quantityToBuy.String(), res.Results.usdForBuying.String(), res.Results.usdForSelling.String())
-		fmt.Println()
-	}
+func main() {
+	//populateDb()
+	//refreshNetworks()
+	getOpportunities()
 }
 
+// scanTimeout is the time a scan of the opportunities has to get every ticker and order book it needs
+const scanTimeout = 50 * time.Second
+
 func getOpportunities() {
-	for exchangeName, _ := range exchanges {
-		brokers[exchangeName].RefreshCoinsInformation(coins, exchangeCoins[exchangeName], exchangeTickers[exchangeName])
-		if err := brokers[exchangeName].RefreshExchangeInformation(context.Background()); err != nil {
-			panic(err)
+	for exchangeName, bs := range accounts {
+		for _, b := range bs {
+			if _, ok := exchanges[exchangeName]; !ok {
+				log.Fatalf("the broker %v trades on %v, which is not in the exchanges table", b.GetBrokerName(), exchangeName)
+			}
+			b.RefreshCoinsInformation(coins, exchangeCoins[exchangeName], exchangeTickers[exchangeName])
+			if err := b.RefreshExchangeInformation(context.Background()); err != nil {
+				panic(err)
+			}
 		}
 	}
 
+	arbitrageConfig := loadConfig().Arbitrage
+	arbitrageConfig.Brokers = accounts
+	arbitrageConfig.Streams = streams
+	engine, err := arbitrage.NewEngine(context.Background(), arbitrageConfig)
+	if err != nil {
+		log.Fatalf("unable to create the arbitrage engine: %v", err)
+	}
+
 	for {
-		allTickers := make(map[string]map[coin.TickerPair]broker.CoinAllInfo)
-		for _, b := range brokers {
-			answTickers, err := b.GetTickersInformation(context.Background())
-			if err != nil {
-				panic(err)
-			}
-			allTickers[b.GetBrokerName()] = answTickers
-		}
+		reportClocks()
 
-		allTickersSorted := make(map[coin.TickerPair]map[string]broker.CoinAllInfo)
-		for brokerName, tickers := range allTickers {
-			for tickerPair, tickerValues := range tickers {
-				tickerValuesSorted, ok := allTickersSorted[tickerPair]
-				if !ok {
-					tickerValuesSorted = make(map[string]broker.CoinAllInfo)
-				}
-				tickerValuesSorted[brokerName] = tickerValues
-				allTickersSorted[tickerPair] = tickerValuesSorted
-			}
+		// A slow exchange cannot hold the scan beyond the next one, its requests being cancelled
+		ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
+		err := engine.Scan(ctx, printOpportunity)
+		cancel()
+		if err != nil {
+			fmt.Println("some opportunities could not be analyzed:", err)
 		}
 
-		analyze(allTickersSorted)
-
 		time.Sleep(1 * time.Minute)
 	}
 }
 
+// printOpportunity prints an opportunity found by the arbitrage engine, with the detail of its fees
+func printOpportunity(o arbitrage.Opportunity) {
+	r := o.Result
+	fmt.Println(o.BuyTicker.Base, "_", o.BuyTicker.Quote, "Buying from", o.BuyExchange, "("+o.BuyAccount.GetBrokerName()+")", "Selling on", o.SellExchange, "("+o.SellAccount.GetBrokerName()+")", "through", o.Network, r.Quantity.String(), r.Spend.String(), r.Proceeds.String())
+	fmt.Println("Fees: buy", r.BuyFee.String(), "sell", r.SellFee.String(), "withdraw", r.WithdrawFee.String(), "deposit", r.DepositFee.String(), "Net profit", r.NetProfit.String())
+	fmt.Println()
+}
+
 func getExchangeNameFromAlias(exchName string) string {
 	exchNameLower := strings.ToLower(exchName)
 	if exchNameLower == "binance" {
@@ -355,8 +287,13 @@ func getExchanges() map[string]database.Exchange {
 		panic(err)
 	}
 	exchanges := make(map[string]database.Exchange)
+	names := make([]string, 0, len(exchangesArr))
 	for _, exchange := range exchangesArr {
 		exchanges[exchange.Name] = exchange
+		names = append(names, exchange.Name)
+	}
+	if err := broker.DefaultRegistry.Validate(names); err != nil {
+		log.Fatalf("invalid exchanges table: %v", err)
 	}
 	return exchanges
 }
@@ -539,14 +476,16 @@ func populateDb() {
 }
 
 func getBalance() {
-	brokers := loadBrokers()
-	for _, broker := range brokers {
-		wallet, err := broker.GetBalance(context.Background())
-		if err != nil {
-			fmt.Println(err)
-			continue
+	_, accounts := loadBrokers()
+	for _, brokers := range accounts {
+		for _, broker := range brokers {
+			wallet, err := broker.GetBalance(context.Background())
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			fmt.Println(broker.GetBrokerName(), wallet)
 		}
-		fmt.Println(broker.GetBrokerName(), wallet)
 	}
 }
 
@@ -554,10 +493,11 @@ func coingeckoExtract() {
 	cg, _ := aggregator.NewCoinGecko(aggregator.Config{
 		Key: "...",
 	})
+	ctx := context.Background()
 
 	data, err := os.ReadFile("coingecko-data/coingecko-coins.json")
 	if err != nil {
-		coins, err := cg.GetCoins()
+		coins, err := cg.GetCoins(ctx)
 		if err != nil {
 			panic(err)
 		}
@@ -586,7 +526,7 @@ func coingeckoExtract() {
 		if err == nil {
 			continue // if file already exist, skip it
 		}
-		ticker, err := cg.GetCoinInfo(coin.Id)
+		ticker, err := cg.GetCoinInfo(ctx, coin.Id)
 		if err != nil {
 			fmt.Println(err)
 			time.Sleep(55 * time.Second)
@@ -623,7 +563,7 @@ func getOrderBook() {
 
 	fmt.Println(rows)
 
-	brokers := loadBrokers()
+	brokers, _ := loadBrokers()
 
 	for _, row := range rows {
 		broker, ok := brokers[row.Name]
@@ -642,45 +582,72 @@ func getOrderBook() {
 	}
 }
 
-func mergeNetworks() {
-	type network struct {
-		Code          string
-		Name          string
-		AddressRegexp string
-		MemoRegexp    string
+// refreshNetworks stores the networks of every coin of every broker, each one being mapped to its canonical chain
+func refreshNetworks() {
+	db, err := database.NewDatabase("postgres", "postgres", "postgres")
+	if err != nil {
+		panic(err)
 	}
+	defer db.Close()
 
-	var mexc_networks []network
-	var binance_networks []network
+	ctx := context.Background()
+	networkIDs := make(map[string]uuid.UUID)
 
-	mexc_data, _ := os.ReadFile("blockchain-network-data/networks_mexc.json")
-	binance_data, _ := os.ReadFile("blockchain-network-data/networks_binance.json")
+	for exchangeName := range exchanges {
+		b, ok := brokers[exchangeName]
+		if !ok {
+			continue
+		}
 
-	json.Unmarshal(mexc_data, &mexc_networks)
-	json.Unmarshal(binance_data, &binance_networks)
+		exchangeCoinIDs := make(map[string]uuid.UUID)
+		for id, ec := range exchangeCoins[exchangeName] {
+			exchangeCoinIDs[strings.ToUpper(ec.Base)] = id
+		}
 
-	type has struct {
-		Binance network
-		Mexc    network
-	}
+		networks, err := b.GetNetworks(ctx)
+		if err != nil {
+			fmt.Println(exchangeName, err)
+			continue
+		}
 
-	mapHas := make(map[string]*has)
+		for _, network := range networks {
+			exchangeCoinID, ok := exchangeCoinIDs[strings.ToUpper(network.Coin)]
+			if !ok {
+				// the coin is not tracked on this exchange
+				continue
+			}
 
-	for _, network := range mexc_networks {
-		mapHas[network.Code] = &has{}
-	}
-	for _, network := range binance_networks {
-		mapHas[network.Code] = &has{}
-	}
+			code := network.CanonicalCode()
+			networkID, ok := networkIDs[code]
+			if !ok || network.AddressRegex != "" || network.MemoRegex != "" {
+				name := network.Name
+				if name == "" {
+					name = code
+				}
+				networkID, err = db.Queries.UpsertNetwork(ctx, database.UpsertNetworkParams{
+					Code:         code,
+					Name:         name,
+					AddressRegex: network.AddressRegex,
+					MemoRegex:    network.MemoRegex,
+				})
+				if err != nil {
+					panic(err)
+				}
+				networkIDs[code] = networkID
+			}
 
-	for _, network := range mexc_networks {
-		mapHas[network.Code].Mexc = network
-	}
-	for _, network := range binance_networks {
-		mapHas[network.Code].Binance = network
+			if err := db.Queries.UpsertExchangeCoinNetwork(ctx, database.UpsertExchangeCoinNetworkParams{
+				ExchangeCoinID:  exchangeCoinID,
+				NetworkID:       networkID,
+				Code:            network.Code,
+				DepositEnabled:  network.DepositPossible,
+				WithdrawEnabled: network.WithdrawPossible,
+				WithdrawFee:     network.WithdrawFee,
+				WithdrawMin:     network.WithdrawMin,
+			}); err != nil {
+				panic(err)
+			}
+		}
+		fmt.Println(exchangeName, len(networks), "networks")
 	}
-
-	data, _ := json.MarshalIndent(mapHas, "", "\t")
-
-	os.WriteFile("networks-compiled.json", data, 0777)
 }
@@ -1,23 +1,31 @@
 package aggregator
 
-import "github.com/ArbitrageCoin/crypto-sdk/src/third_parties/coingecko"
+import (
+	"context"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/coingecko"
+)
 
 type Config struct {
-	Key string
+	Key  string
+	HTTP httpclient.Config
 }
 
 type CoinGecko struct {
 	config Config
+	client *coingecko.Client
 }
 
 func NewCoinGecko(config Config) (*CoinGecko, error) {
 	return &CoinGecko{
 		config: config,
+		client: coingecko.NewClient(config.HTTP.BaseURL, config.HTTP.HTTPClient()),
 	}, nil
 }
 
-func (a CoinGecko) GetCoins() (map[string]coingecko.Coin, error) {
-	coins, err := coingecko.GetCoinsList(a.config.Key)
+func (a CoinGecko) GetCoins(ctx context.Context) (map[string]coingecko.Coin, error) {
+	coins, err := a.client.GetCoinsList(ctx, a.config.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -30,8 +38,8 @@ func (a CoinGecko) GetCoins() (map[string]coingecko.Coin, error) {
 	return out, nil
 }
 
-func (a CoinGecko) GetCoinInfo(coin string) (coingecko.CryptoData, error) {
-	ticker, err := coingecko.GetCoinTickers(a.config.Key, coin)
+func (a CoinGecko) GetCoinInfo(ctx context.Context, coin string) (coingecko.CryptoData, error) {
+	ticker, err := a.client.GetCoinTickers(ctx, a.config.Key, coin)
 	if err != nil {
 		return coingecko.CryptoData{}, err
 	}
@@ -0,0 +1,31 @@
+package aggregator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/aggregator"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/ArbitrageCoin/crypto-sdk/src/recorder"
+)
+
+func TestCoinGeckoGetCoinsReplay(t *testing.T) {
+	rec, err := recorder.New(filepath.Join("testdata", "coingecko_coins_list.json"), recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coingecko, _ := aggregator.NewCoinGecko(aggregator.Config{Key: "key", HTTP: httpclient.Config{Transport: rec}})
+
+	coins, err := coingecko.GetCoins(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(coins) != 2 {
+		t.Fatalf("unexpected coins: %v", coins)
+	}
+	if bitcoin := coins["bitcoin"]; bitcoin.Symbol != "btc" || bitcoin.Name != "Bitcoin" {
+		t.Fatalf("unexpected bitcoin: %+v", bitcoin)
+	}
+}
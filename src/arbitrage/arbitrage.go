@@ -0,0 +1,124 @@
+package arbitrage
+
+import (
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/shopspring/decimal"
+)
+
+// Fees are the fees charged along an arbitrage, from the purchase to the sale
+type Fees struct {
+	// BuyRate and SellRate are the taker rates of the exchanges, as the FOK and IOC orders take liquidity
+	BuyRate  decimal.Decimal
+	SellRate decimal.Decimal
+	// WithdrawFee and DepositFee are charged in base coin, when sending it from an exchange to the other
+	WithdrawFee decimal.Decimal
+	DepositFee  decimal.Decimal
+}
+
+// Result is the outcome of buying a coin on an exchange and selling it on another
+type Result struct {
+	Quantity decimal.Decimal
+	// Spend is the gross spend and Proceeds the gross proceeds, fees excluded
+	Spend    decimal.Decimal
+	Proceeds decimal.Decimal
+
+	// The fees in quote currency, the ones charged in base coin being valued at the average selling price
+	BuyFee      decimal.Decimal
+	SellFee     decimal.Decimal
+	WithdrawFee decimal.Decimal
+	DepositFee  decimal.Decimal
+
+	// NetProfit is the gross proceeds minus the gross spend and every fee
+	NetProfit decimal.Decimal
+}
+
+// TotalFees returns the sum of the fees, in quote currency
+func (r Result) TotalFees() decimal.Decimal {
+	return r.BuyFee.Add(r.SellFee).Add(r.WithdrawFee).Add(r.DepositFee)
+}
+
+// IsProfitable tells whether a price bought then sold remains profitable once the trading fees are paid, the
+// proceeds having to exceed the cost times minProfitability
+func IsProfitable(askPrice, bidPrice decimal.Decimal, fees Fees, minProfitability decimal.Decimal) bool {
+	cost := askPrice.Mul(decimal.NewFromInt(1).Add(fees.BuyRate))
+	proceeds := bidPrice.Mul(decimal.NewFromInt(1).Sub(fees.SellRate))
+	return proceeds.GreaterThan(cost.Mul(minProfitability))
+}
+
+// Calculate buys the asks of the buying exchange and sells them to the bids of the selling one, level by level
+// while profitable by minProfitability, spending maxSpend at most
+func Calculate(asks, bids coin.OrderBook, fees Fees, minProfitability, maxSpend decimal.Decimal) Result {
+	results := Result{
+		Quantity: decimal.Zero,
+		Spend:    decimal.Zero,
+		Proceeds: decimal.Zero,
+	}
+
+	// The quantities of the asks are consumed along the way, the order book of the caller is left untouched
+	asks.Asks = append([]coin.Offer(nil), asks.Asks...)
+
+	askIndex := 0
+out:
+	for _, bid := range bids.Bids {
+		for askIndex < len(asks.Asks) {
+			ask := asks.Asks[askIndex]
+
+			// If the buyer is offering a price lower than the price we can buy it, taking into account the fees and the minimum profitability, then stop
+			if !IsProfitable(ask.Price, bid.Price, fees, minProfitability) {
+				break out
+			}
+
+			canSpend := maxSpend.Sub(results.Spend)
+
+			// If there are not enough quantity in the current bid for the slot we want to buy
+			if bid.Quantity.LessThan(ask.Quantity) {
+				// We take the quantity of the bid, but the price of the ask, as the bid price is
+				// the price we well it to. The price we pay for 1 of quantity is ask.Price
+				askTotalPrice := ask.Price.Mul(bid.Quantity)
+				// If we cannot buy the whole bid
+				if askTotalPrice.GreaterThan(canSpend) {
+					qty := canSpend.Div(ask.Price)
+					results.Quantity = results.Quantity.Add(qty)
+					results.Spend = results.Spend.Add(ask.Price.Mul(qty))
+					results.Proceeds = results.Proceeds.Add(bid.Price.Mul(qty))
+					break out
+				}
+
+				// If we can buy the whole bid, buy it and check the next one
+				results.Quantity = results.Quantity.Add(bid.Quantity)
+				results.Spend = results.Spend.Add(ask.Price.Mul(bid.Quantity))
+				results.Proceeds = results.Proceeds.Add(bid.Price.Mul(bid.Quantity))
+				asks.Asks[askIndex].Quantity = ask.Quantity.Sub(bid.Quantity)
+				break // Move to another bid
+			}
+
+			askTotalPrice := ask.Price.Mul(ask.Quantity)
+			//If we cannot buy the entire offer, buy a chunk and stop
+			if askTotalPrice.GreaterThanOrEqual(canSpend) {
+				qty := canSpend.Div(ask.Price)
+				results.Spend = results.Spend.Add(qty.Mul(ask.Price))
+				results.Proceeds = results.Proceeds.Add(qty.Mul(bid.Price))
+				results.Quantity = results.Quantity.Add(qty)
+				break out
+			}
+
+			// Otherwise just buy the entire ask and move to another ask
+			results.Quantity = results.Quantity.Add(ask.Quantity)
+			results.Spend = results.Spend.Add(askTotalPrice)
+			results.Proceeds = results.Proceeds.Add(ask.Quantity.Mul(bid.Price))
+			bid.Quantity = bid.Quantity.Sub(ask.Quantity)
+			askIndex++
+		}
+	}
+
+	results.BuyFee = results.Spend.Mul(fees.BuyRate)
+	results.SellFee = results.Proceeds.Mul(fees.SellRate)
+	if results.Quantity.IsPositive() {
+		averageSellingPrice := results.Proceeds.Div(results.Quantity)
+		results.WithdrawFee = fees.WithdrawFee.Mul(averageSellingPrice)
+		results.DepositFee = fees.DepositFee.Mul(averageSellingPrice)
+	}
+	results.NetProfit = results.Proceeds.Sub(results.Spend).Sub(results.TotalFees())
+
+	return results
+}
@@ -0,0 +1,54 @@
+package arbitrage_test
+
+import (
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/arbitrage"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/shopspring/decimal"
+)
+
+func offer(price, quantity string) coin.Offer {
+	return coin.Offer{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+func TestCalculate(t *testing.T) {
+	asks := coin.OrderBook{Asks: []coin.Offer{offer("100", "1"), offer("101", "2")}}
+	bids := coin.OrderBook{Bids: []coin.Offer{offer("105", "2"), offer("100", "5")}}
+	minProfitability := decimal.RequireFromString("1.01")
+
+	// The 2 levels under 105 are bought, the bid at 100 is not profitable anymore
+	result := arbitrage.Calculate(asks, bids, arbitrage.Fees{}, minProfitability, decimal.NewFromInt(1000))
+	if !result.Quantity.Equal(decimal.NewFromInt(2)) || !result.Spend.Equal(decimal.NewFromInt(201)) || !result.Proceeds.Equal(decimal.NewFromInt(210)) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if !result.NetProfit.Equal(decimal.NewFromInt(9)) {
+		t.Fatalf("unexpected net profit: %v", result.NetProfit)
+	}
+	if !asks.Asks[1].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("the order book of the caller should be left untouched: %v", asks)
+	}
+
+	// The withdrawal fee is paid in base coin, valued at the average selling price
+	fees := arbitrage.Fees{
+		BuyRate:     decimal.RequireFromString("0.001"),
+		SellRate:    decimal.RequireFromString("0.001"),
+		WithdrawFee: decimal.RequireFromString("0.01"),
+	}
+	result = arbitrage.Calculate(asks, bids, fees, minProfitability, decimal.NewFromInt(1000))
+	if !result.TotalFees().Equal(decimal.RequireFromString("1.461")) || !result.NetProfit.Equal(decimal.RequireFromString("7.539")) {
+		t.Fatalf("unexpected fees %v and net profit %v", result.TotalFees(), result.NetProfit)
+	}
+
+	// The spend is capped
+	result = arbitrage.Calculate(asks, bids, arbitrage.Fees{}, minProfitability, decimal.NewFromInt(50))
+	if !result.Quantity.Equal(decimal.RequireFromString("0.5")) || !result.Spend.Equal(decimal.NewFromInt(50)) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	// A margin above the spread leaves nothing to buy
+	result = arbitrage.Calculate(asks, bids, arbitrage.Fees{}, decimal.RequireFromString("1.1"), decimal.NewFromInt(1000))
+	if !result.Quantity.IsZero() || !result.NetProfit.IsZero() {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
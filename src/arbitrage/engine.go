@@ -0,0 +1,367 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// DefaultMinProfitability is the margin expected on top of the trading fees, 10%
+	DefaultMinProfitability = decimal.RequireFromString("1.1")
+	// DefaultMaxSpend is the most quote coin spent on an arbitrage
+	DefaultMaxSpend = decimal.NewFromInt(1000)
+)
+
+// maxOrderBookAge is the age above which a streamed order book is fetched again through the REST API
+const maxOrderBookAge = 5 * time.Second
+
+// Config configures the Engine, its zero values being replaced by the defaults
+type Config struct {
+	// MinProfitability is the ratio the proceeds of each level bought then sold must exceed its cost by, trading
+	// fees included, e.g. 1.1 for a margin of 10%
+	MinProfitability decimal.Decimal
+	// MaxSpend is the most quote coin spent on an arbitrage
+	MaxSpend decimal.Decimal
+	// Quotes restricts the arbitrages to the tickers quoted in these coins, e.g. USDT, every ticker being
+	// analyzed when empty
+	Quotes []string
+
+	// Brokers are the accounts of each exchange, keyed by the name of the exchange, the first one giving the
+	// market data. They cannot be set from the JSON.
+	Brokers map[string][]broker.IBroker `json:"-"`
+	// Streams are the order books streamed for some of the exchanges, used while they are fresh enough
+	Streams map[string]*broker.MarketDataStream `json:"-"`
+}
+
+// Opportunity is a coin to buy on an exchange, transfer, then sell on another exchange
+type Opportunity struct {
+	Ticker coin.TickerPair
+
+	// BuyAccount is the account of BuyExchange funding the purchase, BuyTicker being the ticker on BuyExchange
+	BuyExchange string
+	BuyAccount  broker.IBroker
+	BuyTicker   database.SelectExchangeTickersRow
+	// SellAccount is the account of SellExchange receiving the deposit and selling it
+	SellExchange string
+	SellAccount  broker.IBroker
+	SellTicker   database.SelectExchangeTickersRow
+
+	// Network is the canonical code of the network the coin is transferred through
+	Network string
+	Result  Result
+}
+
+// Engine looks for the arbitrages between the exchanges of its brokers
+type Engine struct {
+	config Config
+	// exchanges are the names of the exchanges, sorted to analyze them in the same order at each scan
+	exchanges []string
+
+	// ctx bounds the order books streamed for the engine, subscribed is the set of the tickers already
+	// subscribed to, keyed by exchange then symbol
+	ctx        context.Context
+	mu         sync.Mutex
+	subscribed map[string]map[string]bool
+}
+
+// NewEngine returns an engine whose order book streams last until ctx is done, the scans being bounded by their own
+// contexts
+func NewEngine(ctx context.Context, config Config) (*Engine, error) {
+	if config.MinProfitability.IsZero() {
+		config.MinProfitability = DefaultMinProfitability
+	}
+	if config.MaxSpend.IsZero() {
+		config.MaxSpend = DefaultMaxSpend
+	}
+	if config.MinProfitability.LessThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("the minimum profitability cannot be under 1: %v", config.MinProfitability)
+	}
+	if config.MaxSpend.IsNegative() {
+		return nil, fmt.Errorf("the maximum spend cannot be negative: %v", config.MaxSpend)
+	}
+
+	e := &Engine{config: config, ctx: ctx, subscribed: make(map[string]map[string]bool)}
+	for exchange, accounts := range config.Brokers {
+		if len(accounts) == 0 {
+			return nil, fmt.Errorf("%v has no account", exchange)
+		}
+		e.exchanges = append(e.exchanges, exchange)
+	}
+	if len(e.exchanges) < 2 {
+		return nil, fmt.Errorf("an arbitrage needs at least two exchanges, got %v", len(e.exchanges))
+	}
+	sort.Strings(e.exchanges)
+	return e, nil
+}
+
+// market returns the broker giving the market data of exchange
+func (e *Engine) market(exchange string) broker.IBroker {
+	return e.config.Brokers[exchange][0]
+}
+
+// scan holds the data fetched once per scan, shared by the analyses of the tickers
+type scan struct {
+	networks map[string][]coin.Network
+	balances map[string]map[coin.CoinBaseStr]coin.Balance
+
+	mu   sync.Mutex
+	errs []error
+}
+
+func (s *scan) report(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+// Scan looks for the opportunities once, found being called with each one of them from the goroutine of Scan.
+// The exchanges, accounts and tickers whose data cannot be fetched are skipped, their errors being returned
+// joined once the scan is over.
+func (e *Engine) Scan(ctx context.Context, found func(Opportunity)) error {
+	s := &scan{
+		networks: make(map[string][]coin.Network),
+		balances: make(map[string]map[coin.CoinBaseStr]coin.Balance),
+	}
+	tickers := e.tickers(ctx, s)
+
+	// The networks are fetched once per exchange for all the tickers, an exchange whose networks are unknown
+	// cannot be part of an arbitrage
+	for _, exchange := range e.exchanges {
+		networks, err := e.market(exchange).GetNetworks(ctx)
+		if err != nil {
+			s.report(fmt.Errorf("unable to get the networks of %v: %w", exchange, err))
+			continue
+		}
+		s.networks[exchange] = networks
+	}
+
+	// The balances are fetched once per account, an account whose balance is unknown cannot fund a purchase
+	for _, exchange := range e.exchanges {
+		for _, account := range e.config.Brokers[exchange] {
+			balance, err := account.GetBalance(ctx)
+			if err != nil {
+				s.report(fmt.Errorf("unable to get the balance of %v: %w", account.GetBrokerName(), err))
+				continue
+			}
+			s.balances[account.GetBrokerName()] = balance
+		}
+	}
+
+	ch := make(chan Opportunity)
+	go func() {
+		var wg sync.WaitGroup
+		for tickerPair, exchanges := range tickers {
+			if len(exchanges) < 2 {
+				continue
+			}
+
+			wg.Add(1)
+			go func(tickerPair coin.TickerPair, exchanges map[string]broker.CoinAllInfo) {
+				defer wg.Done()
+				if opportunity, ok := e.analyze(ctx, s, tickerPair, exchanges); ok {
+					ch <- opportunity
+				}
+			}(tickerPair, exchanges)
+		}
+
+		wg.Wait()
+		close(ch)
+	}()
+
+	for opportunity := range ch {
+		found(opportunity)
+	}
+	return errors.Join(s.errs...)
+}
+
+// tickers returns the best offers of the tickers quoted in the allowed quotes, grouped by pair then by exchange
+func (e *Engine) tickers(ctx context.Context, s *scan) map[coin.TickerPair]map[string]broker.CoinAllInfo {
+	tickers := make(map[coin.TickerPair]map[string]broker.CoinAllInfo)
+	for _, exchange := range e.exchanges {
+		infos, err := e.market(exchange).GetTickersInformation(ctx)
+		if err != nil {
+			s.report(fmt.Errorf("unable to get the tickers of %v: %w", exchange, err))
+			continue
+		}
+
+		for tickerPair, info := range infos {
+			if !e.allowedQuote(info.ExchangeTicker.Quote) {
+				continue
+			}
+			if _, ok := tickers[tickerPair]; !ok {
+				tickers[tickerPair] = make(map[string]broker.CoinAllInfo)
+			}
+			tickers[tickerPair][exchange] = info
+		}
+	}
+	return tickers
+}
+
+func (e *Engine) allowedQuote(quote string) bool {
+	if len(e.config.Quotes) == 0 {
+		return true
+	}
+	for _, allowed := range e.config.Quotes {
+		if strings.EqualFold(allowed, quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyze looks for an exchange to buy tickerPair and another to sell it. The coin must be transferable from the
+// former to the latter, and the arbitrage profitable once every fee is paid.
+func (e *Engine) analyze(ctx context.Context, s *scan, tickerPair coin.TickerPair, exchanges map[string]broker.CoinAllInfo) (Opportunity, bool) {
+	for _, buyExchange := range e.exchanges {
+		buyValues, ok := exchanges[buyExchange]
+		if !ok {
+			continue
+		}
+		for _, sellExchange := range e.exchanges {
+			sellValues, ok := exchanges[sellExchange]
+			if !ok || buyExchange == sellExchange {
+				continue
+			}
+
+			buyTicker, sellTicker := buyValues.ExchangeTicker, sellValues.ExchangeTicker
+			routes := broker.CommonNetworks(buyTicker.Base, s.networks[buyExchange], s.networks[sellExchange])
+			if len(routes) == 0 {
+				continue
+			}
+
+			// The best offers are screened with the fees of each pair of accounts, as the accounts of an exchange
+			// may be charged different rates
+			screened := false
+			for _, buyAccount := range e.config.Brokers[buyExchange] {
+				for _, sellAccount := range e.config.Brokers[sellExchange] {
+					fees := accountFees(buyAccount, sellAccount, buyTicker, sellTicker, routes[0].Withdrawal.WithdrawFee)
+					if IsProfitable(buyValues.Values.LowestAsk, sellValues.Values.HighestBid, fees, decimal.NewFromInt(1)) {
+						screened = true
+					}
+				}
+			}
+			if !screened {
+				continue
+			}
+
+			asks, err := e.orderBook(ctx, buyExchange, buyTicker)
+			if err != nil {
+				s.report(fmt.Errorf("unable to get the order book of %v on %v: %w", buyTicker.Base, buyExchange, err))
+				continue
+			}
+			bids, err := e.orderBook(ctx, sellExchange, sellTicker)
+			if err != nil {
+				s.report(fmt.Errorf("unable to get the order book of %v on %v: %w", sellTicker.Base, sellExchange, err))
+				continue
+			}
+
+			opportunity, ok := e.pickAccounts(ctx, s, buyExchange, sellExchange, buyTicker, sellTicker, asks, bids, routes[0].Withdrawal)
+			if !ok {
+				continue
+			}
+			opportunity.Ticker = tickerPair
+			opportunity.Network = routes[0].Network
+			return opportunity, true
+		}
+	}
+	return Opportunity{}, false
+}
+
+// orderBook returns the streamed order book of ticker when it is fresh enough, otherwise asks the broker.
+// The ticker is subscribed on its first use so that the next analyses can rely on the stream.
+func (e *Engine) orderBook(ctx context.Context, exchange string, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
+	if stream, ok := e.config.Streams[exchange]; ok {
+		e.subscribe(stream, exchange, ticker)
+		orderbook, updatedAt, ok := stream.OrderBook(ticker)
+		if ok && time.Since(updatedAt) < maxOrderBookAge {
+			return orderbook, nil
+		}
+	}
+
+	return e.market(exchange).GetOrderBooks(ctx, ticker)
+}
+
+// subscribe subscribes stream to ticker for the lifetime of the engine, once
+func (e *Engine) subscribe(stream *broker.MarketDataStream, exchange string, ticker database.SelectExchangeTickersRow) {
+	symbol := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.subscribed[exchange][symbol] {
+		return
+	}
+	if e.subscribed[exchange] == nil {
+		e.subscribed[exchange] = make(map[string]bool)
+	}
+	e.subscribed[exchange][symbol] = true
+	stream.Subscribe(e.ctx, ticker)
+}
+
+// accountFees returns the fees of an arbitrage bought by buyAccount and sold by sellAccount, the coin being
+// withdrawn for withdrawFee
+func accountFees(buyAccount, sellAccount broker.IBroker, buyTicker, sellTicker database.SelectExchangeTickersRow, withdrawFee decimal.Decimal) Fees {
+	return Fees{
+		BuyRate:     buyAccount.GetFees().Rate(buyTicker.Base, buyTicker.Quote).Taker,
+		SellRate:    sellAccount.GetFees().Rate(sellTicker.Base, sellTicker.Quote).Taker,
+		WithdrawFee: withdrawFee,
+		DepositFee:  sellAccount.GetFees().DepositFee(sellTicker.Base),
+	}
+}
+
+// pickAccounts returns the arbitrage traded by the first accounts able to, in the order of Brokers: the account
+// of buyExchange funds the purchase, spending MaxSpend at most but never more than its free quote coin, and the
+// one of sellExchange receives the deposit through withdrawal. The result is the one of the arbitrage with the
+// fees of these accounts, ok being false when no pair of accounts can trade at least the minimum withdrawal
+// profitably.
+func (e *Engine) pickAccounts(ctx context.Context, s *scan, buyExchange, sellExchange string, buyTicker, sellTicker database.SelectExchangeTickersRow, asks, bids coin.OrderBook, withdrawal coin.Network) (Opportunity, bool) {
+	var buyers []broker.IBroker
+	for _, account := range e.config.Brokers[buyExchange] {
+		if err := account.CanBuyAndWithdraw(ctx, buyTicker); err == nil {
+			buyers = append(buyers, account)
+		}
+	}
+
+	for _, sellAccount := range e.config.Brokers[sellExchange] {
+		if err := sellAccount.CanDepositAndSell(ctx, sellTicker); err != nil {
+			continue
+		}
+		for _, buyAccount := range buyers {
+			fees := accountFees(buyAccount, sellAccount, buyTicker, sellTicker, withdrawal.WithdrawFee)
+			maxSpend := decimal.Min(e.config.MaxSpend, freeBalance(s.balances[buyAccount.GetBrokerName()], buyTicker.Quote))
+
+			result := Calculate(asks, bids, fees, e.config.MinProfitability, maxSpend)
+			if result.NetProfit.IsPositive() && result.Quantity.GreaterThanOrEqual(withdrawal.WithdrawMin) {
+				return Opportunity{
+					BuyExchange:  buyExchange,
+					BuyAccount:   buyAccount,
+					BuyTicker:    buyTicker,
+					SellExchange: sellExchange,
+					SellAccount:  sellAccount,
+					SellTicker:   sellTicker,
+					Result:       result,
+				}, true
+			}
+		}
+	}
+	return Opportunity{}, false
+}
+
+// freeBalance returns the quantity of asset in balance, whatever the case the exchange names it with
+func freeBalance(balance map[coin.CoinBaseStr]coin.Balance, asset string) decimal.Decimal {
+	for name, b := range balance {
+		if strings.EqualFold(name, asset) {
+			return b.Quantity
+		}
+	}
+	return decimal.Zero
+}
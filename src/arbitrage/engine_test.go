@@ -0,0 +1,131 @@
+package arbitrage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/arbitrage"
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// fakeBroker serves fixed market data, balances and fees
+type fakeBroker struct {
+	broker.IBroker
+	name       string
+	tickers    map[coin.TickerPair]broker.CoinAllInfo
+	tickersErr error
+	orderbook  coin.OrderBook
+	balance    map[coin.CoinBaseStr]coin.Balance
+	fees       broker.FeeSchedule
+}
+
+func (b fakeBroker) GetBrokerName() string { return b.name }
+
+func (b fakeBroker) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]broker.CoinAllInfo, error) {
+	return b.tickers, b.tickersErr
+}
+
+func (b fakeBroker) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
+	return b.orderbook, nil
+}
+
+func (b fakeBroker) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	return []coin.Network{{
+		Coin:             "BTC",
+		Code:             "BTC",
+		DepositPossible:  true,
+		WithdrawPossible: true,
+		WithdrawMin:      decimal.RequireFromString("0.001"),
+	}}, nil
+}
+
+func (b fakeBroker) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
+	return b.balance, nil
+}
+
+func (b fakeBroker) GetFees() broker.FeeSchedule { return b.fees }
+
+func (b fakeBroker) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	return nil
+}
+
+func (b fakeBroker) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	return nil
+}
+
+func TestEngine(t *testing.T) {
+	btcUSDT := coin.TickerPair{Base: uuid.New(), Quote: uuid.New()}
+	btcETH := coin.TickerPair{Base: btcUSDT.Base, Quote: uuid.New()}
+	tickers := func(bid, ask int64) map[coin.TickerPair]broker.CoinAllInfo {
+		values := coin.TickerValues{HighestBid: decimal.NewFromInt(bid), LowestAsk: decimal.NewFromInt(ask)}
+		return map[coin.TickerPair]broker.CoinAllInfo{
+			btcUSDT: {Values: values, ExchangeTicker: database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}},
+			btcETH:  {Values: values, ExchangeTicker: database.SelectExchangeTickersRow{Base: "BTC", Quote: "ETH"}},
+		}
+	}
+	usdt := func(quantity int64) map[coin.CoinBaseStr]coin.Balance {
+		return map[coin.CoinBaseStr]coin.Balance{"USDT": {Quantity: decimal.NewFromInt(quantity)}}
+	}
+
+	// BTC is bought on Cheap by the account without fees, with the 50 USDT it holds, and sold on Dear. The fees
+	// of the first account of Cheap would not leave any profit.
+	cheap := fakeBroker{
+		name:      "Cheap-main",
+		tickers:   tickers(99, 100),
+		orderbook: coin.OrderBook{Asks: []coin.Offer{offer("100", "1")}},
+		balance:   usdt(500),
+		fees:      broker.FeeSchedule{Taker: decimal.RequireFromString("0.2")},
+	}
+	dear := fakeBroker{
+		name:      "Dear",
+		tickers:   tickers(111, 112),
+		orderbook: coin.OrderBook{Bids: []coin.Offer{offer("111", "2")}},
+	}
+	engine, err := arbitrage.NewEngine(context.Background(), arbitrage.Config{
+		Quotes: []string{"usdt"},
+		Brokers: map[string][]broker.IBroker{
+			"Cheap":  {cheap, fakeBroker{name: "Cheap-sub", balance: usdt(50)}},
+			"Dear":   {dear},
+			"Broken": {fakeBroker{name: "Broken", tickersErr: errors.New("unreachable")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var opportunities []arbitrage.Opportunity
+	err = engine.Scan(context.Background(), func(o arbitrage.Opportunity) {
+		opportunities = append(opportunities, o)
+	})
+	if err == nil {
+		t.Fatalf("the failure of Broken should be reported")
+	}
+	if len(opportunities) != 1 {
+		t.Fatalf("expected a single opportunity, the ETH quote being excluded: %+v", opportunities)
+	}
+	o := opportunities[0]
+	if o.Ticker != btcUSDT || o.BuyExchange != "Cheap" || o.SellExchange != "Dear" || o.Network != "BTC" {
+		t.Fatalf("unexpected opportunity: %+v", o)
+	}
+	if o.BuyAccount.GetBrokerName() != "Cheap-sub" || o.SellAccount.GetBrokerName() != "Dear" {
+		t.Fatalf("unexpected accounts: %v and %v", o.BuyAccount.GetBrokerName(), o.SellAccount.GetBrokerName())
+	}
+	if !o.Result.Quantity.Equal(decimal.RequireFromString("0.5")) || !o.Result.Spend.Equal(decimal.NewFromInt(50)) || !o.Result.NetProfit.Equal(decimal.RequireFromString("5.5")) {
+		t.Fatalf("unexpected result: %+v", o.Result)
+	}
+
+	for _, config := range []arbitrage.Config{
+		{Brokers: map[string][]broker.IBroker{"Cheap": {cheap}}},
+		{Brokers: map[string][]broker.IBroker{"Cheap": {cheap}, "Dear": {}}},
+		{MinProfitability: decimal.RequireFromString("0.9"), Brokers: map[string][]broker.IBroker{"Cheap": {cheap}, "Dear": {dear}}},
+	} {
+		if _, err := arbitrage.NewEngine(context.Background(), config); err == nil {
+			t.Fatalf("the engine should not be created: %+v", config)
+		}
+	}
+}
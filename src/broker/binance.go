@@ -2,36 +2,104 @@ package broker
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
 	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/binance/binance-connector-go/handlers"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	binanceBaseURL = "https://api.binance.com"
+	// binanceHistoryLimit is the largest page of the withdrawal history
+	binanceHistoryLimit = 1000
+)
+
 type Binance struct {
-	config Config
+	config     Config
+	httpClient *http.Client
 
 	coins           CoinsMap
 	exchangeCoins   ExchangeCoinsMap
 	exchangeTickers ExchangeTickersMap
+
+	tickersStatus  map[string]coin.TickerStatus
+	tickersFilters map[string]coin.TickerFilters
+	coinsNetwork   map[string]*binance_connector.CoinInfo
+	accountStatus  AccountStatus
+	clock          *Clock
+}
+
+func init() {
+	DefaultRegistry.Register("Binance", NewBinance)
 }
 
 func NewBinance(config Config) (IBroker, error) {
-	return &Binance{
-		config: config,
-	}, nil
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config.HTTP = config.httpConfig(binanceRateLimits)
+
+	b := &Binance{
+		config:         config,
+		httpClient:     config.HTTP.HTTPClient(),
+		tickersStatus:  make(map[string]coin.TickerStatus),
+		tickersFilters: make(map[string]coin.TickerFilters),
+		coinsNetwork:   make(map[string]*binance_connector.CoinInfo),
+		accountStatus:  NewAccountStatus(false),
+	}
+	b.clock = newClock(func(ctx context.Context) (time.Time, error) { return b.serverTime(ctx) })
+	return b, nil
 }
 
 func (b Binance) GetBrokerName() string { return b.config.InternalName }
 
+// Format prints the broker as its redacted configuration, its clients holding the key being left out
+func (b Binance) Format(f fmt.State, verb rune) { formatBroker(f, verb, "Binance", b.config) }
+
+// newClient returns a connector targeting the endpoint set in b.config.HTTP
+func (b Binance) newClient(key, secret string) *binance_connector.Client {
+	var client *binance_connector.Client
+	if b.config.HTTP.BaseURL != "" {
+		client = binance_connector.NewClient(key, secret, b.config.HTTP.BaseURL)
+	} else {
+		client = binance_connector.NewClient(key, secret)
+	}
+	client.HTTPClient = b.httpClient
+	// The connector timestamps the signed requests with the local time minus TimeOffset
+	client.TimeOffset = -b.clock.Offset().Milliseconds()
+	return client
+}
+
+func (b Binance) Clock() *Clock { return b.clock }
+
+// serverTime returns the time of the exchange, for Clock
+func (b Binance) serverTime(ctx context.Context) (time.Time, error) {
+	resp, err := b.newClient("", "").NewServerTimeService().Do(ctx)
+	if err != nil {
+		return time.Time{}, binanceError(err)
+	}
+	return millisToTime(int64(resp.ServerTime)), nil
+}
+
 func (b Binance) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
-	client := binance_connector.NewClient("", "")
+	client := b.newClient("", "")
 	tickers, err := client.NewTickerBookTickerService().Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, binanceError(err)
 	}
 
 	tickersInfo := make(map[coin.TickerPair]CoinAllInfo)
@@ -89,55 +157,37 @@ func (b Binance) GetOrderBooks(ctx context.Context, ticker database.SelectExchan
 	base := strings.ToUpper(ticker.Base)
 	quote := strings.ToUpper(ticker.Quote)
 
-	client := binance_connector.NewClient(b.config.Key, b.config.Secret)
-	client.TimeOffset = 1000
+	client := b.newClient(b.config.Key, b.config.Secret)
 	orders, err := client.
 		NewOrderBookService().
 		Symbol(base + quote).
 		Do(ctx)
 
+	if err != nil {
+		return coin.OrderBook{}, binanceError(err)
+	}
+
+	bids, err := binanceOffers(orders.Bids)
+	if err != nil {
+		return coin.OrderBook{}, err
+	}
+	asks, err := binanceOffers(orders.Asks)
 	if err != nil {
 		return coin.OrderBook{}, err
 	}
 
 	orderbook := coin.OrderBook{
-		Bids: make([]coin.Offer, len(orders.Bids)),
-		Asks: make([]coin.Offer, len(orders.Asks)),
+		Bids: make([]coin.Offer, 0, len(bids)),
+		Asks: make([]coin.Offer, 0, len(asks)),
 	}
-
-	for i, bid := range orders.Bids {
-		price, err := decimal.NewFromString(bid[0].String())
-		if err != nil {
-			return orderbook, err
-		}
-		quantity, err := decimal.NewFromString(bid[1].String())
-		if err != nil {
-			return orderbook, err
-		}
-		if quantity.Equal(decimal.Zero) {
-			break
-		}
-		orderbook.Bids[i] = coin.Offer{
-			Price:    price,
-			Quantity: quantity,
+	for _, bid := range bids {
+		if bid.Quantity.GreaterThan(decimal.Zero) {
+			orderbook.Bids = append(orderbook.Bids, bid)
 		}
 	}
-
-	for i, ask := range orders.Asks {
-		price, err := decimal.NewFromString(ask[0].String())
-		if err != nil {
-			return orderbook, err
-		}
-		quantity, err := decimal.NewFromString(ask[1].String())
-		if err != nil {
-			return orderbook, err
-		}
-		if !quantity.GreaterThan(decimal.Zero) {
-			break
-		}
-		orderbook.Asks[i] = coin.Offer{
-			Price:    price,
-			Quantity: quantity,
+	for _, ask := range asks {
+		if ask.Quantity.GreaterThan(decimal.Zero) {
+			orderbook.Asks = append(orderbook.Asks, ask)
 		}
 	}
 
@@ -148,11 +198,10 @@ func (b Binance) GetOrderBooks(ctx context.Context, ticker database.SelectExchan
 }
 
 func (b Binance) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
-	client := binance_connector.NewClient(b.config.Key, b.config.Secret)
-	client.TimeOffset = 1000
+	client := b.newClient(b.config.Key, b.config.Secret)
 	coins, err := client.NewGetAllCoinsInfoService().Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, binanceError(err)
 	}
 
 	balance := make(map[coin.CoinBaseStr]coin.Balance)
@@ -176,6 +225,53 @@ func (b Binance) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Bala
 	return balance, nil
 }
 
+func (b Binance) GetFees() FeeSchedule {
+	return b.config.Fees
+}
+
+func (b Binance) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	coinsInfo, err := b.newClient(b.config.Key, b.config.Secret).NewGetAllCoinsInfoService().Do(ctx)
+	if err != nil {
+		return nil, binanceError(err)
+	}
+
+	networks := []coin.Network{}
+	for _, c := range coinsInfo {
+		for _, network := range c.NetworkList {
+			withdrawFee, err := decimalOrZero(network.WithdrawFee)
+			if err != nil {
+				return nil, fmt.Errorf("invalid withdraw fee of %v on %v: %v", c.Coin, network.Network, err)
+			}
+			withdrawMin, err := decimalOrZero(network.WithdrawMin)
+			if err != nil {
+				return nil, fmt.Errorf("invalid withdraw minimum of %v on %v: %v", c.Coin, network.Network, err)
+			}
+			networks = append(networks, coin.Network{
+				Coin:             strings.ToUpper(c.Coin),
+				Code:             network.Network,
+				Name:             network.Name,
+				DepositPossible:  network.DepositEnable,
+				WithdrawPossible: network.WithdrawEnable,
+				WithdrawFee:      withdrawFee,
+				WithdrawMin:      withdrawMin,
+				MinConfirm:       network.MinConfirm,
+				AddressRegex:     network.AddressRegex,
+				MemoRegex:        network.MemoRegex,
+			})
+		}
+	}
+
+	return networks, nil
+}
+
+// decimalOrZero parses the amounts Binance sends as strings, an empty one being zero
+func decimalOrZero(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
+
 func (b *Binance) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
 	b.coins = coins
 	b.exchangeCoins = exchangeCoins
@@ -183,70 +279,520 @@ func (b *Binance) RefreshCoinsInformation(coins CoinsMap, exchangeCoins Exchange
 }
 
 func (b *Binance) RefreshExchangeInformation(ctx context.Context) error {
+	if err := b.clock.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to synchronize the clock: %w", err)
+	}
+
+	client := b.newClient(b.config.Key, b.config.Secret)
+
+	respExchange, err := client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return binanceError(err)
+	}
+
+	for _, ticker := range respExchange.Symbols {
+		isLimitOrderAllowed := false
+		for _, orderAllowed := range ticker.OrderTypes {
+			if strings.ToUpper(orderAllowed) == "LIMIT" {
+				isLimitOrderAllowed = true
+				break
+			}
+		}
+
+		isTrading := strings.ToUpper(ticker.Status) == "TRADING"
+		b.tickersStatus[ticker.Symbol] = coin.TickerStatus{
+			IsEnabled:            isTrading,
+			IsLimitOrderAllowed:  isLimitOrderAllowed,
+			IsSpotTradingAllowed: ticker.IsSpotTradingAllowed,
+			IsBuyable:            isTrading,
+			IsSellable:           isTrading,
+		}
+
+		filters, err := binanceFilters(ticker.Filters)
+		if err != nil {
+			return fmt.Errorf("%v: %v", ticker.Symbol, err)
+		}
+		b.tickersFilters[ticker.Symbol] = filters
+	}
+
+	// The networks are kept for CanBuyAndWithdraw and CanDepositAndSell, called for every opportunity, as
+	// capital/config/getall weighs 10
+	coinsNetwork, err := client.NewGetAllCoinsInfoService().Do(ctx)
+	if err != nil {
+		return binanceError(err)
+	}
+	for _, c := range coinsNetwork {
+		b.coinsNetwork[strings.ToUpper(c.Coin)] = c
+	}
+
+	respAccount, err := client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return binanceError(err)
+	}
+
+	b.accountStatus.CanDeposit = respAccount.CanDeposit
+	b.accountStatus.CanTrade = respAccount.CanTrade
+	b.accountStatus.CanWithdraw = respAccount.CanWithdraw
+	b.accountStatus.CanUseSpot = false
+	for _, permission := range respAccount.Permissions {
+		if strings.ToUpper(permission) == "SPOT" {
+			b.accountStatus.CanUseSpot = true
+			break
+		}
+	}
+	if strings.ToUpper(respAccount.AccountType) != "SPOT" {
+		b.accountStatus.CanUseSpot = false
+	}
+
 	return nil
 }
 
-func (b *Binance) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error {
+// binanceFilters reads the PRICE_FILTER, LOT_SIZE and (MIN_)NOTIONAL filters of a symbol
+func binanceFilters(symbolFilters []*binance_connector.SymbolFilter) (coin.TickerFilters, error) {
+	var filters coin.TickerFilters
+
+	for _, filter := range symbolFilters {
+		var err error
+		switch filter.FilterType {
+		case "PRICE_FILTER":
+			err = parseFilter(&filters.TickSize, filter.TickSize)
+		case "LOT_SIZE":
+			err = errors.Join(
+				parseFilter(&filters.StepSize, filter.StepSize),
+				parseFilter(&filters.MinQuantity, filter.MinQty),
+				parseFilter(&filters.MaxQuantity, filter.MaxQty),
+			)
+		case "MIN_NOTIONAL", "NOTIONAL":
+			err = parseFilter(&filters.MinNotional, filter.MinNotional)
+		}
+		if err != nil {
+			return coin.TickerFilters{}, fmt.Errorf("invalid %v filter: %v", filter.FilterType, err)
+		}
+	}
+
+	return filters, nil
+}
+
+func (b Binance) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return tickerFilters(b.tickersFilters, strings.ToUpper(ticker.Base)+strings.ToUpper(ticker.Quote))
+}
+
+func (b *Binance) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, "BUY", "FOK", maxPrice, quoteQuantity)
+}
+
+func (b *Binance) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, "SELL", "IOC", minPrice, quoteQuantity)
+}
+
+// placeOrder sets a FOK or IOC order and builds its result from the fills of the FULL response
+func (b *Binance) placeOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, side, timeInForce string, price, quoteQuantity decimal.Decimal) (OrderResult, error) {
 	base := strings.ToUpper(ticker.Base)
 	quote := strings.ToUpper(ticker.Quote)
 	tickerStr := base + quote
 
-	client := binance_connector.NewClient(b.config.Key, b.config.Secret)
+	filters, err := tickerFilters(b.tickersFilters, tickerStr)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	price, quantity, err := normalizeOrder(filters, side == "BUY", price, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("%v: %w", tickerStr, err)
+	}
 
-	quoteQuantityReal := quoteQuantity.Div(maxPrice).InexactFloat64()
+	params := url.Values{}
+	params.Set("symbol", tickerStr)
+	params.Set("side", side)
+	params.Set("type", "LIMIT")
+	params.Set("timeInForce", timeInForce)
+	params.Set("price", price.String())
+	params.Set("quantity", quantity.String())
+	params.Set("newClientOrderId", newClientOrderID())
+	params.Set("newOrderRespType", "FULL")
+
+	newOrderTyped := new(binance_connector.CreateOrderResponseFULL)
+	if err := b.signedPost(ctx, "/api/v3/order", params, newOrderTyped); err != nil {
+		return OrderResult{}, binanceError(err)
+	}
 
-	newOrder, err := client.NewCreateOrderService().Symbol(tickerStr).
-		Side("BUY").Type("LIMIT").TimeInForce("IOC").
-		Price(maxPrice.InexactFloat64()).Quantity(quoteQuantityReal).
-		Do(ctx)
+	result := OrderResult{
+		OrderID:       strconv.FormatInt(newOrderTyped.OrderId, 10),
+		ClientOrderID: newOrderTyped.ClientOrderId,
+		Status:        toOrderStatus(newOrderTyped.Status),
+		CreatedAt:     millisToTime(int64(newOrderTyped.TransactTime)),
+		UpdatedAt:     millisToTime(int64(newOrderTyped.TransactTime)),
+	}
+
+	executedQty, err := decimal.NewFromString(newOrderTyped.ExecutedQty)
 	if err != nil {
-		return err
+		return result, fmt.Errorf("invalid executed quantity: %v", err)
 	}
+	executedQuote, err := decimal.NewFromString(newOrderTyped.CumulativeQuoteQty)
+	if err != nil {
+		return result, fmt.Errorf("invalid executed quote quantity: %v", err)
+	}
+	result.setFilled(executedQty, executedQuote)
 
-	newOrderTyped, ok := newOrder.(*binance_connector.CreateOrderResponseFULL)
-	if !ok {
-		return fmt.Errorf("newOrderTyped is not a binance_connector.CreateOrderResponseFULL")
+	for _, fill := range newOrderTyped.Fills {
+		commission, err := decimal.NewFromString(fill.Commission)
+		if err != nil {
+			return result, fmt.Errorf("invalid commission: %v", err)
+		}
+		result.addFee(commission, fill.CommissionAsset)
 	}
 
-	if strings.ToUpper(newOrderTyped.Status) != "FILLED" {
-		return fmt.Errorf("the buy order has not been filled")
+	if !result.IsFilled() {
+		return result, fmt.Errorf("the %v order has not been filled", strings.ToLower(side))
 	}
+	return result, nil
+}
 
-	return nil
+func (b *Binance) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("invalid order ID %v: %v", orderID, err)
+	}
+
+	client := b.newClient(b.config.Key, b.config.Secret)
+	order, err := client.NewGetOrderService().Symbol(symbol).OrderId(id).Do(ctx)
+	if err != nil {
+		return OrderResult{}, binanceError(err)
+	}
+
+	return b.orderResult(ctx, client, symbol, order.OrderId, order.ClientOrderId, order.Status, order.ExecutedQty, order.Time, order.UpdateTime)
 }
 
-func (b *Binance) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error {
-	base := strings.ToUpper(ticker.Base)
-	quote := strings.ToUpper(ticker.Quote)
-	tickerStr := base + quote
+func (b *Binance) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %v: %v", orderID, err)
+	}
 
-	client := binance_connector.NewClient(b.config.Key, b.config.Secret)
+	_, err = b.newClient(b.config.Key, b.config.Secret).NewCancelOrderService().Symbol(symbol).OrderId(id).Do(ctx)
+	return binanceError(err)
+}
 
-	quoteQuantityReal := quoteQuantity.Div(minPrice).InexactFloat64()
+func (b *Binance) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	client := b.newClient(b.config.Key, b.config.Secret)
 
-	newOrder, err := client.NewCreateOrderService().Symbol(tickerStr).
-		Side("SELL").Type("LIMIT").TimeInForce("IOC").
-		Price(minPrice.InexactFloat64()).Quantity(quoteQuantityReal).
-		Do(ctx)
+	// Binance fails when there is no order to cancel
+	openOrders, err := client.NewGetOpenOrdersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return binanceError(err)
+	}
+	if len(openOrders) == 0 {
+		return nil
+	}
+
+	_, err = client.NewCancelOpenOrdersService().Symbol(symbol).Do(ctx)
+	return binanceError(err)
+}
+
+func (b *Binance) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	client := b.newClient(b.config.Key, b.config.Secret)
+
+	openOrders, err := client.NewGetOpenOrdersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, binanceError(err)
+	}
+
+	results := make([]OrderResult, 0, len(openOrders))
+	for _, order := range openOrders {
+		result, err := b.orderResult(ctx, client, symbol, order.OrderId, order.ClientOrderId, order.Status, order.ExecutedQty, order.Time, order.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// orderResult converts an order queried from the API. The connector expects cumulativeQuoteQty whereas
+// Binance sends cummulativeQuoteQty, so the quote quantity and the fees are taken from the trades.
+func (b *Binance) orderResult(ctx context.Context, client *binance_connector.Client, symbol string, orderID int64, clientOrderID, status, executedQty string, createdAt, updatedAt uint64) (OrderResult, error) {
+	result := OrderResult{
+		OrderID:       strconv.FormatInt(orderID, 10),
+		ClientOrderID: clientOrderID,
+		Status:        toOrderStatus(status),
+		CreatedAt:     millisToTime(int64(createdAt)),
+		UpdatedAt:     millisToTime(int64(updatedAt)),
+	}
+
+	executed, err := decimal.NewFromString(executedQty)
+	if err != nil {
+		return result, fmt.Errorf("invalid executed quantity: %v", err)
+	}
+	if !executed.GreaterThan(decimal.Zero) {
+		result.setFilled(decimal.Zero, decimal.Zero)
+		return result, nil
+	}
+
+	trades, err := client.NewGetMyTradesService().Symbol(symbol).OrderId(orderID).Do(ctx)
+	if err != nil {
+		return result, binanceError(err)
+	}
+
+	quantity, quoteQuantity := decimal.Zero, decimal.Zero
+	for _, trade := range trades {
+		qty, err := decimal.NewFromString(trade.Quantity)
+		if err != nil {
+			return result, fmt.Errorf("invalid trade quantity: %v", err)
+		}
+		quoteQty, err := decimal.NewFromString(trade.QuoteQuantity)
+		if err != nil {
+			return result, fmt.Errorf("invalid trade quote quantity: %v", err)
+		}
+		commission, err := decimal.NewFromString(trade.Commission)
+		if err != nil {
+			return result, fmt.Errorf("invalid commission: %v", err)
+		}
+		quantity = quantity.Add(qty)
+		quoteQuantity = quoteQuantity.Add(quoteQty)
+		result.addFee(commission, trade.CommissionAsset)
+	}
+	result.setFilled(quantity, quoteQuantity)
+
+	return result, nil
+}
+
+func (b *Binance) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	address, err := b.newClient(b.config.Key, b.config.Secret).NewDepositAddressService().
+		Coin(strings.ToUpper(asset)).Network(network).Do(ctx)
+	if err != nil {
+		return DepositAddress{}, binanceError(err)
+	}
+
+	return DepositAddress{
+		Asset:   strings.ToUpper(asset),
+		Network: network,
+		Address: address.Address,
+		Memo:    address.Tag,
+	}, nil
+}
+
+func (b *Binance) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	params := url.Values{}
+	params.Set("coin", strings.ToUpper(asset))
+	params.Set("network", network)
+	params.Set("address", address)
+	params.Set("amount", amount.String())
+	if memo != "" {
+		params.Set("addressTag", memo)
+	}
+
+	var withdrawal binance_connector.WithdrawResponse
+	if err := b.signedPost(ctx, "/sapi/v1/capital/withdraw/apply", params, &withdrawal); err != nil {
+		return Transfer{}, binanceError(err)
+	}
+
+	return acceptedWithdrawal(ctx, b.GetWithdrawal, withdrawal.Id, asset, network, address, memo, amount), nil
+}
+
+// signedPost sends a signed request the way the connector does. The connector only takes the prices, quantities
+// and amounts as float64, that it formats with %v, e.g. 1e-05, when the exact decimals must be sent.
+func (b *Binance) signedPost(ctx context.Context, path string, params url.Values, res interface{}) error {
+	params.Set("timestamp", strconv.FormatInt(b.clock.Now().UnixMilli(), 10))
+	query := params.Encode()
+	mac := hmac.New(sha256.New, []byte(b.config.Secret))
+	mac.Write([]byte(query))
+	query += "&signature=" + hex.EncodeToString(mac.Sum(nil))
+
+	baseURL := b.config.HTTP.BaseURL
+	if baseURL == "" {
+		baseURL = binanceBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+path+"?"+query, nil)
 	if err != nil {
 		return err
 	}
+	req.Header.Set("X-MBX-APIKEY", b.config.Key)
 
-	newOrderTyped, ok := newOrder.(*binance_connector.CreateOrderResponseFULL)
-	if !ok {
-		return fmt.Errorf("newOrderTyped is not a binance_connector.CreateOrderResponseFULL: %v", newOrder)
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	if strings.ToUpper(newOrderTyped.Status) != "FILLED" {
-		return fmt.Errorf("the sell order has not been filled")
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := new(handlers.APIError)
+		if err := json.Unmarshal(body, apiErr); err != nil {
+			return fmt.Errorf("status %v: %s", resp.StatusCode, body)
+		}
+		return apiErr
+	}
+
+	return json.Unmarshal(body, res)
+}
+
+func (b *Binance) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	client := b.newClient(b.config.Key, b.config.Secret)
+	// The history has no filter on the ID, it is read page by page from the latest withdrawal
+	for offset := 0; ; offset += binanceHistoryLimit {
+		withdrawals, err := client.NewWithdrawHistoryService().
+			Coin(strings.ToUpper(asset)).Offset(offset).Limit(binanceHistoryLimit).Do(ctx)
+		if err != nil {
+			return Transfer{}, binanceError(err)
+		}
+
+		for _, withdrawal := range withdrawals {
+			if withdrawal.Id != withdrawalID || !sameNetwork(withdrawal.Network, network) {
+				continue
+			}
+			return binanceWithdrawal(withdrawal)
+		}
+
+		if len(withdrawals) < binanceHistoryLimit {
+			return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+		}
+	}
+}
+
+func binanceWithdrawal(withdrawal *binance_connector.WithdrawHistoryResponse) (Transfer, error) {
+	transfer := Transfer{
+		ID:      withdrawal.Id,
+		Asset:   withdrawal.Coin,
+		Network: withdrawal.Network,
+		Address: withdrawal.Address,
+		Status:  binanceWithdrawStatus(withdrawal.Status, withdrawal.TxId),
+		TxID:    withdrawal.TxId,
+	}
+	// the amount is the one received, without the fee
+	received, err := decimal.NewFromString(withdrawal.Amount)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("invalid withdrawal amount: %v", err)
+	}
+	transfer.Fee, err = decimal.NewFromString(withdrawal.TransactionFee)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("invalid withdrawal fee: %v", err)
+	}
+	transfer.Amount = received.Add(transfer.Fee)
+	if appliedAt, err := time.ParseInLocation(time.DateTime, withdrawal.ApplyTime, time.UTC); err == nil {
+		transfer.CreatedAt = appliedAt
+	}
+	return transfer, nil
+}
+
+// binanceWithdrawStatus converts 0 (email sent), 1 (cancelled), 2 (awaiting approval), 3 (rejected),
+// 4 (processing), 5 (failure) and 6 (completed)
+func binanceWithdrawStatus(status int, txID string) TransferStatus {
+	switch status {
+	case 6:
+		return TransferStatusCompleted
+	case 1, 3, 5:
+		return TransferStatusFailed
+	case 4:
+		return inProgressStatus(txID)
+	default:
+		return TransferStatusPending
+	}
+}
+
+func (b *Binance) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	deposits, err := b.newClient(b.config.Key, b.config.Secret).NewDepositHistoryService().
+		Coin(strings.ToUpper(asset)).TxId(txID).Do(ctx)
+	if err != nil {
+		return Transfer{}, binanceError(err)
+	}
+
+	for _, deposit := range deposits {
+		if deposit.TxId != txID || !sameNetwork(deposit.Network, network) {
+			continue
+		}
+
+		amount, err := decimal.NewFromString(deposit.Amount)
+		if err != nil {
+			return Transfer{}, fmt.Errorf("invalid deposit amount: %v", err)
+		}
+		return Transfer{
+			ID:        deposit.Id,
+			Asset:     deposit.Coin,
+			Network:   deposit.Network,
+			Address:   deposit.Address,
+			Memo:      deposit.AddressTag,
+			Amount:    amount,
+			Fee:       decimal.Zero,
+			Status:    binanceDepositStatus(deposit.Status),
+			TxID:      deposit.TxId,
+			CreatedAt: millisToTime(int64(deposit.InsertTime)),
+		}, nil
+	}
+
+	return Transfer{}, errTransferNotFound("deposit", txID)
+}
+
+// binanceDepositStatus converts 0 (pending), 6 (credited but cannot withdraw), 7 (wrong deposit),
+// 8 (waiting user confirm) and 1 (success)
+func binanceDepositStatus(status int) TransferStatus {
+	switch status {
+	case 1, 6:
+		return TransferStatusCompleted
+	case 7:
+		return TransferStatusFailed
+	case 8:
+		return TransferStatusPending
+	default:
+		return TransferStatusConfirming
 	}
-	return nil
 }
 
 func (b Binance) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
-	return nil
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeBought() {
+		return fmt.Errorf("%w: %v cannot be bought", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanBuyAndWithdraw() {
+		return fmt.Errorf("the account cannot buy and withdraw")
+	}
+
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
+	}
+	for _, network := range coinNetwork.NetworkList {
+		if network.WithdrawEnable {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
 
 func (b Binance) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
-	return nil
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeSold() {
+		return fmt.Errorf("%w: %v cannot be sold", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanDepositAndSell() {
+		return fmt.Errorf("the account cannot deposit and sell")
+	}
+
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
+	}
+	for _, network := range coinNetwork.NetworkList {
+		if network.DepositEnable {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
@@ -0,0 +1,117 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/shopspring/decimal"
+)
+
+const binanceStreamURL = "wss://stream.binance.com:9443"
+
+func (b Binance) NewMarketDataStream() *MarketDataStream {
+	return newMarketDataStream(binanceStream{b})
+}
+
+// binanceStream follows the diff. depth stream, see "How to manage a local order book correctly"
+type binanceStream struct {
+	broker Binance
+}
+
+type binanceDepthEvent struct {
+	FirstID int64               `json:"U"`
+	LastID  int64               `json:"u"`
+	Bids    [][]decimal.Decimal `json:"b"`
+	Asks    [][]decimal.Decimal `json:"a"`
+}
+
+func (s binanceStream) symbol(ticker database.SelectExchangeTickersRow) string {
+	return strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+}
+
+func (s binanceStream) url(symbol string) string {
+	baseURL := binanceStreamURL
+	if s.broker.config.StreamURL != "" {
+		baseURL = s.broker.config.StreamURL
+	}
+	return baseURL + "/ws/" + strings.ToLower(symbol) + "@depth@100ms"
+}
+
+func (s binanceStream) subscribe(symbol string) [][]byte { return nil }
+
+func (s binanceStream) ping() []byte { return nil }
+
+func (s binanceStream) decode(message []byte) (depthUpdate, bool, error) {
+	var event binanceDepthEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return depthUpdate{}, false, err
+	}
+	if event.LastID == 0 {
+		return depthUpdate{}, false, nil
+	}
+
+	bids, err := toOffers(event.Bids)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+	asks, err := toOffers(event.Asks)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+
+	return depthUpdate{
+		FirstID: event.FirstID,
+		LastID:  event.LastID,
+		Bids:    bids,
+		Asks:    asks,
+	}, true, nil
+}
+
+func (s binanceStream) snapshot(ctx context.Context, symbol string) (depthSnapshot, error) {
+	client := s.broker.newClient("", "")
+	orders, err := client.NewOrderBookService().Symbol(symbol).Limit(1000).Do(ctx)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	bids, err := binanceOffers(orders.Bids)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+	asks, err := binanceOffers(orders.Asks)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	return depthSnapshot{
+		LastID: int64(orders.LastUpdateId),
+		Book: coin.OrderBook{
+			Bids: bids,
+			Asks: asks,
+		},
+	}, nil
+}
+
+// binanceOffers converts the levels of the connector without the rounding of big.Float.String
+func binanceOffers(levels [][]*big.Float) ([]coin.Offer, error) {
+	offers := make([]coin.Offer, len(levels))
+	for i, level := range levels {
+		price, err := decimal.NewFromString(level[0].Text('f', -1))
+		if err != nil {
+			return nil, err
+		}
+		quantity, err := decimal.NewFromString(level[1].Text('f', -1))
+		if err != nil {
+			return nil, err
+		}
+		offers[i] = coin.Offer{
+			Price:    price,
+			Quantity: quantity,
+		}
+	}
+	return offers, nil
+}
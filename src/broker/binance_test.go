@@ -2,11 +2,15 @@ package broker_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
 	"github.com/shopspring/decimal"
 )
 
@@ -44,7 +48,7 @@ func TestBinanceBuy(t *testing.T) {
 		RetryTimerHTTP: 1000 * time.Millisecond,
 	})
 
-	err := binance.Buy(context.Background(), database.SelectExchangeTickersRow{
+	_, err := binance.Buy(context.Background(), database.SelectExchangeTickersRow{
 		Base:  "TAO",
 		Quote: "USDT",
 	}, decimal.NewFromFloat(400.), decimal.NewFromFloat(5.))
@@ -61,7 +65,7 @@ func TestBinanceSell(t *testing.T) {
 		RetryTimerHTTP: 1000 * time.Millisecond,
 	})
 
-	err := binance.Sell(context.Background(), database.SelectExchangeTickersRow{
+	_, err := binance.Sell(context.Background(), database.SelectExchangeTickersRow{
 		Base:  "TAO",
 		Quote: "USDT",
 	}, decimal.NewFromFloat(400.), decimal.NewFromFloat(5.))
@@ -70,3 +74,74 @@ func TestBinanceSell(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestBinanceGetOrderBooksPrecision(t *testing.T) {
+	ex := fakeexchange.NewBinance("key", "secret")
+	defer ex.Close()
+	// 13 significant digits, that big.Float.String would round to 10
+	price := decimal.RequireFromString("61990.12345678")
+	quantity := decimal.RequireFromString("0.12345678901")
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{{Price: price, Quantity: quantity}},
+		Asks: []coin.Offer{{Price: price.Add(decimal.RequireFromString("0.00000001")), Quantity: quantity}},
+	})
+
+	binance, _ := broker.NewBinance(broker.Config{
+		InternalName: "Binance",
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+	orderbook, err := binance.GetOrderBooks(context.Background(), database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderbook.Bids) != 1 || !orderbook.Bids[0].Price.Equal(price) || !orderbook.Bids[0].Quantity.Equal(quantity) {
+		t.Fatalf("the bid should be kept exact: %v", orderbook.Bids)
+	}
+	if len(orderbook.Asks) != 1 || !orderbook.Asks[0].Price.Equal(decimal.RequireFromString("61990.12345679")) {
+		t.Fatalf("the ask should be kept exact: %v", orderbook.Asks)
+	}
+}
+
+func TestBinanceExchangeStatus(t *testing.T) {
+	ex := fakeexchange.NewBinance("key", "secret")
+	defer ex.Close()
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+		Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+	})
+	ex.SetNetwork(fakeexchange.Network{Coin: "BTC", Network: "BTC", WithdrawEnable: true})
+
+	binance, _ := broker.NewBinance(broker.Config{
+		InternalName: "Binance",
+		Key:          "key",
+		Secret:       "secret",
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+	ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+	ctx := context.Background()
+
+	if err := binance.CanBuyAndWithdraw(ctx, ticker); err == nil {
+		t.Fatalf("the status should be unknown before the refresh")
+	}
+	if err := binance.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := binance.CanBuyAndWithdraw(ctx, ticker); err != nil {
+		t.Fatal(err)
+	}
+	if err := binance.CanDepositAndSell(ctx, ticker); err == nil {
+		t.Fatalf("the deposits of BTC are closed")
+	}
+	// The networks are read once by the refresh, the checks reading them from the cache
+	if requests := ex.Requests(http.MethodGet, "/sapi/v1/capital/config/getall"); requests != 1 {
+		t.Fatalf("the networks should only be requested by the refresh: %v requests", requests)
+	}
+
+	ex.SetTradable("BTC", "USDT", false)
+	if err := binance.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := binance.CanBuyAndWithdraw(ctx, ticker); err == nil {
+		t.Fatalf("a halted ticker should not be bought")
+	}
+}
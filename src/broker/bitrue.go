@@ -2,6 +2,10 @@ package broker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
@@ -11,24 +15,67 @@ import (
 
 type Bitrue struct {
 	config Config
+	client *bitruesdk.Client
 
 	coins           CoinsMap
 	exchangeCoins   ExchangeCoinsMap
 	exchangeTickers ExchangeTickersMap
+
+	tickersStatus  map[string]coin.TickerStatus
+	tickersFilters map[string]coin.TickerFilters
+	accountStatus  AccountStatus
+	coinsNetwork   map[string]bitruesdk.CoinGetExchangeInfo
+	clock          *Clock
+}
+
+func init() {
+	DefaultRegistry.Register("Bitrue", NewBitrue)
 }
 
 func NewBitrue(config Config) (IBroker, error) {
-	return &Bitrue{
-		config: config,
-	}, nil
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config.HTTP = config.httpConfig(bitrueRateLimits)
+
+	b := &Bitrue{
+		config:         config,
+		client:         bitruesdk.NewClient(config.HTTP.BaseURL, config.HTTP.HTTPClient()),
+		tickersStatus:  make(map[string]coin.TickerStatus),
+		tickersFilters: make(map[string]coin.TickerFilters),
+		accountStatus:  NewAccountStatus(false),
+		coinsNetwork:   make(map[string]bitruesdk.CoinGetExchangeInfo),
+	}
+	b.clock = newClock(func(ctx context.Context) (time.Time, error) { return b.serverTime(ctx) })
+	b.client.Now = b.clock.Now
+	return b, nil
 }
 
 func (b Bitrue) GetBrokerName() string { return b.config.InternalName }
 
+// Format prints the broker as its redacted configuration, its clients holding the key being left out
+func (b Bitrue) Format(f fmt.State, verb rune) { formatBroker(f, verb, "Bitrue", b.config) }
+
+func (b Bitrue) Clock() *Clock { return b.clock }
+
+// serverTime returns the time of the exchange, for Clock
+func (b Bitrue) serverTime(ctx context.Context) (time.Time, error) {
+	resp, err := b.client.GetServerTime(ctx)
+	if err != nil {
+		return time.Time{}, bitrueError(err)
+	}
+	return millisToTime(resp.ServerTime), nil
+}
+
+// bitrueSymbol returns the name of the ticker in the /api/v1 endpoints, e.g. BTCUSDT
+func bitrueSymbol(ticker database.SelectExchangeTickersRow) string {
+	return strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+}
+
 func (b Bitrue) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
-	tickers, err := bitruesdk.GetTickersInformation()
+	tickers, err := b.client.GetTickersInformation(ctx)
 	if err != nil {
-		return nil, err
+		return nil, bitrueError(err)
 	}
 
 	tickersInfo := make(map[coin.TickerPair]CoinAllInfo)
@@ -76,13 +123,46 @@ func (b Bitrue) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]
 }
 
 func (b Bitrue) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
-	return coin.OrderBook{}, nil
+	orders, err := b.client.GetDepth(ctx, bitrueSymbol(ticker), 100)
+	if err != nil {
+		return coin.OrderBook{}, bitrueError(err)
+	}
+
+	orderbook := coin.OrderBook{
+		Bids: make([]coin.Offer, 0, len(orders.Bids)),
+		Asks: make([]coin.Offer, 0, len(orders.Asks)),
+	}
+
+	for _, bid := range orders.Bids {
+		if !bid.Quantity.GreaterThan(decimal.Zero) {
+			continue
+		}
+		orderbook.Bids = append(orderbook.Bids, coin.Offer{
+			Price:    bid.Price,
+			Quantity: bid.Quantity,
+		})
+	}
+
+	for _, ask := range orders.Asks {
+		if !ask.Quantity.GreaterThan(decimal.Zero) {
+			continue
+		}
+		orderbook.Asks = append(orderbook.Asks, coin.Offer{
+			Price:    ask.Price,
+			Quantity: ask.Quantity,
+		})
+	}
+
+	orderbook.SortAsks()
+	orderbook.SortBids()
+
+	return orderbook, nil
 }
 
 func (b Bitrue) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
-	coins, err := bitruesdk.GetBalance(b.config.Key, b.config.Secret)
+	coins, err := b.client.GetBalance(ctx, b.config.Key, b.config.Secret)
 	if err != nil {
-		return nil, err
+		return nil, bitrueError(err)
 	}
 
 	balance := make(map[coin.CoinBaseStr]coin.Balance)
@@ -102,6 +182,34 @@ func (b Bitrue) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balan
 	return balance, nil
 }
 
+func (b Bitrue) GetFees() FeeSchedule {
+	return b.config.Fees
+}
+
+func (b Bitrue) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	exchangeInfo, err := b.client.GetExchangeInfo(ctx)
+	if err != nil {
+		return nil, bitrueError(err)
+	}
+
+	networks := []coin.Network{}
+	for _, c := range exchangeInfo.Coins {
+		for _, chain := range c.ChainDetail {
+			networks = append(networks, coin.Network{
+				Coin:             strings.ToUpper(c.Coin),
+				Code:             chain.Chain,
+				Name:             chain.Chain,
+				DepositPossible:  chain.EnableDeposit,
+				WithdrawPossible: chain.EnableWithdraw,
+				WithdrawFee:      chain.WithdrawFee,
+				WithdrawMin:      chain.MinWithdraw,
+			})
+		}
+	}
+
+	return networks, nil
+}
+
 func (b *Bitrue) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
 	b.coins = coins
 	b.exchangeCoins = exchangeCoins
@@ -109,21 +217,373 @@ func (b *Bitrue) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeC
 }
 
 func (b *Bitrue) RefreshExchangeInformation(ctx context.Context) error {
+	if err := b.clock.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to synchronize the clock: %w", err)
+	}
+
+	respExchange, err := b.client.GetExchangeInfo(ctx)
+	if err != nil {
+		return bitrueError(err)
+	}
+
+	for _, ticker := range respExchange.Symbols {
+		isLimitOrderAllowed := false
+		for _, orderAllowed := range ticker.OrderTypes {
+			if strings.ToUpper(orderAllowed) == "LIMIT" {
+				isLimitOrderAllowed = true
+				break
+			}
+		}
+
+		isTrading := strings.ToUpper(ticker.Status) == "TRADING"
+		b.tickersStatus[strings.ToUpper(ticker.Symbol)] = coin.TickerStatus{
+			IsEnabled:            isTrading,
+			IsLimitOrderAllowed:  isLimitOrderAllowed,
+			IsSpotTradingAllowed: isTrading,
+			IsBuyable:            isTrading,
+			IsSellable:           isTrading,
+		}
+		b.tickersFilters[strings.ToUpper(ticker.Symbol)] = bitrueFilters(ticker)
+	}
+
+	for _, c := range respExchange.Coins {
+		b.coinsNetwork[strings.ToUpper(c.Coin)] = c
+	}
+
+	respAccount, err := b.client.GetBalance(ctx, b.config.Key, b.config.Secret)
+	if err != nil {
+		return bitrueError(err)
+	}
+
+	// canDeposit and canWithdraw are always false, the networks of each coin are checked instead
+	b.accountStatus = AccountStatus{
+		CanTrade:    respAccount.CanTrade,
+		CanDeposit:  true,
+		CanWithdraw: true,
+		CanUseSpot:  true,
+	}
+
 	return nil
 }
 
-func (b *Bitrue) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error {
-	return nil
+// bitrueFilters reads the PRICE_FILTER and LOT_SIZE filters of a symbol
+func bitrueFilters(symbol bitruesdk.SymbolGetExchangeInfo) coin.TickerFilters {
+	var filters coin.TickerFilters
+	for _, filter := range symbol.Filters {
+		switch filter.FilterType {
+		case "PRICE_FILTER":
+			filters.TickSize = coin.StepFromPrecision(filter.PriceScale)
+		case "LOT_SIZE":
+			filters.StepSize = coin.StepFromPrecision(filter.VolumeScale)
+			filters.MinQuantity = filter.MinQty
+			filters.MaxQuantity = filter.MaxQty
+			filters.MinNotional = filter.MinVal
+		}
+	}
+	return filters
 }
 
-func (b *Bitrue) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error {
-	return nil
+func (b Bitrue) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return tickerFilters(b.tickersFilters, bitrueSymbol(ticker))
+}
+
+func (b *Bitrue) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, bitruesdk.Order{
+		Symbol:        bitrueSymbol(ticker),
+		Side:          bitruesdk.BUY,
+		TimeInForce:   bitruesdk.FILL_OR_KILL,
+		Price:         maxPrice,
+		ClientOrderID: newClientOrderID(),
+	}, quoteQuantity)
+}
+
+func (b *Bitrue) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, bitruesdk.Order{
+		Symbol:        bitrueSymbol(ticker),
+		Side:          bitruesdk.SELL,
+		TimeInForce:   bitruesdk.IMMEDIATE_OR_CANCEL,
+		Price:         minPrice,
+		ClientOrderID: newClientOrderID(),
+	}, quoteQuantity)
+}
+
+// placeOrder rounds the order worth quoteQuantity to the filters of its symbol, sends it and checks that it has
+// been entirely filled. Whatever would remain in the book is cancelled, should the time in force not be honoured.
+func (b *Bitrue) placeOrder(ctx context.Context, order bitruesdk.Order, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	filters, err := tickerFilters(b.tickersFilters, order.Symbol)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	order.Price, order.Quantity, err = normalizeOrder(filters, order.Side == bitruesdk.BUY, order.Price, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("%v: %w", order.Symbol, err)
+	}
+
+	postResp, err := b.client.PostOrder(ctx, b.config.Key, b.config.Secret, order)
+	if err != nil {
+		return OrderResult{}, bitrueError(err)
+	}
+	orderID := postResp.OrderID.String()
+
+	getResp, err := b.client.GetOrder(ctx, b.config.Key, b.config.Secret, order.Symbol, orderID)
+	if err != nil {
+		return OrderResult{OrderID: orderID}, bitrueError(err)
+	}
+
+	if status := toOrderStatus(getResp.Status); status == OrderStatusNew || status == OrderStatusPartiallyFilled {
+		if err := b.client.CancelOrder(ctx, b.config.Key, b.config.Secret, order.Symbol, orderID); err != nil {
+			return OrderResult{OrderID: orderID, Status: status}, fmt.Errorf("the %v order has not been filled and could not be cancelled: %w", strings.ToLower(string(order.Side)), bitrueError(err))
+		}
+		getResp.Status = string(OrderStatusCanceled)
+	}
+
+	result, err := b.orderResult(ctx, getResp)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.IsFilled() {
+		return result, fmt.Errorf("the %v order has not been filled", strings.ToLower(string(order.Side)))
+	}
+	return result, nil
+}
+
+func (b *Bitrue) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
+	order, err := b.client.GetOrder(ctx, b.config.Key, b.config.Secret, bitrueSymbol(ticker), orderID)
+	if err != nil {
+		return OrderResult{}, bitrueError(err)
+	}
+
+	return b.orderResult(ctx, order)
+}
+
+func (b *Bitrue) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	return bitrueError(b.client.CancelOrder(ctx, b.config.Key, b.config.Secret, bitrueSymbol(ticker), orderID))
+}
+
+// CancelAllOrders cancels the open orders one by one, Bitrue having no endpoint to cancel them at once
+func (b *Bitrue) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	symbol := bitrueSymbol(ticker)
+
+	openOrders, err := b.client.GetOpenOrders(ctx, b.config.Key, b.config.Secret, symbol)
+	if err != nil {
+		return bitrueError(err)
+	}
+
+	var errs []error
+	for _, order := range openOrders {
+		if err := b.client.CancelOrder(ctx, b.config.Key, b.config.Secret, symbol, order.OrderID.String()); err != nil {
+			errs = append(errs, fmt.Errorf("order %v: %w", order.OrderID, bitrueError(err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bitrue) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	openOrders, err := b.client.GetOpenOrders(ctx, b.config.Key, b.config.Secret, bitrueSymbol(ticker))
+	if err != nil {
+		return nil, bitrueError(err)
+	}
+
+	results := make([]OrderResult, 0, len(openOrders))
+	for _, order := range openOrders {
+		result, err := b.orderResult(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// orderResult converts an order, fetching the trades of its symbol for the fees when it has been executed
+func (b *Bitrue) orderResult(ctx context.Context, order bitruesdk.GetOrderResult) (OrderResult, error) {
+	orderID := order.OrderID.String()
+	result := OrderResult{
+		OrderID:       orderID,
+		ClientOrderID: order.ClientOrderID,
+		Status:        toOrderStatus(order.Status),
+		CreatedAt:     millisToTime(order.Time),
+		UpdatedAt:     millisToTime(order.UpdateTime),
+	}
+	result.setFilled(order.ExecutedQty, order.CummulativeQuoteQty)
+
+	if result.FilledQuantity.GreaterThan(decimal.Zero) {
+		trades, err := b.client.GetMyTrades(ctx, b.config.Key, b.config.Secret, order.Symbol)
+		if err != nil {
+			return result, bitrueError(err)
+		}
+		for _, trade := range trades {
+			if trade.OrderID.String() == orderID {
+				result.addFee(trade.Commission, trade.CommissionAsset)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetDepositAddress always fails, as Bitrue has no endpoint returning the deposit addresses
+func (b *Bitrue) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	return DepositAddress{}, fmt.Errorf("bitrue does not provide the deposit addresses through its API")
+}
+
+func (b *Bitrue) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	withdrawal, err := b.client.Withdraw(ctx, b.config.Key, b.config.Secret, bitruesdk.Withdraw{
+		Coin:      strings.ToLower(asset),
+		ChainName: network,
+		AddressTo: address,
+		Tag:       memo,
+		Amount:    amount,
+	})
+	if err != nil {
+		return Transfer{}, bitrueError(err)
+	}
+
+	return acceptedWithdrawal(ctx, b.GetWithdrawal, withdrawal.WithdrawID.String(), asset, network, address, memo, amount), nil
+}
+
+func (b *Bitrue) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	withdrawal, err := b.findTransfer(ctx, b.client.GetWithdrawHistory, asset, func(withdrawal bitruesdk.Transfer) bool {
+		return withdrawal.ID.String() == withdrawalID && sameNetwork(withdrawal.ChainName, network)
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	if withdrawal == nil {
+		return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+	}
+
+	transfer := bitrueTransfer(*withdrawal)
+	switch withdrawal.Status {
+	case 6:
+		transfer.Status = TransferStatusCompleted
+	case 5:
+		transfer.Status = TransferStatusFailed
+	default:
+		transfer.Status = inProgressStatus(withdrawal.TxID)
+	}
+	return transfer, nil
+}
+
+func (b *Bitrue) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	deposit, err := b.findTransfer(ctx, b.client.GetDepositHistory, asset, func(deposit bitruesdk.Transfer) bool {
+		return deposit.TxID == txID && sameNetwork(deposit.ChainName, network)
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	if deposit == nil {
+		return Transfer{}, errTransferNotFound("deposit", txID)
+	}
+
+	transfer := bitrueTransfer(*deposit)
+	switch deposit.Status {
+	case 1:
+		transfer.Status = TransferStatusCompleted
+	case 2:
+		transfer.Status = TransferStatusFailed
+	default:
+		transfer.Status = TransferStatusConfirming
+	}
+	return transfer, nil
+}
+
+// bitrueHistoryLimit is the largest page of the withdrawal and deposit histories
+const bitrueHistoryLimit = 1000
+
+// findTransfer reads a history of asset page by page from the latest transfer, it returns the first one matching
+// found, nil when none does
+func (b *Bitrue) findTransfer(ctx context.Context,
+	history func(ctx context.Context, apiKey, secretKey string, params bitruesdk.HistoryParams) ([]bitruesdk.Transfer, error),
+	asset string, found func(bitruesdk.Transfer) bool) (*bitruesdk.Transfer, error) {
+	for offset := 0; ; offset += bitrueHistoryLimit {
+		transfers, err := history(ctx, b.config.Key, b.config.Secret, bitruesdk.HistoryParams{
+			Coin:   strings.ToLower(asset),
+			Offset: offset,
+			Limit:  bitrueHistoryLimit,
+		})
+		if err != nil {
+			return nil, bitrueError(err)
+		}
+
+		for i := range transfers {
+			if found(transfers[i]) {
+				return &transfers[i], nil
+			}
+		}
+
+		if len(transfers) < bitrueHistoryLimit {
+			return nil, nil
+		}
+	}
+}
+
+// bitrueTransfer converts a withdrawal or a deposit, but its status that differs between both
+func bitrueTransfer(transfer bitruesdk.Transfer) Transfer {
+	return Transfer{
+		ID:        transfer.ID.String(),
+		Asset:     strings.ToUpper(transfer.Symbol),
+		Network:   transfer.ChainName,
+		Address:   transfer.AddressTo,
+		Memo:      transfer.Tag,
+		Amount:    transfer.Amount,
+		Fee:       transfer.Fee,
+		TxID:      transfer.TxID,
+		CreatedAt: millisToTime(transfer.CreatedAt),
+	}
 }
 
 func (b Bitrue) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
-	return nil
+	symbol := bitrueSymbol(ticker)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeBought() {
+		return fmt.Errorf("%w: %v cannot be bought", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanBuyAndWithdraw() {
+		return fmt.Errorf("the account cannot buy and withdraw")
+	}
+
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
+	}
+	for _, chain := range coinNetwork.ChainDetail {
+		if chain.EnableWithdraw {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
 
 func (b Bitrue) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
-	return nil
+	symbol := bitrueSymbol(ticker)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeSold() {
+		return fmt.Errorf("%w: %v cannot be sold", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanDepositAndSell() {
+		return fmt.Errorf("the account cannot deposit and sell")
+	}
+
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
+	}
+	for _, chain := range coinNetwork.ChainDetail {
+		if chain.EnableDeposit {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
@@ -5,6 +5,11 @@ import (
 	"testing"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
 )
 
 func TestBitrueGetTickersInformation(t *testing.T) {
@@ -22,3 +27,56 @@ func TestBitrueGetBalance(t *testing.T) {
 
 	bitrue.GetBalance(context.Background())
 }
+
+func TestBitrueTrading(t *testing.T) {
+	ex := fakeexchange.NewBitrue("key", "secret")
+	defer ex.Close()
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{
+			{Price: decimal.NewFromInt(98), Quantity: decimal.NewFromInt(1)},
+			{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)},
+		},
+		Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(2)}},
+	})
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+	ex.SetBalance("BTC", decimal.NewFromInt(2))
+	ex.SetNetwork(fakeexchange.Network{Coin: "BTC", Network: "BTC", DepositEnable: true})
+
+	bitrue, _ := broker.NewBitrue(broker.Config{
+		InternalName: "Bitrue",
+		Key:          "key",
+		Secret:       "secret",
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+	ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+	ctx := context.Background()
+
+	orderbook, err := bitrue.GetOrderBooks(ctx, ticker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderbook.Bids) != 2 || !orderbook.Bids[0].Price.Equal(decimal.NewFromInt(99)) {
+		t.Fatalf("unexpected order book: %v", orderbook)
+	}
+
+	if err := bitrue.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := bitrue.CanDepositAndSell(ctx, ticker); err != nil {
+		t.Fatal(err)
+	}
+	if err := bitrue.CanBuyAndWithdraw(ctx, ticker); err == nil {
+		t.Fatalf("the withdrawals of BTC are closed")
+	}
+
+	// only 1 of the 2 BTC can be sold at 99 or more, the IOC order is partially filled
+	if _, err := bitrue.Sell(ctx, ticker, decimal.NewFromInt(99), decimal.NewFromInt(198)); err == nil {
+		t.Fatalf("the sell order should not have been filled")
+	}
+	if _, err := bitrue.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Balance("USDT").Equal(decimal.NewFromInt(999)) {
+		t.Fatalf("unexpected USDT balance: %v", ex.Balance("USDT"))
+	}
+}
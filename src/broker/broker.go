@@ -26,23 +26,51 @@ type IBroker interface {
 
 	// GetTickersInformation returns the best bid and ask for each coin at once
 	GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error)
-	// GetDepositInformation returns the available network for a specific coin. Only coin.Base is used
-	//GetDepositInformation(ctx context.Context, coin database.SelectExchangeTickersRow) error
 
 	// GetOrderBooks returns the list of the best bid*s* and ask*s* for a specific ticker
 	GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error)
 	// GetBalance retrieve the entire balance for the Spot account, for each tokens
 	GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error)
+	// GetNetworks returns the deposit and withdrawal networks of every coin listed on the exchange
+	GetNetworks(ctx context.Context) ([]coin.Network, error)
+
+	// GetFees returns the fee schedule of the account, GetFees().Rate(base, quote) being the rates of a ticker
+	GetFees() FeeSchedule
 
 	// RefreshCoinsInformation sets the data for each coins, from the three databases: coins, exchange_coins, exchange_tickers
 	RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap)
 	// RefreshExchangeInformation refreshes status about the account and the state of the different coins/tickers (whether they are enabled, etc)
 	RefreshExchangeInformation(ctx context.Context) error
 
-	// Buy sets a FOK buy order for the specified ticker, buying the equivalent of quoteQuantity, at a maximum price of maxPrice
-	Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error
-	// Sell sets a IOC sell order for the specified ticker, selling the equivalent of quoteQuantity, at a minimum price of minPrice
-	Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error
+	// GetFilters returns the constraints of the exchange on the orders of ticker, known once RefreshExchangeInformation
+	// has been called. Buy and Sell round their orders to them.
+	GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error)
+
+	// Buy sets a FOK buy order for the specified ticker, buying the equivalent of quoteQuantity, at a maximum price of maxPrice.
+	// When the order is not entirely filled, the error comes with the result of the order, if it has been placed.
+	Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error)
+	// Sell sets a IOC sell order for the specified ticker, selling the equivalent of quoteQuantity, at a minimum price of minPrice.
+	// When the order is not entirely filled, the error comes with the result of the order, if it has been placed.
+	Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error)
+
+	// GetOrder returns the current state of an order of ticker, orderID being the ID given by the exchange
+	GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error)
+	// CancelOrder cancels an order of ticker that is still open
+	CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error
+	// CancelAllOrders cancels every open order of ticker, it succeeds when there is none
+	CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error
+	// ListOpenOrders returns the orders of ticker that are still open, new or partially filled
+	ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error)
+
+	// GetDepositAddress returns the address of the account to deposit asset through network
+	GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error)
+	// Withdraw sends amount asset to address through network, the withdrawal fee being taken from amount.
+	// memo is only needed by the networks using a tag along with the address.
+	Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error)
+	// GetWithdrawal returns the current state of a withdrawal, withdrawalID being the ID returned by Withdraw
+	GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error)
+	// GetDeposit returns the current state of the deposit of asset sent in the on-chain transaction txID
+	GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error)
 
 	CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error
 	CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error
@@ -1,5 +1,12 @@
 package broker
 
+import (
+	"context"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+)
+
 type AccountStatus struct {
 	CanTrade    bool
 	CanDeposit  bool
@@ -27,3 +34,34 @@ func (as AccountStatus) CanBuyAndWithdraw() bool {
 func (as AccountStatus) CanDepositAndSell() bool {
 	return as.CanTradeSpot() && as.CanDeposit
 }
+
+// AccountBroker is an account of an exchange sharing the market data of another broker of the exchange: the
+// tickers, the order books and the networks come from market, the balances, orders and transfers from the account
+type AccountBroker struct {
+	IBroker
+	market IBroker
+}
+
+func NewAccountBroker(account, market IBroker) IBroker {
+	return &AccountBroker{IBroker: account, market: market}
+}
+
+func (b *AccountBroker) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
+	return b.market.GetTickersInformation(ctx)
+}
+
+func (b *AccountBroker) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
+	return b.market.GetOrderBooks(ctx, ticker)
+}
+
+func (b *AccountBroker) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	return b.market.GetNetworks(ctx)
+}
+
+// Clock returns the clock of the account, nil when it does not timestamp its requests
+func (b *AccountBroker) Clock() *Clock {
+	if synchronizer, ok := b.IBroker.(IClockSynchronizer); ok {
+		return synchronizer.Clock()
+	}
+	return nil
+}
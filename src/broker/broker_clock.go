@@ -0,0 +1,97 @@
+package broker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// IClockSynchronizer is implemented by the brokers signing their requests with a timestamp, which the exchange
+// rejects when it is too far from its own clock
+type IClockSynchronizer interface {
+	Clock() *Clock
+}
+
+// ClockHealth reports how the local clock relates to the one of an exchange
+type ClockHealth struct {
+	// Offset is the drift of the local clock, positive when it is behind the exchange
+	Offset time.Duration
+	// RTT is the round trip of the last synchronization, the offset being known within RTT/2
+	RTT      time.Duration
+	SyncedAt time.Time
+	// Err is the error of the last synchronization, the previous offset being kept meanwhile
+	Err error
+}
+
+// Clock estimates the time of an exchange from the server time it reports, the signed requests being timestamped
+// with Now. It is zero, the local time being used, until synchronized.
+type Clock struct {
+	serverTime func(ctx context.Context) (time.Time, error)
+
+	mu     sync.RWMutex
+	health ClockHealth
+}
+
+func newClock(serverTime func(ctx context.Context) (time.Time, error)) *Clock {
+	return &Clock{serverTime: serverTime}
+}
+
+// Now returns the time of the exchange
+func (c *Clock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns the difference between the clock of the exchange and the local one
+func (c *Clock) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.health.Offset
+}
+
+func (c *Clock) Health() ClockHealth {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.health
+}
+
+// Sync measures the offset of the clock of the exchange, the server time being assumed to be read halfway
+// through the request
+func (c *Clock) Sync(ctx context.Context) error {
+	sentAt := time.Now()
+	serverTime, err := c.serverTime(ctx)
+	receivedAt := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.health.Err = err
+		return err
+	}
+
+	rtt := receivedAt.Sub(sentAt)
+	c.health = ClockHealth{
+		Offset:   serverTime.Sub(sentAt.Add(rtt / 2)),
+		RTT:      rtt,
+		SyncedAt: receivedAt,
+	}
+	return nil
+}
+
+// Run synchronizes the clock every interval until ctx is done
+func (c *Clock) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("clock sync: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
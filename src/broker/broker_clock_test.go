@@ -0,0 +1,59 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
+)
+
+func TestClockSync(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetBalance("USDT", decimal.NewFromInt(100))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			synchronizer, ok := b.(broker.IClockSynchronizer)
+			if !ok {
+				t.Skipf("%v does not timestamp its requests", exchange.name)
+			}
+			clock := synchronizer.Clock()
+			ctx := context.Background()
+
+			// The local clock is 30 seconds behind the exchange, far beyond its receive window
+			ex.SetClockOffset(30 * time.Second)
+			if _, err := b.GetBalance(ctx); err == nil {
+				t.Fatalf("the request should be rejected before the clock is synchronized")
+			}
+
+			if err := clock.Sync(ctx); err != nil {
+				t.Fatal(err)
+			}
+			health := clock.Health()
+			if health.Err != nil || health.SyncedAt.IsZero() || (health.Offset-30*time.Second).Abs() > time.Second {
+				t.Fatalf("unexpected clock health: %+v", health)
+			}
+
+			balance, err := b.GetBalance(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(100)) {
+				t.Fatalf("unexpected balance: %+v", balance)
+			}
+		})
+	}
+}
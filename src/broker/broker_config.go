@@ -1,15 +1,44 @@
 package broker
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/ArbitrageCoin/crypto-sdk/src/secrets"
+	"github.com/shopspring/decimal"
 )
 
+// Config is the configuration of a broker, its Key and Secret being redacted whenever it is printed
 type Config struct {
-	InternalName   string
-	Key            string
-	Secret         string
+	InternalName string
+	Key          string
+	Secret       string
+	// KeyRef and SecretRef name the key and the secret in a secrets.Provider, set into Key and Secret by Resolve
+	KeyRef    string
+	SecretRef string
+	// RetryTimerHTTP is the wait before resending a request failing for a transient reason, doubled at each retry
 	RetryTimerHTTP time.Duration
+	// MaxAttempts is the number of times such a request is sent at most, defaultMaxAttempts when zero
+	MaxAttempts int
+
+	// HTTP configures the requests sent to the exchange, its zero value targets the real endpoints
+	HTTP httpclient.Config
+
+	// Stream keeps the order books up to date through the websocket of the exchange, see MarketDataStream
+	Stream bool
+	// StreamURL overrides the websocket endpoint
+	StreamURL string
+
+	// Fees are the trading and deposit fees of the account, used to estimate the profitability of the trades
+	Fees FeeSchedule
+
+	// Paper replaces the orders by simulated ones, see PaperBroker
+	Paper        bool
+	PaperBalance map[coin.CoinBaseStr]decimal.Decimal
 }
 
 func (b *Config) UnmarshalJSON(data []byte) error {
@@ -26,6 +55,9 @@ func (b *Config) UnmarshalJSON(data []byte) error {
 	}
 
 	// Convert RetryTimerHTTP from string to time.Duration
+	if aux.RetryTimerHTTP == "" {
+		return nil
+	}
 	duration, err := time.ParseDuration(aux.RetryTimerHTTP)
 	if err != nil {
 		return err
@@ -33,3 +65,93 @@ func (b *Config) UnmarshalJSON(data []byte) error {
 	b.RetryTimerHTTP = duration
 	return nil
 }
+
+// Resolve returns config with the key and the secret looked up from provider, when referenced
+func (b Config) Resolve(ctx context.Context, provider secrets.Provider) (Config, error) {
+	for _, secret := range []struct {
+		value *string
+		ref   string
+	}{{&b.Key, b.KeyRef}, {&b.Secret, b.SecretRef}} {
+		if secret.ref == "" {
+			continue
+		}
+		if *secret.value != "" {
+			return b, fmt.Errorf("%v is referenced while being set in the configuration", secret.ref)
+		}
+		if provider == nil {
+			return b, fmt.Errorf("%v is referenced without any secrets provider", secret.ref)
+		}
+		value, err := provider.Lookup(ctx, secret.ref)
+		if err != nil {
+			return b, err
+		}
+		*secret.value = value
+	}
+	return b, nil
+}
+
+// Format prints the configuration with its Key and Secret redacted, whatever the verb
+func (b Config) Format(f fmt.State, verb rune) {
+	type config Config
+	if b.Key != "" {
+		b.Key = secrets.Redacted
+	}
+	if b.Secret != "" {
+		b.Secret = secrets.Redacted
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), config(b))
+}
+
+// formatBroker prints a broker named name as its configuration, redacted. The brokers keep their configuration,
+// and their SDK clients the key, in unexported fields that fmt prints without calling Config.Format.
+func formatBroker(f fmt.State, verb rune, name string, config Config) {
+	fmt.Fprintf(f, "%v "+fmt.FormatString(f, verb), name, config)
+}
+
+// String makes the panics print the configuration redacted as well
+func (b Config) String() string {
+	return fmt.Sprintf("%+v", b)
+}
+
+// validate checks the settings that would only fail once the broker is used
+func (b Config) validate() error {
+	if (b.Key == "") != (b.Secret == "") {
+		return fmt.Errorf("the key and the secret must be set together")
+	}
+	if b.MaxAttempts < 0 || b.RetryTimerHTTP < 0 {
+		return fmt.Errorf("the retries cannot be negative")
+	}
+	return nil
+}
+
+const (
+	defaultMaxAttempts = 3
+	// maxRetryTimer bounds the wait between two attempts
+	maxRetryTimer = 30 * time.Second
+	retryJitter   = 0.2
+	// signedRetryWindow bounds the retries of a signed request below the receive window of 5s of Binance, MEXC,
+	// Bitrue and XT, the last attempt having to reach the exchange within it
+	signedRetryWindow = 4 * time.Second
+)
+
+// httpConfig returns the HTTP configuration of a broker, throttled to the limits of its exchange unless a limiter
+// is already set, and retrying the idempotent requests as RetryTimerHTTP and MaxAttempts tell
+func (b Config) httpConfig(limits httpclient.RateLimits) httpclient.Config {
+	config := b.HTTP
+	if config.Limiter == nil {
+		config.Limiter = httpclient.NewLimiter(limits)
+	}
+
+	maxAttempts := b.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	config.Retry = httpclient.RetryPolicy{
+		MaxAttempts:  maxAttempts,
+		Backoff:      b.RetryTimerHTTP,
+		MaxBackoff:   maxRetryTimer,
+		Jitter:       retryJitter,
+		SignedWindow: signedRetryWindow,
+	}
+	return config
+}
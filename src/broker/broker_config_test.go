@@ -0,0 +1,80 @@
+package broker_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/secrets"
+)
+
+func TestConfigSecrets(t *testing.T) {
+	t.Setenv("ARB_GATE_KEY", "gate-key")
+	t.Setenv("ARB_GATE_SECRET", "gate-secret")
+
+	config, err := broker.BrokersConfig{
+		"Gate":    {{KeyRef: "Gate/Key", SecretRef: "Gate/Secret"}},
+		"Binance": {{Key: "binance-key", Secret: "binance-secret"}},
+	}.Resolve(context.Background(), secrets.Env{Prefix: "ARB_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config["Gate"][0].Key != "gate-key" || config["Gate"][0].Secret != "gate-secret" || config["Binance"][0].Key != "binance-key" {
+		t.Fatalf("unexpected keys")
+	}
+
+	// Neither the logs nor the panics print the keys
+	c := config["Gate"][0]
+	for _, printed := range []string{
+		fmt.Sprintf("%v", c),
+		fmt.Sprintf("%+v", c),
+		fmt.Sprintf("%#v", c),
+		fmt.Sprintf("%s", c),
+		fmt.Sprintf("%v", broker.Account{Config: c}),
+		fmt.Sprint(config),
+		c.String(),
+	} {
+		if strings.Contains(printed, "gate-key") || strings.Contains(printed, "gate-secret") || strings.Contains(printed, "binance") {
+			t.Fatalf("the secrets are printed: %v", printed)
+		}
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", c), "Key:"+secrets.Redacted) {
+		t.Fatalf("the key should be redacted: %+v", c)
+	}
+
+	// Nor do they print the brokers, whose configuration and SDK clients are unexported
+	var brokers []broker.IBroker
+	for _, exchange := range fakeBrokers {
+		b, err := exchange.newBroker(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		brokers = append(brokers, b)
+	}
+	paper, err := broker.NewPaperBroker(c, brokers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	brokers = append(brokers, paper, broker.NewAccountBroker(brokers[1], brokers[0]))
+	for _, b := range brokers {
+		for _, printed := range []string{fmt.Sprintf("%v", b), fmt.Sprintf("%+v", b), fmt.Sprintf("%#v", b), fmt.Sprint([]broker.IBroker{b})} {
+			if strings.Contains(printed, "gate-key") || strings.Contains(printed, "gate-secret") {
+				t.Fatalf("the secrets of %v are printed: %v", b.GetBrokerName(), printed)
+			}
+		}
+	}
+
+	for _, c := range []broker.Config{
+		{KeyRef: "Gate/Key", Key: "key"},
+		{KeyRef: "MEXC/Key"},
+	} {
+		if _, err := c.Resolve(context.Background(), secrets.Env{Prefix: "ARB_"}); err == nil {
+			t.Fatalf("the configuration should not be resolved: %+v", c)
+		}
+	}
+	if _, err := (broker.Config{KeyRef: "Gate/Key"}).Resolve(context.Background(), nil); err == nil {
+		t.Fatalf("a reference without any provider should fail")
+	}
+}
@@ -0,0 +1,19 @@
+package broker_test
+
+import (
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker/brokertest"
+)
+
+func TestConformance(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			brokertest.Run(t, brokertest.Harness{
+				Name:      exchange.name,
+				NewFake:   exchange.newFake,
+				NewBroker: exchange.newBroker,
+			})
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/bitruesdk"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/mexcsdk"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/xt_com"
+	"github.com/binance/binance-connector-go/handlers"
+	"github.com/gateio/gateapi-go/v6"
+)
+
+// The errors of the brokers are classified into these whatever the exchange, to be matched with errors.Is
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrSymbolNotTrading    = errors.New("symbol not trading")
+	ErrInvalidSignature    = errors.New("invalid signature")
+	// ErrOrderRejected is an order refused for another reason, e.g. a filter, the reason being the message of
+	// the ExchangeError
+	ErrOrderRejected   = errors.New("order rejected")
+	ErrNetworkDisabled = errors.New("network disabled")
+	ErrTimeout         = errors.New("timeout")
+)
+
+// ExchangeError is an error answered by an exchange, with its raw code and message
+type ExchangeError struct {
+	Exchange string
+	// Status is the HTTP status of the response, 0 when unknown
+	Status  int
+	Code    string
+	Message string
+	// Kind is the one of the errors above the error is classified as, nil when unknown
+	Kind error
+	// Err is the error returned by the SDK, if any
+	Err error
+}
+
+func (e *ExchangeError) Error() string {
+	message := e.Message
+	if e.Code != "" && e.Code != e.Message {
+		message += " (" + e.Code + ")"
+	}
+	if e.Kind == nil {
+		return fmt.Sprintf("%v: %v", e.Exchange, message)
+	}
+	return fmt.Sprintf("%v: %v: %v", e.Exchange, e.Kind, message)
+}
+
+// Unwrap makes errors.Is match the kind of the error as well as the error of the SDK
+func (e *ExchangeError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// newExchangeError classifies the error code of exchange, kinds giving the kind of its codes. The codes shared by
+// several errors, or unknown, are classified by their message, then by the HTTP status.
+func newExchangeError(exchange string, err error, status int, code, message string, kinds map[string]error) *ExchangeError {
+	kind := kinds[code]
+	if kind == nil || kind == ErrOrderRejected {
+		if messageKind := messageKind(message); messageKind != nil {
+			kind = messageKind
+		}
+	}
+	if kind == nil {
+		kind = statusKind(status)
+	}
+
+	return &ExchangeError{
+		Exchange: exchange,
+		Status:   status,
+		Code:     code,
+		Message:  message,
+		Kind:     kind,
+		Err:      err,
+	}
+}
+
+// messageKind classifies an error from its message
+func messageKind(message string) error {
+	message = strings.ToLower(message)
+	containsAny := func(substrs ...string) bool {
+		for _, substr := range substrs {
+			if strings.Contains(message, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case containsAny("insufficient", "not enough balance"):
+		return ErrInsufficientBalance
+	case containsAny("withdraw", "deposit") && containsAny("disabled", "suspended", "closed", "not open", "not support"):
+		return ErrNetworkDisabled
+	case containsAny("market is closed", "not trading", "not tradable", "trading is disabled", "trading disabled"):
+		return ErrSymbolNotTrading
+	}
+	return nil
+}
+
+// statusKind classifies an error from its HTTP status
+func statusKind(status int) error {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusTeapot:
+		return ErrRateLimited
+	case http.StatusUnauthorized:
+		return ErrInvalidSignature
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	}
+	return nil
+}
+
+// transportError classifies an error raised before any answer of the exchange: a request throttled by the
+// rate limiter, or timing out
+func transportError(err error) error {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	if errors.Is(err, httpclient.ErrRateLimited) {
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// binanceErrorKinds are the kinds of the error codes of Binance, Bitrue sharing them
+var binanceErrorKinds = map[string]error{
+	"-1003": ErrRateLimited,      // TOO_MANY_REQUESTS
+	"-1015": ErrRateLimited,      // TOO_MANY_ORDERS
+	"-1007": ErrTimeout,          // TIMEOUT
+	"-1021": ErrInvalidSignature, // INVALID_TIMESTAMP
+	"-1022": ErrInvalidSignature, // INVALID_SIGNATURE
+	"-2014": ErrInvalidSignature, // BAD_API_KEY_FMT
+	"-2015": ErrInvalidSignature, // REJECTED_MBX_KEY
+	"-1121": ErrSymbolNotTrading, // BAD_SYMBOL
+	"-1013": ErrOrderRejected,    // filter failure
+	"-1111": ErrOrderRejected,    // BAD_PRECISION
+	"-1116": ErrOrderRejected,    // INVALID_ORDER_TYPE
+	"-1117": ErrOrderRejected,    // INVALID_SIDE
+	"-2010": ErrOrderRejected,    // NEW_ORDER_REJECTED
+}
+
+// binanceError classifies an error returned by the Binance connector
+func binanceError(err error) error {
+	var exchangeErr *ExchangeError
+	var apiErr *handlers.APIError
+	switch {
+	case err == nil || errors.As(err, &exchangeErr):
+		return err
+	case errors.As(err, &apiErr):
+		return newExchangeError("Binance", err, 0, strconv.FormatInt(apiErr.Code, 10), apiErr.Message, binanceErrorKinds)
+	}
+	return transportError(err)
+}
+
+// mexcErrorKinds are the kinds of the error codes of MEXC
+var mexcErrorKinds = map[string]error{
+	"700002": ErrInvalidSignature,    // signature not valid
+	"700003": ErrInvalidSignature,    // timestamp outside of the recvWindow
+	"10072":  ErrInvalidSignature,    // invalid access key
+	"-1121":  ErrSymbolNotTrading,    // invalid symbol
+	"10007":  ErrSymbolNotTrading,    // symbol not support api
+	"30014":  ErrSymbolNotTrading,    // invalid symbol
+	"30016":  ErrSymbolNotTrading,    // trading disabled
+	"10101":  ErrInsufficientBalance, // insufficient balance
+	"30004":  ErrInsufficientBalance, // insufficient position
+	"30005":  ErrInsufficientBalance, // oversold
+	"30002":  ErrOrderRejected,       // minimum transaction volume
+	"30003":  ErrOrderRejected,       // maximum transaction volume
+}
+
+// mexcError classifies an error returned by the MEXC SDK
+func mexcError(err error) error {
+	var exchangeErr *ExchangeError
+	var apiErr *mexcsdk.APIError
+	switch {
+	case err == nil || errors.As(err, &exchangeErr):
+		return err
+	case errors.As(err, &apiErr):
+		return newExchangeError("MEXC", err, apiErr.StatusCode, strconv.Itoa(apiErr.Code), apiErr.Msg, mexcErrorKinds)
+	}
+	return transportError(err)
+}
+
+// gateErrorKinds are the kinds of the error labels of Gate
+var gateErrorKinds = map[string]error{
+	"TOO_MANY_REQUESTS":     ErrRateLimited,
+	"INVALID_SIGNATURE":     ErrInvalidSignature,
+	"INVALID_KEY":           ErrInvalidSignature,
+	"REQUEST_EXPIRED":       ErrInvalidSignature,
+	"INVALID_CURRENCY_PAIR": ErrSymbolNotTrading,
+	"TRADE_RESTRICTED":      ErrSymbolNotTrading,
+	"BALANCE_NOT_ENOUGH":    ErrInsufficientBalance,
+	"INVALID_PRECISION":     ErrOrderRejected,
+	"POC_FILL_IMMEDIATELY":  ErrOrderRejected,
+	"FOK_NOT_FILL":          ErrOrderRejected,
+}
+
+// gateError classifies an error returned by the Gate SDK
+func gateError(err error) error {
+	var exchangeErr *ExchangeError
+	var apiErr gateapi.GateAPIError
+	var genericErr gateapi.GenericOpenAPIError
+	switch {
+	case err == nil || errors.As(err, &exchangeErr):
+		return err
+	case errors.As(err, &apiErr):
+		return newExchangeError("Gate", err, gateStatus(apiErr.APIError), apiErr.Label, apiErr.GetMessage(), gateErrorKinds)
+	case errors.As(err, &genericErr):
+		return newExchangeError("Gate", err, gateStatus(genericErr), "", string(genericErr.Body()), gateErrorKinds)
+	}
+	return transportError(err)
+}
+
+// gateStatus returns the HTTP status of an error of the Gate SDK, which only keeps it at the start of the message
+func gateStatus(err gateapi.GenericOpenAPIError) int {
+	status, _, _ := strings.Cut(err.Error(), " ")
+	code, _ := strconv.Atoi(status)
+	return code
+}
+
+// xtErrorKinds are the kinds of the error codes of XT, the AUTH_ ones being invalid signatures, and the ORDER_F
+// ones orders rejected by a filter
+var xtErrorKinds = map[string]error{
+	"TOO_MANY_REQUESTS": ErrRateLimited,
+	"SYMBOL_001":        ErrSymbolNotTrading, // symbol not found
+	"SYMBOL_002":        ErrSymbolNotTrading, // symbol not online
+	"ORDER_F0101":       ErrInsufficientBalance,
+}
+
+// xtError classifies an error answered by XT with code in the mc field of its envelope, and messages in the ma one
+func xtError(resp *xt_com.APIBody, code string, messages []string) error {
+	if resp.Err != nil {
+		return transportError(fmt.Errorf("%v: %w", resp.Path, resp.Err))
+	}
+
+	message := strings.Join(messages, ", ")
+	if message == "" {
+		message = code
+	}
+	exchangeErr := newExchangeError("XT", nil, resp.StatusCode, code, message, xtErrorKinds)
+	if exchangeErr.Kind == nil {
+		switch {
+		case strings.HasPrefix(code, "AUTH_"):
+			exchangeErr.Kind = ErrInvalidSignature
+		case strings.HasPrefix(code, "ORDER_F"):
+			exchangeErr.Kind = ErrOrderRejected
+		}
+	}
+	return exchangeErr
+}
+
+// bitrueError classifies an error returned by the Bitrue SDK
+func bitrueError(err error) error {
+	var exchangeErr *ExchangeError
+	var apiErr *bitruesdk.APIError
+	switch {
+	case err == nil || errors.As(err, &exchangeErr):
+		return err
+	case errors.As(err, &apiErr):
+		return newExchangeError("Bitrue", err, apiErr.StatusCode, strconv.Itoa(apiErr.Code), apiErr.Msg, binanceErrorKinds)
+	}
+	return transportError(err)
+}
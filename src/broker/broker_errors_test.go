@@ -0,0 +1,104 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
+)
+
+func TestErrors(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(100)}},
+				Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(100)}},
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+			ex.SetNetwork(fakeexchange.Network{
+				Coin:          "USDT",
+				Network:       "TRX",
+				DepositEnable: true,
+				WithdrawFee:   decimal.NewFromInt(1),
+				WithdrawMin:   decimal.NewFromInt(10),
+				WithdrawMax:   decimal.NewFromInt(1000),
+			})
+
+			newBroker := func(secret string) broker.IBroker {
+				b, err := exchange.newBroker(broker.Config{
+					InternalName: exchange.name,
+					Key:          "key",
+					Secret:       secret,
+					MaxAttempts:  1,
+					HTTP:         httpclient.Config{BaseURL: ex.URL()},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return b
+			}
+			b := newBroker("secret")
+			ctx := context.Background()
+			if err := b.RefreshExchangeInformation(ctx); err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+
+			// The raw code and message of the exchange are kept along the kind of the error
+			_, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(5000))
+			if !errors.Is(err, broker.ErrInsufficientBalance) {
+				t.Fatalf("expected an insufficient balance: %v", err)
+			}
+			var exchangeErr *broker.ExchangeError
+			if !errors.As(err, &exchangeErr) || exchangeErr.Exchange != exchange.name || exchangeErr.Code == "" || exchangeErr.Message == "" {
+				t.Fatalf("the error should hold the code and the message of the exchange: %#v", err)
+			}
+
+			if _, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.RequireFromString("0.001")); !errors.Is(err, broker.ErrOrderRejected) {
+				t.Fatalf("an order under the filters should be rejected: %v", err)
+			}
+
+			if _, err := b.Withdraw(ctx, "USDT", "TRX", "TAddress", "", decimal.NewFromInt(50)); !errors.Is(err, broker.ErrNetworkDisabled) {
+				t.Fatalf("the withdrawal should fail on a disabled network: %v", err)
+			}
+
+			if _, err := newBroker("wrong").GetBalance(ctx); !errors.Is(err, broker.ErrInvalidSignature) {
+				t.Fatalf("expected an invalid signature: %v", err)
+			}
+
+			ex.SetTradable("BTC", "USDT", false)
+			if _, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(50)); !errors.Is(err, broker.ErrSymbolNotTrading) {
+				t.Fatalf("expected a symbol not trading: %v", err)
+			}
+
+			// The requests are held back for a while after a 429
+			ex.FailNext(http.StatusTooManyRequests)
+			if _, err := b.GetOrderBooks(ctx, ticker); err == nil {
+				t.Fatalf("the order books should fail")
+			}
+			shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			if _, err := b.GetOrderBooks(shortCtx, ticker); !errors.Is(err, broker.ErrRateLimited) {
+				t.Fatalf("expected a rate limit: %v", err)
+			}
+
+			time.Sleep(time.Second)
+			ex.SetLatency(10 * time.Second)
+			shortCtx, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+			if _, err := b.GetBalance(shortCtx); !errors.Is(err, broker.ErrTimeout) {
+				t.Fatalf("expected a timeout: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/shopspring/decimal"
+)
+
+// FeeRate is the share of the amount of an order charged by the exchange, e.g. 0.001 for 0.1%
+type FeeRate struct {
+	Maker decimal.Decimal
+	Taker decimal.Decimal
+}
+
+// FeeSchedule is the trading fees of the account, configured in config.json
+type FeeSchedule struct {
+	// Maker and Taker are the rates of the exchange, when neither the tier nor the symbol have their own
+	Maker decimal.Decimal
+	Taker decimal.Decimal
+
+	// VIPTier is the tier of the account, Tiers the rates of each tier
+	VIPTier int
+	Tiers   map[int]FeeRate
+
+	// Symbols overrides the rates of some symbols, written base then quote, e.g. "BTCUSDT"
+	Symbols map[string]FeeRate
+
+	// DepositFees are the flat fees charged on deposits of some coins, as the exchanges do not expose them
+	DepositFees map[coin.CoinBaseStr]decimal.Decimal
+}
+
+// Rate returns the fee rate of the symbol base/quote, the one of the symbol first, then the one of the tier
+func (s FeeSchedule) Rate(base, quote string) FeeRate {
+	if rate, ok := s.Symbols[strings.ToUpper(base+quote)]; ok {
+		return rate
+	}
+	if rate, ok := s.Tiers[s.VIPTier]; ok {
+		return rate
+	}
+	return FeeRate{Maker: s.Maker, Taker: s.Taker}
+}
+
+// DepositFee returns the flat fee charged on the deposits of asset, zero for most of the coins
+func (s FeeSchedule) DepositFee(asset string) decimal.Decimal {
+	if fee, ok := s.DepositFees[strings.ToUpper(asset)]; ok {
+		return fee
+	}
+	return decimal.Zero
+}
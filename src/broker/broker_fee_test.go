@@ -0,0 +1,48 @@
+package broker_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/shopspring/decimal"
+)
+
+func TestFeeSchedule(t *testing.T) {
+	var config broker.Config
+	err := json.Unmarshal([]byte(`{
+		"InternalName": "Binance",
+		"RetryTimerHTTP": "1000ms",
+		"Fees": {
+			"Maker": "0.001",
+			"Taker": "0.001",
+			"VIPTier": 1,
+			"Tiers": {"1": {"Maker": "0.0009", "Taker": "0.001"}},
+			"Symbols": {"BTCUSDT": {"Maker": "0", "Taker": "0.0005"}},
+			"DepositFees": {"XRP": "0.25"}
+		}
+	}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fees := config.Fees
+
+	if rate := fees.Rate("btc", "usdt"); !rate.Maker.IsZero() || !rate.Taker.Equal(decimal.RequireFromString("0.0005")) {
+		t.Fatalf("the rate of the symbol should be used: %+v", rate)
+	}
+	if rate := fees.Rate("ETH", "USDT"); !rate.Maker.Equal(decimal.RequireFromString("0.0009")) {
+		t.Fatalf("the rate of the tier should be used: %+v", rate)
+	}
+
+	fees.VIPTier = 0
+	if rate := fees.Rate("ETH", "USDT"); !rate.Maker.Equal(decimal.RequireFromString("0.001")) {
+		t.Fatalf("the rate of the exchange should be used: %+v", rate)
+	}
+
+	if fee := fees.DepositFee("xrp"); !fee.Equal(decimal.RequireFromString("0.25")) {
+		t.Fatalf("unexpected deposit fee of XRP: %v", fee)
+	}
+	if fee := fees.DepositFee("BTC"); !fee.IsZero() {
+		t.Fatalf("unexpected deposit fee of BTC: %v", fee)
+	}
+}
@@ -0,0 +1,140 @@
+package broker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	// OrderStatusExpired is the status of the IOC and FOK orders whose remaining has been cancelled by the exchange
+	OrderStatusExpired  OrderStatus = "EXPIRED"
+	OrderStatusRejected OrderStatus = "REJECTED"
+)
+
+// OrderResult is the state of an order, the same way for every exchange
+type OrderResult struct {
+	OrderID       string
+	ClientOrderID string
+	Status        OrderStatus
+
+	// FilledQuantity is in the base asset, FilledQuoteQuantity in the quote asset
+	FilledQuantity      decimal.Decimal
+	FilledQuoteQuantity decimal.Decimal
+	AveragePrice        decimal.Decimal
+
+	Fee      decimal.Decimal
+	FeeAsset string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (o OrderResult) IsFilled() bool {
+	return o.Status == OrderStatusFilled
+}
+
+// setFilled sets the filled quantities and computes the average price from them
+func (o *OrderResult) setFilled(quantity, quoteQuantity decimal.Decimal) {
+	o.FilledQuantity = quantity
+	o.FilledQuoteQuantity = quoteQuantity
+	o.AveragePrice = decimal.Zero
+	if quantity.GreaterThan(decimal.Zero) {
+		o.AveragePrice = quoteQuantity.Div(quantity)
+	}
+}
+
+// addFee adds a fee paid for the order. An order is charged in a single asset, the fees in another
+// asset than the first one are ignored.
+func (o *OrderResult) addFee(amount decimal.Decimal, asset string) {
+	asset = strings.ToUpper(asset)
+	if o.FeeAsset == "" {
+		o.FeeAsset = asset
+	}
+	if o.FeeAsset == asset {
+		o.Fee = o.Fee.Add(amount)
+	}
+}
+
+// normalizeOrder returns the price and the base quantity of an order worth quoteQuantity, rounded to the filters
+// of the ticker. The price is rounded in favor of the order, down for a buy and up for a sell, and the quantity down.
+func normalizeOrder(filters coin.TickerFilters, buy bool, price, quoteQuantity decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	if !price.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: the price %v must be positive", ErrOrderRejected, price)
+	}
+
+	price = filters.RoundPrice(price, !buy)
+	if !price.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: the price is lower than the tick size %v", ErrOrderRejected, filters.TickSize)
+	}
+	quantity := filters.RoundQuantity(quoteQuantity.Div(price))
+
+	if err := filters.Validate(price, quantity); err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: %v", ErrOrderRejected, err)
+	}
+	return price, quantity, nil
+}
+
+// tickerFilters returns the filters of symbol, which are known once the exchange information has been refreshed
+func tickerFilters(filters map[string]coin.TickerFilters, symbol string) (coin.TickerFilters, error) {
+	f, ok := filters[symbol]
+	if !ok {
+		return coin.TickerFilters{}, fmt.Errorf("the filters of %v are unknown, the exchange information must be refreshed", symbol)
+	}
+	return f, nil
+}
+
+// parseFilter sets dst to the value of a filter, an empty value leaving dst unchanged
+func parseFilter(dst *decimal.Decimal, value string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
+
+// toOrderStatus converts the statuses used by Binance and the exchanges copying its API
+func toOrderStatus(status string) OrderStatus {
+	switch strings.ToUpper(status) {
+	case "NEW":
+		return OrderStatusNew
+	case "PARTIALLY_FILLED":
+		return OrderStatusPartiallyFilled
+	case "FILLED":
+		return OrderStatusFilled
+	case "EXPIRED", "EXPIRED_IN_MATCH":
+		return OrderStatusExpired
+	case "REJECTED":
+		return OrderStatusRejected
+	default:
+		// CANCELED, PARTIALLY_CANCELED, PENDING_CANCEL
+		return OrderStatusCanceled
+	}
+}
+
+// newClientOrderID returns a unique ID, short enough to be accepted by every exchange
+func newClientOrderID() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")[:24]
+}
+
+// millisToTime converts the timestamps in milliseconds of the exchanges
+func millisToTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,382 @@
+package broker_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
+)
+
+// fakeBrokers pairs each broker with the fake exchange standing in for its API
+var fakeBrokers = []struct {
+	name      string
+	newFake   func(key, secret string) *fakeexchange.Exchange
+	newBroker func(config broker.Config) (broker.IBroker, error)
+}{
+	{"Binance", fakeexchange.NewBinance, broker.NewBinance},
+	{"MEXC", fakeexchange.NewMEXC, broker.NewMEXC},
+	{"Gate", fakeexchange.NewGate, broker.NewGate},
+	{"XT", fakeexchange.NewXT, broker.NewXT},
+	{"Bitrue", fakeexchange.NewBitrue, broker.NewBitrue},
+}
+
+func TestOrderResults(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+				Asks: []coin.Offer{
+					{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)},
+					{Price: decimal.NewFromInt(102), Quantity: decimal.NewFromInt(1)},
+				},
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+			ex.SetBalance("BTC", decimal.NewFromInt(1))
+			ex.SetFeeRate(decimal.RequireFromString("0.001"))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.RefreshExchangeInformation(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+
+			// 2 BTC at 100 at most, only 1 is available under this price
+			result, err := b.Buy(context.Background(), ticker, decimal.NewFromInt(102), decimal.NewFromInt(204))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.IsFilled() || result.OrderID == "" || result.ClientOrderID == "" {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			if !result.FilledQuantity.Equal(decimal.NewFromInt(2)) || !result.FilledQuoteQuantity.Equal(decimal.NewFromInt(202)) {
+				t.Fatalf("unexpected filled quantities: %v BTC for %v USDT", result.FilledQuantity, result.FilledQuoteQuantity)
+			}
+			if !result.AveragePrice.Equal(decimal.NewFromInt(101)) {
+				t.Fatalf("unexpected average price: %v", result.AveragePrice)
+			}
+			if !result.Fee.Equal(decimal.RequireFromString("0.002")) || result.FeeAsset != "BTC" {
+				t.Fatalf("unexpected fee: %v %v", result.Fee, result.FeeAsset)
+			}
+
+			// only 1 of the 2 BTC can be sold at 99 or more
+			result, err = b.Sell(context.Background(), ticker, decimal.NewFromInt(99), decimal.NewFromInt(198))
+			if err == nil {
+				t.Fatalf("the sell order should not have been filled")
+			}
+			if result.IsFilled() || !result.FilledQuantity.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			if !result.Fee.Equal(decimal.RequireFromString("0.099")) || result.FeeAsset != "USDT" {
+				t.Fatalf("unexpected fee: %v %v", result.Fee, result.FeeAsset)
+			}
+		})
+	}
+}
+
+func TestOrderManagement(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+				Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+			ctx := context.Background()
+
+			// leftovers of a previous run, resting under the best bid
+			first, err := ex.PlaceOrder("BTC", "USDT", "BUY", decimal.NewFromInt(90), decimal.NewFromInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ex.PlaceOrder("BTC", "USDT", "BUY", decimal.NewFromInt(80), decimal.NewFromInt(1)); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := b.GetOrder(ctx, ticker, first)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.OrderID != first || result.Status != broker.OrderStatusNew || !result.FilledQuantity.IsZero() {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+
+			openOrders, err := b.ListOpenOrders(ctx, ticker)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(openOrders) != 2 {
+				t.Fatalf("unexpected open orders: %+v", openOrders)
+			}
+
+			if err := b.CancelOrder(ctx, ticker, first); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.CancelOrder(ctx, ticker, first); err == nil {
+				t.Fatalf("a cancelled order cannot be cancelled again")
+			}
+			if result, err := b.GetOrder(ctx, ticker, first); err != nil || result.Status != broker.OrderStatusCanceled {
+				t.Fatalf("unexpected result: %+v, %v", result, err)
+			}
+
+			if err := b.CancelAllOrders(ctx, ticker); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.CancelAllOrders(ctx, ticker); err != nil {
+				t.Fatal(err)
+			}
+			if openOrders, err := b.ListOpenOrders(ctx, ticker); err != nil || len(openOrders) != 0 {
+				t.Fatalf("unexpected open orders: %+v, %v", openOrders, err)
+			}
+			if !ex.Balance("USDT").Equal(decimal.NewFromInt(1000)) {
+				t.Fatalf("the funds of the cancelled orders should be released: %v", ex.Balance("USDT"))
+			}
+		})
+	}
+}
+
+func TestOrderFilters(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+				Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+			})
+			ex.SetFilters("BTC", "USDT", coin.TickerFilters{
+				TickSize:    decimal.RequireFromString("0.1"),
+				StepSize:    decimal.RequireFromString("0.01"),
+				MinQuantity: decimal.RequireFromString("0.01"),
+				MaxQuantity: decimal.NewFromInt(100),
+				MinNotional: decimal.NewFromInt(10),
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+			ctx := context.Background()
+
+			if _, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(50)); err == nil {
+				t.Fatalf("the order should not be sent before the filters are known")
+			}
+			if err := b.RefreshExchangeInformation(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			filters, err := b.GetFilters(ticker)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !filters.TickSize.Equal(decimal.RequireFromString("0.1")) || !filters.StepSize.Equal(decimal.RequireFromString("0.01")) || !filters.MinNotional.Equal(decimal.NewFromInt(10)) {
+				t.Fatalf("unexpected filters: %+v", filters)
+			}
+
+			// 50.5 / 100.07 is rounded down to 0.5 BTC, at 100.0 at most
+			result, err := b.Buy(ctx, ticker, decimal.RequireFromString("100.07"), decimal.RequireFromString("50.5"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.FilledQuantity.Equal(decimal.RequireFromString("0.5")) {
+				t.Fatalf("unexpected filled quantity: %v", result.FilledQuantity)
+			}
+
+			// 8 USDT is under the minimum notional, the order is not sent
+			orders := len(ex.Orders())
+			if _, err := b.Sell(ctx, ticker, decimal.NewFromInt(99), decimal.NewFromInt(8)); err == nil || !strings.Contains(err.Error(), "notional") {
+				t.Fatalf("the order should be rejected by the filters: %v", err)
+			}
+			if len(ex.Orders()) != orders {
+				t.Fatalf("the order should not have been sent")
+			}
+
+			// 49.6 / 98.93 is rounded down to 0.5 BTC, at 99.0 at least
+			result, err = b.Sell(ctx, ticker, decimal.RequireFromString("98.93"), decimal.RequireFromString("49.6"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.FilledQuantity.Equal(decimal.RequireFromString("0.5")) {
+				t.Fatalf("unexpected filled quantity: %v", result.FilledQuantity)
+			}
+		})
+	}
+}
+
+func TestOrderPrecision(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("SHIB", "USDT", coin.OrderBook{
+				Asks: []coin.Offer{{Price: decimal.RequireFromString("0.00001234"), Quantity: decimal.NewFromInt(10000000)}},
+			})
+			ex.SetFilters("SHIB", "USDT", coin.TickerFilters{
+				TickSize:    decimal.RequireFromString("0.00000001"),
+				StepSize:    decimal.NewFromInt(1),
+				MinQuantity: decimal.NewFromInt(1),
+				MaxQuantity: decimal.NewFromInt(100000000),
+				MinNotional: decimal.NewFromInt(1),
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := b.RefreshExchangeInformation(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			// The price is under 1e-4, that %v would send as 1.234e-05
+			ticker := database.SelectExchangeTickersRow{Base: "SHIB", Quote: "USDT"}
+			result, err := b.Buy(ctx, ticker, decimal.RequireFromString("0.00001234"), decimal.RequireFromString("12.34"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.FilledQuantity.Equal(decimal.NewFromInt(1000000)) {
+				t.Fatalf("unexpected filled quantity: %v", result.FilledQuantity)
+			}
+			orders := ex.Orders()
+			if len(orders) != 1 || !orders[0].Price.Equal(decimal.RequireFromString("0.00001234")) || !orders[0].Quantity.Equal(decimal.NewFromInt(1000000)) {
+				t.Fatalf("unexpected orders: %+v", orders)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+				Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+			})
+			ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName:   exchange.name,
+				Key:            "key",
+				Secret:         "secret",
+				RetryTimerHTTP: time.Millisecond,
+				HTTP:           httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.RefreshExchangeInformation(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+			ctx := context.Background()
+
+			// The order books are fetched again after a 5xx, up to 3 times
+			ex.FailNext(http.StatusServiceUnavailable, http.StatusInternalServerError)
+			if _, err := b.GetOrderBooks(ctx, ticker); err != nil {
+				t.Fatal(err)
+			}
+			ex.FailNext(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+			if _, err := b.GetOrderBooks(ctx, ticker); err == nil {
+				t.Fatalf("the order books should fail after 3 attempts")
+			}
+			ex.FailNext(http.StatusUnauthorized)
+			if _, err := b.GetOrderBooks(ctx, ticker); err == nil {
+				t.Fatalf("an authentication error should not be retried")
+			}
+
+			// An order is sent once, whether the exchange processed it or not is unknown
+			ex.FailNext(http.StatusServiceUnavailable)
+			if _, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(50)); err == nil {
+				t.Fatalf("the order should fail")
+			}
+			if orders := ex.Orders(); len(orders) != 0 {
+				t.Fatalf("the order should not have been sent again: %+v", orders)
+			}
+		})
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+				Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+				Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+			})
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+
+			// The deadline of the caller cancels the requests to a slow exchange
+			ex.SetLatency(10 * time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			if _, err := b.GetOrderBooks(ctx, ticker); err == nil {
+				t.Fatalf("the order books should not be fetched past the deadline")
+			}
+			if _, err := b.GetBalance(ctx); err == nil {
+				t.Fatalf("the balance should not be fetched past the deadline")
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("the requests should have been cancelled, they took %v", elapsed)
+			}
+		})
+	}
+}
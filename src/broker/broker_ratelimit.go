@@ -0,0 +1,122 @@
+package broker
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+)
+
+// binanceRateLimits are the limits of the Binance spot API: 6000 of weight per minute on /api, 12000 per minute
+// on /sapi, and 50 orders per 10 seconds. Binance reports the weight used in the headers of every response.
+var binanceRateLimits = httpclient.RateLimits{
+	Rules: []httpclient.RateLimitRule{
+		{PathPrefix: "/api/", Weighted: true, Limit: 6000, Interval: time.Minute, Remaining: httpclient.UsedWeightHeader("X-Mbx-Used-Weight-1m", 6000)},
+		{PathPrefix: "/sapi/", Weighted: true, Limit: 12000, Interval: time.Minute, Remaining: httpclient.UsedWeightHeader("X-Sapi-Used-Ip-Weight-1m", 12000)},
+		{Method: http.MethodPost, PathPrefix: "/api/v3/order", Limit: 50, Interval: 10 * time.Second},
+	},
+	Weight: binanceWeight,
+}
+
+// binanceWeight returns the weight of a request, as documented by Binance
+func binanceWeight(req *http.Request) int {
+	query := req.URL.Query()
+	switch req.URL.Path {
+	case "/api/v3/depth":
+		limit, err := strconv.Atoi(query.Get("limit"))
+		switch {
+		case err != nil || limit <= 100:
+			return 5
+		case limit <= 500:
+			return 25
+		case limit <= 1000:
+			return 50
+		default:
+			return 250
+		}
+	case "/api/v3/ticker/bookTicker":
+		if query.Has("symbol") {
+			return 2
+		}
+		return 4
+	case "/api/v3/openOrders":
+		if req.Method == http.MethodGet && !query.Has("symbol") {
+			return 80
+		}
+		return 6
+	case "/api/v3/order":
+		if req.Method == http.MethodGet {
+			return 4
+		}
+		return 1
+	case "/api/v3/exchangeInfo", "/api/v3/account", "/api/v3/myTrades":
+		return 20
+	case "/sapi/v1/capital/config/getall", "/sapi/v1/capital/deposit/address", "/sapi/v1/capital/deposit/hisrec":
+		return 10
+	}
+	return 1
+}
+
+// mexcRateLimits are the limits of the MEXC spot API: 500 of weight per 10 seconds for each endpoint
+var mexcRateLimits = httpclient.RateLimits{
+	Rules: []httpclient.RateLimitRule{
+		{PathPrefix: "/api/v3/", PerPath: true, Weighted: true, Limit: 500, Interval: 10 * time.Second},
+	},
+	Weight: mexcWeight,
+}
+
+// mexcWeight returns the weight of a request, as documented by MEXC
+func mexcWeight(req *http.Request) int {
+	switch req.URL.Path {
+	case "/api/v3/exchangeInfo", "/api/v3/account", "/api/v3/myTrades", "/api/v3/capital/config/getall":
+		return 10
+	case "/api/v3/openOrders":
+		return 3
+	case "/api/v3/order":
+		if req.Method == http.MethodGet {
+			return 2
+		}
+	}
+	return 1
+}
+
+// gateRateLimits are the limits of the Gate API v4: 200 requests per 10 seconds for each endpoint, and 10 orders
+// per second. Gate reports the requests left in the headers of every response.
+var gateRateLimits = httpclient.RateLimits{
+	Rules: []httpclient.RateLimitRule{
+		{PathPrefix: "/api/v4/", PerPath: true, Limit: 200, Interval: 10 * time.Second, Remaining: httpclient.RemainingHeader("X-Gate-Ratelimit-Requests-Remain")},
+		{Method: http.MethodPost, PathPrefix: "/api/v4/spot/orders", Limit: 10, Interval: time.Second},
+	},
+}
+
+// xtRateLimits are the limits of the XT API v4: 10 requests per second for each endpoint, the orders being
+// limited to 50 per second
+var xtRateLimits = httpclient.RateLimits{
+	Rules: []httpclient.RateLimitRule{
+		{Method: http.MethodGet, PathPrefix: "/v4/", PerPath: true, Limit: 10, Interval: time.Second},
+		{Method: http.MethodDelete, PathPrefix: "/v4/", PerPath: true, Limit: 10, Interval: time.Second},
+		{Method: http.MethodPost, PathPrefix: "/v4/order", Limit: 50, Interval: time.Second},
+		{Method: http.MethodPost, PathPrefix: "/v4/withdraw", Limit: 10, Interval: time.Second},
+	},
+}
+
+// bitrueRateLimits are the limits of the Bitrue spot API: 1200 of weight per minute, and 100 orders per 10 seconds
+var bitrueRateLimits = httpclient.RateLimits{
+	Rules: []httpclient.RateLimitRule{
+		{Weighted: true, Limit: 1200, Interval: time.Minute},
+		{Method: http.MethodPost, PathPrefix: "/api/v1/order", Limit: 100, Interval: 10 * time.Second},
+	},
+	Weight: bitrueWeight,
+}
+
+// bitrueWeight returns the weight of a request, as documented by Bitrue
+func bitrueWeight(req *http.Request) int {
+	switch req.URL.Path {
+	case "/api/v1/exchangeInfo", "/api/v1/account", "/api/v2/myTrades":
+		return 5
+	case "/api/v1/openOrders":
+		return 3
+	}
+	return 1
+}
@@ -0,0 +1,179 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/secrets"
+)
+
+// Factory builds the broker of an exchange from its configuration, e.g. NewBinance
+type Factory func(config Config) (IBroker, error)
+
+// Registry builds the brokers of the exchanges registered in it, which are named as in the exchanges table
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// DefaultRegistry holds the brokers of this package, each one registering itself
+var DefaultRegistry = NewRegistry()
+
+// Register adds the factory of exchange, it panics when the exchange is already registered
+func (r *Registry) Register(exchange string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[exchange]; ok {
+		panic(fmt.Sprintf("the broker of %v is already registered", exchange))
+	}
+	r.factories[exchange] = factory
+}
+
+// Exchanges returns the registered exchanges, sorted
+func (r *Registry) Exchanges() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	exchanges := make([]string, 0, len(r.factories))
+	for exchange := range r.factories {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	return exchanges
+}
+
+// New builds the broker of exchange, named after the exchange unless config.InternalName is set
+func (r *Registry) New(exchange string, config Config) (IBroker, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[exchange]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no broker is registered for %v, the registered exchanges are %v", exchange, strings.Join(r.Exchanges(), ", "))
+	}
+
+	if config.InternalName == "" {
+		config.InternalName = exchange
+	}
+	b, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the broker %v: %w", config.InternalName, err)
+	}
+	return b, nil
+}
+
+// Account is a broker built from the configuration of one of the accounts of an exchange
+type Account struct {
+	Exchange string
+	Config   Config
+	Broker   IBroker
+}
+
+// Load builds the brokers of every account of config, sorted by exchange, failing on the first one which cannot
+// be built. The accounts of an exchange having several of them must be given distinct names, all of them sharing
+// the market data of the first one through an AccountBroker.
+func (r *Registry) Load(config BrokersConfig) ([]Account, error) {
+	exchanges := make([]string, 0, len(config))
+	for exchange := range config {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+
+	var accounts []Account
+	names := make(map[string]string)
+	for _, exchange := range exchanges {
+		var market IBroker
+		for _, c := range config[exchange] {
+			if c.InternalName == "" && len(config[exchange]) > 1 {
+				return nil, fmt.Errorf("the accounts of %v must be named by their InternalName", exchange)
+			}
+			b, err := r.New(exchange, c)
+			if err != nil {
+				return nil, err
+			}
+			if other, ok := names[b.GetBrokerName()]; ok {
+				return nil, fmt.Errorf("the broker name %v is used by both %v and %v", b.GetBrokerName(), other, exchange)
+			}
+			names[b.GetBrokerName()] = exchange
+
+			c.InternalName = b.GetBrokerName()
+			if market == nil {
+				market = b
+			} else {
+				b = NewAccountBroker(b, market)
+			}
+			accounts = append(accounts, Account{Exchange: exchange, Config: c, Broker: b})
+		}
+	}
+	return accounts, nil
+}
+
+// Validate checks that a broker is registered for each of exchanges, as named in the exchanges table
+func (r *Registry) Validate(exchanges []string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var unknown []string
+	for _, exchange := range exchanges {
+		if _, ok := r.factories[exchange]; !ok {
+			unknown = append(unknown, exchange)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("no broker is registered for the exchanges %v", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// BrokersConfig lists the brokers to enable with the configuration of each of their accounts, keyed by exchange.
+// An exchange having a single account may be given its configuration instead of a list.
+type BrokersConfig map[string][]Config
+
+func (c *BrokersConfig) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = make(BrokersConfig, len(raw))
+	for exchange, data := range raw {
+		var configs []Config
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+			if err := json.Unmarshal(data, &configs); err != nil {
+				return fmt.Errorf("%v: %w", exchange, err)
+			}
+		} else {
+			var config Config
+			if err := json.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("%v: %w", exchange, err)
+			}
+			configs = append(configs, config)
+		}
+		(*c)[exchange] = configs
+	}
+	return nil
+}
+
+// Resolve returns the configuration with the keys and the secrets of every account looked up from provider, see
+// Config.Resolve
+func (c BrokersConfig) Resolve(ctx context.Context, provider secrets.Provider) (BrokersConfig, error) {
+	resolved := make(BrokersConfig, len(c))
+	for exchange, configs := range c {
+		for _, config := range configs {
+			config, err := config.Resolve(ctx, provider)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", exchange, err)
+			}
+			resolved[exchange] = append(resolved[exchange], config)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,111 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
+)
+
+func TestRegistry(t *testing.T) {
+	exchanges := broker.DefaultRegistry.Exchanges()
+	if strings.Join(exchanges, ",") != "Binance,Bitrue,Gate,MEXC,XT" {
+		t.Fatalf("unexpected registered exchanges: %v", exchanges)
+	}
+	if err := broker.DefaultRegistry.Validate([]string{"Binance", "Gate"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.DefaultRegistry.Validate([]string{"Binance", "Kraken"}); err == nil || !strings.Contains(err.Error(), "Kraken") {
+		t.Fatalf("an unregistered exchange should be reported: %v", err)
+	}
+
+	// An exchange is given either a configuration or a list of them, one per account
+	var config broker.BrokersConfig
+	if err := json.Unmarshal([]byte(`{
+		"Binance": {"Key": "key", "Secret": "secret", "RetryTimerHTTP": "1s"},
+		"Gate": [
+			{"InternalName": "Gate-main", "Key": "key1", "Secret": "secret1"},
+			{"InternalName": "Gate-sub", "Key": "key2", "Secret": "secret2"}
+		]
+	}`), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := broker.DefaultRegistry.Load(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, account := range accounts {
+		names = append(names, account.Exchange+"/"+account.Broker.GetBrokerName())
+	}
+	if strings.Join(names, ",") != "Binance/Binance,Gate/Gate-main,Gate/Gate-sub" {
+		t.Fatalf("unexpected accounts: %v", names)
+	}
+
+	for _, test := range []struct {
+		name   string
+		config broker.BrokersConfig
+	}{
+		{"unknown exchange", broker.BrokersConfig{"Kraken": {{}}}},
+		{"unnamed accounts", broker.BrokersConfig{"Gate": {{Key: "key1", Secret: "secret1"}, {Key: "key2", Secret: "secret2"}}}},
+		{"duplicate names", broker.BrokersConfig{"Gate": {{InternalName: "main"}}, "MEXC": {{InternalName: "main"}}}},
+		{"constructor error", broker.BrokersConfig{"XT": {{Key: "key"}}}},
+	} {
+		if _, err := broker.DefaultRegistry.Load(test.config); err == nil {
+			t.Fatalf("%v: the brokers should not be loaded", test.name)
+		}
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	// Each account of the exchange stands on its own fake, to tell where the data comes from
+	main := fakeexchange.NewBinance("key1", "secret1")
+	defer main.Close()
+	sub := fakeexchange.NewBinance("key2", "secret2")
+	defer sub.Close()
+
+	book := coin.OrderBook{
+		Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+		Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)}},
+	}
+	main.SetOrderBook("BTC", "USDT", book)
+	main.SetBalance("USDT", decimal.NewFromInt(10))
+	sub.SetBalance("USDT", decimal.NewFromInt(500))
+
+	accounts, err := broker.DefaultRegistry.Load(broker.BrokersConfig{"Binance": {
+		{InternalName: "Binance-main", Key: "key1", Secret: "secret1", HTTP: httpclient.Config{BaseURL: main.URL()}},
+		{InternalName: "Binance-sub", Key: "key2", Secret: "secret2", HTTP: httpclient.Config{BaseURL: sub.URL()}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[1].Broker.GetBrokerName() != "Binance-sub" {
+		t.Fatalf("unexpected accounts: %v", accounts)
+	}
+
+	// The market data of the sub-account is the one of the first account, its balance its own
+	ctx := context.Background()
+	b := accounts[1].Broker
+	orderbook, err := b.GetOrderBooks(ctx, database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderbook.Asks) != 1 || !orderbook.Asks[0].Price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("the order book should come from the first account: %v", orderbook)
+	}
+	balance, err := b.GetBalance(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(500)) {
+		t.Fatalf("the balance should be the one of the sub-account: %v", balance)
+	}
+}
@@ -0,0 +1,330 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// IMarketDataStreamer is implemented by the brokers able to stream their order books
+type IMarketDataStreamer interface {
+	NewMarketDataStream() *MarketDataStream
+}
+
+// depthUpdate is a diff of the order book covering the sequence numbers FirstID to LastID.
+// A level with a zero quantity is removed from the book.
+type depthUpdate struct {
+	FirstID int64
+	LastID  int64
+	Bids    []coin.Offer
+	Asks    []coin.Offer
+}
+
+// depthSnapshot is a full order book, as returned by the REST API, up to LastID
+type depthSnapshot struct {
+	LastID int64
+	Book   coin.OrderBook
+}
+
+// streamExchange holds what differs from one exchange websocket to another
+type streamExchange interface {
+	// symbol returns the name of the ticker in the messages of the exchange
+	symbol(ticker database.SelectExchangeTickersRow) string
+	// url returns the websocket endpoint for symbol
+	url(symbol string) string
+	// subscribe returns the messages to send once connected, if any
+	subscribe(symbol string) [][]byte
+	// ping returns the keep-alive message to send regularly, if any
+	ping() []byte
+	// decode parses a message, ok is false when it is not a depth update (acknowledgements, pongs, ...)
+	decode(message []byte) (update depthUpdate, ok bool, err error)
+	// snapshot fetches the order book from the REST API
+	snapshot(ctx context.Context, symbol string) (depthSnapshot, error)
+}
+
+const (
+	streamPingInterval = 20 * time.Second
+	streamRetryDelay   = time.Second
+)
+
+// MarketDataStream maintains a local order book for each subscribed ticker, from the diffs sent
+// on the websocket of the exchange. When a diff is missing, the book is rebuilt from a REST snapshot.
+type MarketDataStream struct {
+	exchange streamExchange
+	dialer   *websocket.Dialer
+
+	mu    sync.Mutex
+	books map[string]*localOrderBook
+}
+
+func newMarketDataStream(exchange streamExchange) *MarketDataStream {
+	return &MarketDataStream{
+		exchange: exchange,
+		dialer:   websocket.DefaultDialer,
+		books:    make(map[string]*localOrderBook),
+	}
+}
+
+// Subscribe starts streaming the order book of ticker until ctx is done, it does nothing if already subscribed.
+// Once ctx is done, the ticker is unsubscribed and can be subscribed again.
+func (s *MarketDataStream) Subscribe(ctx context.Context, ticker database.SelectExchangeTickersRow) {
+	symbol := s.exchange.symbol(ticker)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.books[symbol]; ok {
+		return
+	}
+	s.books[symbol] = newLocalOrderBook()
+
+	go s.run(ctx, symbol)
+}
+
+// OrderBook returns the latest order book of ticker and the time of its last update.
+// ok is false when the ticker is not subscribed or its book is being resynchronised.
+func (s *MarketDataStream) OrderBook(ticker database.SelectExchangeTickersRow) (orderbook coin.OrderBook, updatedAt time.Time, ok bool) {
+	symbol := s.exchange.symbol(ticker)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	book, found := s.books[symbol]
+	if !found || !book.synced {
+		return coin.OrderBook{}, time.Time{}, false
+	}
+	return book.orderBook(), book.updatedAt, true
+}
+
+func (s *MarketDataStream) run(ctx context.Context, symbol string) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.books, symbol)
+		s.mu.Unlock()
+	}()
+
+	for {
+		err := s.stream(ctx, symbol)
+
+		s.mu.Lock()
+		s.books[symbol].reset()
+		s.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("order book stream of %v: %v, resyncing", symbol, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamRetryDelay):
+		}
+	}
+}
+
+// stream connects to the websocket and keeps the book of symbol in sync until an error or a gap occurs
+func (s *MarketDataStream) stream(ctx context.Context, symbol string) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.exchange.url(symbol), nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for _, message := range s.exchange.subscribe(symbol) {
+		if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			return err
+		}
+	}
+
+	if s.exchange.ping() != nil {
+		go func() {
+			ticker := time.NewTicker(streamPingInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := conn.WriteMessage(websocket.TextMessage, s.exchange.ping()); err != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	// the diffs are buffered while the snapshot is fetched, so that none is missed
+	updates := make(chan depthUpdate, 1024)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(updates)
+		// the error is dropped when the stream already returned, on a failed diff or a done ctx
+		fail := func(err error) {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				fail(err)
+				return
+			}
+			update, ok, err := s.exchange.decode(message)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if !ok {
+				continue
+			}
+			select {
+			case updates <- update:
+			default:
+				fail(fmt.Errorf("too many pending updates"))
+				return
+			}
+		}
+	}()
+
+	snapshot, err := s.exchange.snapshot(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.books[symbol].load(snapshot)
+	s.mu.Unlock()
+
+	for update := range updates {
+		s.mu.Lock()
+		err := s.books[symbol].apply(update)
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// localOrderBook is the order book of a ticker, rebuilt from a snapshot and the following diffs
+type localOrderBook struct {
+	bids map[string]coin.Offer
+	asks map[string]coin.Offer
+
+	lastID    int64
+	synced    bool // a snapshot is loaded
+	continued bool // a diff followed the snapshot
+	updatedAt time.Time
+}
+
+func newLocalOrderBook() *localOrderBook {
+	return &localOrderBook{
+		bids: make(map[string]coin.Offer),
+		asks: make(map[string]coin.Offer),
+	}
+}
+
+func (b *localOrderBook) reset() {
+	b.bids = make(map[string]coin.Offer)
+	b.asks = make(map[string]coin.Offer)
+	b.lastID = 0
+	b.synced = false
+	b.continued = false
+}
+
+func (b *localOrderBook) load(snapshot depthSnapshot) {
+	b.reset()
+	setLevels(b.bids, snapshot.Book.Bids)
+	setLevels(b.asks, snapshot.Book.Asks)
+	b.lastID = snapshot.LastID
+	b.synced = true
+	b.updatedAt = time.Now()
+}
+
+// apply adds a diff to the book, it fails when the diff does not follow the previous one
+func (b *localOrderBook) apply(update depthUpdate) error {
+	if update.LastID <= b.lastID {
+		return nil
+	}
+
+	if !b.continued {
+		// the first diff must contain the one following the snapshot
+		if update.FirstID > b.lastID+1 {
+			return fmt.Errorf("gap between the snapshot %v and the update %v", b.lastID, update.FirstID)
+		}
+		b.continued = true
+	} else if update.FirstID != b.lastID+1 {
+		return fmt.Errorf("gap between the updates %v and %v", b.lastID, update.FirstID)
+	}
+
+	setLevels(b.bids, update.Bids)
+	setLevels(b.asks, update.Asks)
+	b.lastID = update.LastID
+	b.updatedAt = time.Now()
+	return nil
+}
+
+func (b *localOrderBook) orderBook() coin.OrderBook {
+	orderbook := coin.OrderBook{
+		Bids: make([]coin.Offer, 0, len(b.bids)),
+		Asks: make([]coin.Offer, 0, len(b.asks)),
+	}
+	for _, offer := range b.bids {
+		orderbook.Bids = append(orderbook.Bids, offer)
+	}
+	for _, offer := range b.asks {
+		orderbook.Asks = append(orderbook.Asks, offer)
+	}
+
+	orderbook.SortAsks()
+	orderbook.SortBids()
+
+	return orderbook
+}
+
+// setLevels sets the quantity of each price level, a zero quantity removes the level
+func setLevels(levels map[string]coin.Offer, offers []coin.Offer) {
+	for _, offer := range offers {
+		key := offer.Price.String()
+		if !offer.Quantity.GreaterThan(decimal.Zero) {
+			delete(levels, key)
+			continue
+		}
+		levels[key] = offer
+	}
+}
+
+// toOffers converts the [price, quantity] pairs sent by most exchanges
+func toOffers(levels [][]decimal.Decimal) ([]coin.Offer, error) {
+	offers := make([]coin.Offer, len(levels))
+	for i, level := range levels {
+		if len(level) < 2 {
+			return nil, fmt.Errorf("invalid price level: %v", level)
+		}
+		offers[i] = coin.Offer{
+			Price:    level[0],
+			Quantity: level[1],
+		}
+	}
+	return offers, nil
+}
@@ -0,0 +1,134 @@
+package broker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+func TestBinanceMarketDataStream(t *testing.T) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/depth", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"lastUpdateId":100,"bids":[["100","1"],["99","2"]],"asks":[["101","1"],["102","4"]]}`))
+	})
+	mux.HandleFunc("GET /ws/btcusdt@depth@100ms", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		events := []string{
+			`{"e":"depthUpdate","U":95,"u":100,"b":[["100","5"]],"a":[]}`,
+			`{"e":"depthUpdate","U":101,"u":102,"b":[["100","0"],["98","3"]],"a":[]}`,
+		}
+		if connections.Add(1) == 1 {
+			// the diffs 103 and 104 are lost, the book must be rebuilt
+			events = append(events, `{"e":"depthUpdate","U":105,"u":106,"b":[],"a":[["101","0"]]}`)
+		} else {
+			events = append(events, `{"e":"depthUpdate","U":103,"u":103,"b":[],"a":[["101","0.5"]]}`)
+		}
+		for _, event := range events {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+				return
+			}
+		}
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	binance, _ := broker.NewBinance(broker.Config{
+		InternalName: "Binance",
+		HTTP:         httpclient.Config{BaseURL: server.URL},
+		StreamURL:    "ws://" + strings.TrimPrefix(server.URL, "http://"),
+	})
+	stream := binance.(broker.IMarketDataStreamer).NewMarketDataStream()
+
+	ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+	if _, _, ok := stream.OrderBook(ticker); ok {
+		t.Fatalf("the book of an unsubscribed ticker should not be available")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream.Subscribe(ctx, ticker)
+
+	expected := coin.OrderBook{
+		Bids: []coin.Offer{
+			{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(2)},
+			{Price: decimal.NewFromInt(98), Quantity: decimal.NewFromInt(3)},
+		},
+		Asks: []coin.Offer{
+			{Price: decimal.NewFromInt(101), Quantity: decimal.RequireFromString("0.5")},
+			{Price: decimal.NewFromInt(102), Quantity: decimal.NewFromInt(4)},
+		},
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		orderbook, updatedAt, ok := stream.OrderBook(ticker)
+		if ok && sameOffers(orderbook.Bids, expected.Bids) && sameOffers(orderbook.Asks, expected.Asks) {
+			if time.Since(updatedAt) > 5*time.Second {
+				t.Fatalf("unexpected update time %v", updatedAt)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected order book %v (ok: %v)", orderbook, ok)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if connections.Load() != 2 {
+		t.Fatalf("expected a resync after the gap, got %v connections", connections.Load())
+	}
+
+	// Once its ctx is done, the ticker can be subscribed again
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		stream.Subscribe(ctx, ticker)
+		if _, _, ok := stream.OrderBook(ticker); ok && connections.Load() == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the ticker should be subscribed again, got %v connections", connections.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sameOffers compares the decimals by value, unlike coin.OrderBook.Equals
+func sameOffers(offers, expected []coin.Offer) bool {
+	if len(offers) != len(expected) {
+		return false
+	}
+	for i := range offers {
+		if !offers[i].Price.Equal(expected[i].Price) || !offers[i].Quantity.Equal(expected[i].Quantity) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/shopspring/decimal"
+)
+
+type TransferStatus string
+
+const (
+	// TransferStatusPending is the status of a transfer not yet sent on-chain
+	TransferStatusPending TransferStatus = "PENDING"
+	// TransferStatusConfirming is the status of a transfer sent on-chain, waiting for enough confirmations
+	TransferStatusConfirming TransferStatus = "CONFIRMING"
+	TransferStatusCompleted  TransferStatus = "COMPLETED"
+	TransferStatusFailed     TransferStatus = "FAILED"
+)
+
+// DepositAddress is where to send an asset on a network to deposit it on the account
+type DepositAddress struct {
+	Asset   string
+	Network string
+	Address string
+	// Memo is the tag, or payment ID, some networks require along with the address
+	Memo string
+}
+
+// Transfer is a withdrawal or a deposit, the same way for every exchange
+type Transfer struct {
+	ID      string
+	Asset   string
+	Network string
+	Address string
+	Memo    string
+
+	// Amount is the amount debited by the withdrawal, or credited by the deposit. Fee is part of it.
+	Amount decimal.Decimal
+	Fee    decimal.Decimal
+
+	Status TransferStatus
+	// TxID is the hash of the on-chain transaction, empty until it is sent
+	TxID string
+
+	CreatedAt time.Time
+}
+
+func (t Transfer) IsCompleted() bool {
+	return t.Status == TransferStatusCompleted
+}
+
+// inProgressStatus is the status of a transfer the exchange reports as processing without telling
+// whether it has been sent on-chain, which is known from its transaction ID
+func inProgressStatus(txID string) TransferStatus {
+	if txID == "" {
+		return TransferStatusPending
+	}
+	return TransferStatusConfirming
+}
+
+// errTransferNotFound is returned when a transfer is not in the recent history of the exchange
+func errTransferNotFound(kind, id string) error {
+	return fmt.Errorf("%v %v not found", kind, id)
+}
+
+// acceptedWithdrawal returns the withdrawal the exchange accepted as id, completed from its history when found
+// there. The withdrawal is sent even when its history cannot be read, its ID must then reach the caller anyway,
+// which would withdraw twice when retrying.
+func acceptedWithdrawal(ctx context.Context, getWithdrawal func(ctx context.Context, asset, network, withdrawalID string) (Transfer, error),
+	id, asset, network, address, memo string, amount decimal.Decimal) Transfer {
+	if withdrawal, err := getWithdrawal(ctx, asset, network, id); err == nil {
+		return withdrawal
+	}
+
+	return Transfer{
+		ID:        id,
+		Asset:     strings.ToUpper(asset),
+		Network:   network,
+		Address:   address,
+		Memo:      memo,
+		Amount:    amount,
+		Status:    TransferStatusPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// sameNetwork compares the network names, whose case differs from one endpoint to another
+func sameNetwork(a, b string) bool {
+	return b == "" || strings.EqualFold(a, b)
+}
+
+// TransferRoute is a network through which an asset can be withdrawn from an exchange and deposited to another
+type TransferRoute struct {
+	// Network is the canonical code of the network, the same on both exchanges
+	Network string
+	// Withdrawal and Deposit are the network as described by the source and by the destination exchange
+	Withdrawal coin.Network
+	Deposit    coin.Network
+}
+
+// PlanTransfer returns the networks through which asset can be sent from the exchange from to the exchange to,
+// the best first. There is none when no network is both withdrawable from the source and depositable to the destination.
+func PlanTransfer(ctx context.Context, from, to IBroker, asset string) ([]TransferRoute, error) {
+	withdrawals, err := from.GetNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the networks of %v: %w", from.GetBrokerName(), err)
+	}
+	deposits, err := to.GetNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the networks of %v: %w", to.GetBrokerName(), err)
+	}
+
+	return CommonNetworks(asset, withdrawals, deposits), nil
+}
+
+// CommonNetworks returns the networks of asset enabled for withdrawal in withdrawals and for deposit in deposits,
+// the networks being matched by their canonical code. They are ranked by withdrawal fee, then minimum withdrawal,
+// then number of confirmations.
+func CommonNetworks(asset string, withdrawals, deposits []coin.Network) []TransferRoute {
+	depositable := make(map[string]coin.Network)
+	for _, network := range deposits {
+		if network.DepositPossible && strings.EqualFold(network.Coin, asset) {
+			depositable[network.CanonicalCode()] = network
+		}
+	}
+
+	routes := []TransferRoute{}
+	for _, network := range withdrawals {
+		if !network.WithdrawPossible || !strings.EqualFold(network.Coin, asset) {
+			continue
+		}
+		code := network.CanonicalCode()
+		if deposit, ok := depositable[code]; ok {
+			routes = append(routes, TransferRoute{Network: code, Withdrawal: network, Deposit: deposit})
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if !a.Withdrawal.WithdrawFee.Equal(b.Withdrawal.WithdrawFee) {
+			return a.Withdrawal.WithdrawFee.LessThan(b.Withdrawal.WithdrawFee)
+		}
+		if !a.Withdrawal.WithdrawMin.Equal(b.Withdrawal.WithdrawMin) {
+			return a.Withdrawal.WithdrawMin.LessThan(b.Withdrawal.WithdrawMin)
+		}
+		return a.confirmationsRank() < b.confirmationsRank()
+	})
+
+	return routes
+}
+
+// confirmationsRank orders the routes by their number of confirmations. The one required by the destination
+// is used, or the one of the source when the destination does not tell it, an unknown number coming last.
+func (r TransferRoute) confirmationsRank() int {
+	minConfirm := r.Deposit.MinConfirm
+	if minConfirm <= 0 {
+		minConfirm = r.Withdrawal.MinConfirm
+	}
+	if minConfirm <= 0 {
+		return math.MaxInt
+	}
+	return minConfirm
+}
@@ -0,0 +1,300 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestTransfers(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetBalance("USDT", decimal.NewFromInt(100))
+			ex.SetNetwork(fakeexchange.Network{
+				Coin:           "USDT",
+				Network:        "TRX",
+				DepositEnable:  true,
+				WithdrawEnable: true,
+				WithdrawFee:    decimal.NewFromInt(1),
+				WithdrawMin:    decimal.NewFromInt(10),
+				WithdrawMax:    decimal.NewFromInt(1000),
+			})
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+
+			address, err := b.GetDepositAddress(ctx, "USDT", "TRX")
+			if exchange.name == "Bitrue" {
+				// Bitrue does not expose the deposit addresses
+				if err == nil {
+					t.Fatalf("the deposit address should not be available")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if !strings.Contains(address.Address, "usdt-trx") {
+				t.Fatalf("unexpected deposit address: %+v", address)
+			}
+
+			if _, err := b.Withdraw(ctx, "USDT", "TRX", "TAddress", "", decimal.NewFromInt(5)); err == nil {
+				t.Fatalf("a withdrawal under the minimum should fail")
+			}
+
+			withdrawal, err := b.Withdraw(ctx, "USDT", "TRX", "TAddress", "", decimal.NewFromInt(50))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if withdrawal.ID == "" || withdrawal.TxID == "" || withdrawal.Status != broker.TransferStatusConfirming {
+				t.Fatalf("unexpected withdrawal: %+v", withdrawal)
+			}
+			if !withdrawal.Amount.Equal(decimal.NewFromInt(50)) || !withdrawal.Fee.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected withdrawal amount: %v, fee %v", withdrawal.Amount, withdrawal.Fee)
+			}
+
+			if err := ex.SetTransferStatus(withdrawal.ID, fakeexchange.TransferCompleted); err != nil {
+				t.Fatal(err)
+			}
+			withdrawal, err = b.GetWithdrawal(ctx, "USDT", "TRX", withdrawal.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !withdrawal.IsCompleted() {
+				t.Fatalf("unexpected withdrawal status: %v", withdrawal.Status)
+			}
+
+			// The withdrawal is sent even when its history cannot be read, its ID must be returned. Its amount is sent
+			// exactly, as a decimal.
+			noHistory, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				MaxAttempts:  1,
+				HTTP:         httpclient.Config{BaseURL: ex.URL(), Transport: unavailableHistory{}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			withdrawal, err = noHistory.Withdraw(ctx, "USDT", "TRX", "TAddress", "", decimal.RequireFromString("20.12345678901234567"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			transfers := ex.Transfers()
+			if last := transfers[len(transfers)-1]; withdrawal.ID != last.ID || !last.Amount.Equal(decimal.RequireFromString("20.12345678901234567")) {
+				t.Fatalf("unexpected withdrawal %+v, sent as %+v", withdrawal, last)
+			}
+			if withdrawal.Status != broker.TransferStatusPending || !withdrawal.Amount.Equal(decimal.RequireFromString("20.12345678901234567")) {
+				t.Fatalf("unexpected withdrawal: %+v", withdrawal)
+			}
+
+			txID := "0xdeposit"
+			depositID := ex.Deposit("USDT", "TRX", txID, decimal.NewFromInt(20))
+			deposit, err := b.GetDeposit(ctx, "USDT", "TRX", txID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deposit.Status != broker.TransferStatusConfirming || deposit.TxID != txID || !deposit.Amount.Equal(decimal.NewFromInt(20)) {
+				t.Fatalf("unexpected deposit: %+v", deposit)
+			}
+
+			if err := ex.SetTransferStatus(depositID, fakeexchange.TransferFailed); err != nil {
+				t.Fatal(err)
+			}
+			deposit, err = b.GetDeposit(ctx, "USDT", "TRX", txID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deposit.Status != broker.TransferStatusFailed {
+				t.Fatalf("unexpected deposit status: %v", deposit.Status)
+			}
+
+			if _, err := b.GetDeposit(ctx, "USDT", "TRX", "0xunknown"); err == nil {
+				t.Fatalf("an unknown deposit should not be found")
+			}
+
+			// The deposit is found beyond the first page of the history
+			for i := 0; i < 1000; i++ {
+				ex.Deposit("USDT", "TRX", fmt.Sprintf("0xlater%v", i), decimal.NewFromInt(1))
+			}
+			deposit, err = b.GetDeposit(ctx, "USDT", "TRX", txID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deposit.TxID != txID || !deposit.Amount.Equal(decimal.NewFromInt(20)) {
+				t.Fatalf("unexpected deposit: %+v", deposit)
+			}
+		})
+	}
+}
+
+// unavailableHistory fails the requests reading the withdrawal histories
+type unavailableHistory struct{}
+
+func (unavailableHistory) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "withdraw") {
+		return nil, errors.New("the history is unavailable")
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNetworks(t *testing.T) {
+	for _, exchange := range fakeBrokers {
+		t.Run(exchange.name, func(t *testing.T) {
+			ex := exchange.newFake("key", "secret")
+			defer ex.Close()
+			ex.SetNetwork(fakeexchange.Network{
+				Coin:           "USDT",
+				Network:        "TRC20",
+				DepositEnable:  true,
+				WithdrawEnable: true,
+				WithdrawFee:    decimal.NewFromInt(1),
+				WithdrawMin:    decimal.NewFromInt(10),
+				AddressRegex:   "^T[1-9A-HJ-NP-Za-km-z]{33}$",
+			})
+			ex.SetNetwork(fakeexchange.Network{
+				Coin:           "USDT",
+				Network:        "ERC20",
+				DepositEnable:  true,
+				WithdrawEnable: false,
+				WithdrawFee:    decimal.NewFromInt(5),
+				WithdrawMin:    decimal.NewFromInt(20),
+			})
+
+			b, err := exchange.newBroker(broker.Config{
+				InternalName: exchange.name,
+				Key:          "key",
+				Secret:       "secret",
+				HTTP:         httpclient.Config{BaseURL: ex.URL()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			networks, err := b.GetNetworks(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(networks) != 2 {
+				t.Fatalf("unexpected networks: %+v", networks)
+			}
+
+			for _, network := range networks {
+				if network.Coin != "USDT" {
+					t.Fatalf("unexpected coin: %+v", network)
+				}
+				switch network.CanonicalCode() {
+				case "TRX":
+					if !network.WithdrawPossible || !network.WithdrawFee.Equal(decimal.NewFromInt(1)) {
+						t.Fatalf("unexpected TRX network: %+v", network)
+					}
+					if exchange.name == "Binance" && network.AddressRegex == "" {
+						t.Fatalf("the address regex of Binance is missing")
+					}
+				case "ETH":
+					if network.WithdrawPossible || !network.DepositPossible {
+						t.Fatalf("unexpected ETH network: %+v", network)
+					}
+				default:
+					t.Fatalf("unexpected network: %+v", network)
+				}
+			}
+		})
+	}
+}
+
+func TestGateNetworks(t *testing.T) {
+	ex := fakeexchange.NewGate("key", "secret")
+	defer ex.Close()
+	// BNB is listed in the currencies of USDT, but not in its chains as it cannot be used
+	ex.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "ETH", DepositEnable: true, WithdrawEnable: true, WithdrawFee: decimal.NewFromInt(3)})
+	ex.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "BNB", DepositEnable: true, WithdrawEnable: true, Unusable: true})
+	ex.SetNetwork(fakeexchange.Network{Coin: "BTC", Network: "BTC", DepositEnable: true, WithdrawEnable: true})
+
+	b, err := broker.NewGate(broker.Config{InternalName: "Gate", Key: "key", Secret: "secret", HTTP: httpclient.Config{BaseURL: ex.URL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, err := b.GetNetworks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 2 {
+		t.Fatalf("unexpected networks: %+v", networks)
+	}
+	for _, network := range networks {
+		if network.Code == "BNB" {
+			t.Fatalf("the unusable chain should be left out: %+v", networks)
+		}
+		if network.Coin == "USDT" && !network.WithdrawFee.Equal(decimal.NewFromInt(3)) {
+			t.Fatalf("unexpected USDT network: %+v", network)
+		}
+	}
+
+	// Once the tracked coins are known, the chains of the others are not requested
+	b.RefreshCoinsInformation(nil, broker.ExchangeCoinsMap{uuid.New(): {Base: "BTC"}}, nil)
+	networks, err = b.GetNetworks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].Coin != "BTC" || ex.Requests(http.MethodGet, "/api/v4/wallet/currency_chains") != 3 {
+		t.Fatalf("only the chains of BTC should be requested: %+v", networks)
+	}
+}
+
+func TestPlanTransfer(t *testing.T) {
+	mexc := fakeexchange.NewMEXC("key", "secret")
+	defer mexc.Close()
+	gate := fakeexchange.NewGate("key", "secret")
+	defer gate.Close()
+
+	// TRX is the cheapest network but cannot be deposited on Gate, ETH and BSC have the same fee and minimum
+	mexc.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "TRC20", DepositEnable: true, WithdrawEnable: true, WithdrawFee: decimal.NewFromInt(1), MinConfirm: 20})
+	mexc.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "ERC20", DepositEnable: true, WithdrawEnable: true, WithdrawFee: decimal.NewFromInt(3), MinConfirm: 64})
+	mexc.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "BEP20(BSC)", DepositEnable: true, WithdrawEnable: true, WithdrawFee: decimal.NewFromInt(3), MinConfirm: 15})
+	mexc.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "SOL", DepositEnable: true, WithdrawEnable: false, WithdrawFee: decimal.Zero})
+	gate.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "TRX", DepositEnable: false, WithdrawEnable: true})
+	gate.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "ETH", DepositEnable: true, WithdrawEnable: true, MinConfirm: 12})
+	gate.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "BSC", DepositEnable: true, WithdrawEnable: true, MinConfirm: 10})
+	gate.SetNetwork(fakeexchange.Network{Coin: "USDT", Network: "SOL", DepositEnable: true, WithdrawEnable: true})
+
+	from, err := broker.NewMEXC(broker.Config{InternalName: "MEXC", Key: "key", Secret: "secret", HTTP: httpclient.Config{BaseURL: mexc.URL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := broker.NewGate(broker.Config{InternalName: "Gate", Key: "key", Secret: "secret", HTTP: httpclient.Config{BaseURL: gate.URL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := broker.PlanTransfer(context.Background(), from, to, "USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 || routes[0].Network != "BSC" || routes[1].Network != "ETH" {
+		t.Fatalf("unexpected routes: %+v", routes)
+	}
+	if routes[0].Withdrawal.Code != "BEP20(BSC)" || routes[0].Deposit.Code != "BSC" {
+		t.Fatalf("the codes of the exchanges should be kept: %+v", routes[0])
+	}
+
+	if routes, err := broker.PlanTransfer(context.Background(), from, to, "BTC"); err != nil || len(routes) != 0 {
+		t.Fatalf("unexpected routes for BTC: %+v, %v", routes, err)
+	}
+}
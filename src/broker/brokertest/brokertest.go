@@ -0,0 +1,254 @@
+// Package brokertest checks that a broker honours the contract of broker.IBroker, the broker being wired to the
+// fake exchange standing in for the API of its exchange.
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
+)
+
+// Harness builds a broker and the fake exchange it is checked against
+type Harness struct {
+	Name      string
+	NewFake   func(key, secret string) *fakeexchange.Exchange
+	NewBroker broker.Factory
+}
+
+var ticker = database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+
+// Run checks every part of the contract, each one in a subtest against its own fake exchange
+func Run(t *testing.T, h Harness) {
+	t.Run("OrderBook", h.testOrderBook)
+	t.Run("SymbolStatus", h.testSymbolStatus)
+	t.Run("FillOrKill", h.testFillOrKill)
+	t.Run("ImmediateOrCancel", h.testImmediateOrCancel)
+	t.Run("Balance", h.testBalance)
+	t.Run("Errors", h.testErrors)
+}
+
+// start returns a fake exchange trading BTC_USDT around 100, and the broker wired to it with its exchange
+// information refreshed
+func (h Harness) start(t *testing.T) (*fakeexchange.Exchange, broker.IBroker) {
+	t.Helper()
+	ex := h.NewFake("key", "secret")
+	t.Cleanup(ex.Close)
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+		Asks: []coin.Offer{
+			{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)},
+			{Price: decimal.NewFromInt(102), Quantity: decimal.NewFromInt(1)},
+		},
+	})
+	ex.SetNetwork(fakeexchange.Network{
+		Coin:           "BTC",
+		Network:        "BTC",
+		DepositEnable:  true,
+		WithdrawEnable: true,
+		WithdrawFee:    decimal.RequireFromString("0.0005"),
+		WithdrawMin:    decimal.RequireFromString("0.001"),
+		WithdrawMax:    decimal.NewFromInt(100),
+	})
+
+	return ex, h.newBroker(t, ex, "secret")
+}
+
+func (h Harness) newBroker(t *testing.T, ex *fakeexchange.Exchange, secret string) broker.IBroker {
+	t.Helper()
+	b, err := h.NewBroker(broker.Config{
+		InternalName: h.Name,
+		Key:          "key",
+		Secret:       secret,
+		MaxAttempts:  1,
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == "secret" {
+		if err := b.RefreshExchangeInformation(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+// testOrderBook checks that the order books are sorted from the best offer, without any empty level
+func (h Harness) testOrderBook(t *testing.T) {
+	ex, b := h.start(t)
+	offer := func(price, quantity int64) coin.Offer {
+		return coin.Offer{Price: decimal.NewFromInt(price), Quantity: decimal.NewFromInt(quantity)}
+	}
+	// The empty levels are in the middle of the sides, as sent by the exchanges when a level is being removed
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{offer(98, 2), offer(99, 1), offer(97, 0), offer(96, 3)},
+		Asks: []coin.Offer{offer(101, 2), offer(103, 0), offer(100, 1), offer(102, 3)},
+	})
+
+	orderbook, err := b.GetOrderBooks(context.Background(), ticker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderbook.Bids) != 3 || len(orderbook.Asks) != 3 {
+		t.Fatalf("the empty levels should be dropped: %v", orderbook)
+	}
+	for side, offers := range map[string][]coin.Offer{"bids": orderbook.Bids, "asks": orderbook.Asks} {
+		for i, offer := range offers {
+			if !offer.Price.IsPositive() || !offer.Quantity.IsPositive() {
+				t.Fatalf("the %v hold an empty level: %v", side, offers)
+			}
+			if i == 0 {
+				continue
+			}
+			if side == "bids" && !offer.Price.LessThan(offers[i-1].Price) || side == "asks" && !offer.Price.GreaterThan(offers[i-1].Price) {
+				t.Fatalf("the %v are not sorted from the best offer: %v", side, offers)
+			}
+		}
+	}
+	if !orderbook.Bids[0].Price.Equal(decimal.NewFromInt(99)) || !orderbook.Asks[0].Price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("unexpected best offers: %v", orderbook)
+	}
+}
+
+// testSymbolStatus checks that a symbol is only traded and transferred when the exchange allows it
+func (h Harness) testSymbolStatus(t *testing.T) {
+	ex, b := h.start(t)
+	ctx := context.Background()
+
+	if err := b.CanBuyAndWithdraw(ctx, ticker); err != nil {
+		t.Fatalf("BTC can be bought and withdrawn: %v", err)
+	}
+	if err := b.CanDepositAndSell(ctx, ticker); err != nil {
+		t.Fatalf("BTC can be deposited and sold: %v", err)
+	}
+	unknown := database.SelectExchangeTickersRow{Base: "NOPE", Quote: "USDT"}
+	if err := b.CanBuyAndWithdraw(ctx, unknown); err == nil {
+		t.Fatalf("an unknown symbol cannot be bought")
+	}
+
+	// No network is open to transfer BTC anymore, as known once the exchange information is refreshed
+	ex.SetNetwork(fakeexchange.Network{Coin: "BTC", Network: "BTC"})
+	if err := b.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CanBuyAndWithdraw(ctx, ticker); !errors.Is(err, broker.ErrNetworkDisabled) {
+		t.Fatalf("BTC cannot be withdrawn: %v", err)
+	}
+	if err := b.CanDepositAndSell(ctx, ticker); !errors.Is(err, broker.ErrNetworkDisabled) {
+		t.Fatalf("BTC cannot be deposited: %v", err)
+	}
+
+	ex.SetTradable("BTC", "USDT", false)
+	if err := b.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CanBuyAndWithdraw(ctx, ticker); !errors.Is(err, broker.ErrSymbolNotTrading) {
+		t.Fatalf("a halted symbol cannot be bought: %v", err)
+	}
+	if err := b.CanDepositAndSell(ctx, ticker); !errors.Is(err, broker.ErrSymbolNotTrading) {
+		t.Fatalf("a halted symbol cannot be sold: %v", err)
+	}
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+	if _, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(50)); !errors.Is(err, broker.ErrSymbolNotTrading) {
+		t.Fatalf("a halted symbol cannot be bought: %v", err)
+	}
+}
+
+// testFillOrKill checks that a purchase is either filled entirely or not at all
+func (h Harness) testFillOrKill(t *testing.T) {
+	ex, b := h.start(t)
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+	ctx := context.Background()
+
+	// 2 BTC at 100 at most, only 1 is available under this price
+	result, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(200))
+	if err == nil || result.IsFilled() || !result.FilledQuantity.IsZero() {
+		t.Fatalf("the purchase should be killed: %+v, %v", result, err)
+	}
+	if !ex.Balance("USDT").Equal(decimal.NewFromInt(1000)) || !ex.Balance("BTC").IsZero() {
+		t.Fatalf("a killed purchase should not move any fund: %v USDT, %v BTC", ex.Balance("USDT"), ex.Balance("BTC"))
+	}
+
+	result, err = b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsFilled() || !result.FilledQuantity.Equal(decimal.NewFromInt(1)) || !result.FilledQuoteQuantity.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("the purchase should be filled: %+v", result)
+	}
+	if !ex.Balance("BTC").Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("unexpected BTC balance: %v", ex.Balance("BTC"))
+	}
+}
+
+// testImmediateOrCancel checks that a sale is filled as much as possible, the rest being cancelled
+func (h Harness) testImmediateOrCancel(t *testing.T) {
+	ex, b := h.start(t)
+	ex.SetBalance("BTC", decimal.NewFromInt(2))
+	ctx := context.Background()
+
+	// Only 1 of the 2 BTC can be sold at 99 or more
+	result, err := b.Sell(ctx, ticker, decimal.NewFromInt(99), decimal.NewFromInt(198))
+	if err == nil || result.IsFilled() {
+		t.Fatalf("the sale should only be filled partially: %+v, %v", result, err)
+	}
+	if !result.FilledQuantity.Equal(decimal.NewFromInt(1)) || !result.FilledQuoteQuantity.Equal(decimal.NewFromInt(99)) {
+		t.Fatalf("unexpected filled quantities: %v BTC for %v USDT", result.FilledQuantity, result.FilledQuoteQuantity)
+	}
+
+	openOrders, err := b.ListOpenOrders(ctx, ticker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openOrders) != 0 || !ex.Balance("BTC").Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("the rest of the sale should be cancelled: %+v, %v BTC", openOrders, ex.Balance("BTC"))
+	}
+}
+
+// testBalance checks that the balances are keyed by the upper-case assets, with the free quantities
+func (h Harness) testBalance(t *testing.T) {
+	ex, b := h.start(t)
+	ex.SetBalance("USDT", decimal.RequireFromString("1000.5"))
+	ex.SetBalance("BTC", decimal.RequireFromString("0.25"))
+
+	balance, err := b.GetBalance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for asset, b := range balance {
+		if asset != strings.ToUpper(asset) || b.Quantity.IsNegative() {
+			t.Fatalf("unexpected balance of %v: %v", asset, b.Quantity)
+		}
+	}
+	if !balance["USDT"].Quantity.Equal(decimal.RequireFromString("1000.5")) || !balance["BTC"].Quantity.Equal(decimal.RequireFromString("0.25")) {
+		t.Fatalf("unexpected balances: %v", balance)
+	}
+}
+
+// testErrors checks that the errors of the exchange are classified, with its raw code and message
+func (h Harness) testErrors(t *testing.T) {
+	ex, b := h.start(t)
+	ex.SetBalance("USDT", decimal.NewFromInt(10))
+	ctx := context.Background()
+
+	_, err := b.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(50))
+	if !errors.Is(err, broker.ErrInsufficientBalance) {
+		t.Fatalf("expected an insufficient balance: %v", err)
+	}
+	var exchangeErr *broker.ExchangeError
+	if !errors.As(err, &exchangeErr) || exchangeErr.Exchange != h.Name || exchangeErr.Message == "" {
+		t.Fatalf("the error should hold the message of the exchange: %#v", err)
+	}
+
+	if _, err := h.newBroker(t, ex, "wrong").GetBalance(ctx); !errors.Is(err, broker.ErrInvalidSignature) {
+		t.Fatalf("expected an invalid signature: %v", err)
+	}
+}
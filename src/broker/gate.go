@@ -3,7 +3,10 @@ package broker
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
@@ -13,31 +16,65 @@ import (
 )
 
 type Gate struct {
-	config Config
+	config     Config
+	httpClient *http.Client
 
 	coins           CoinsMap
 	exchangeCoins   ExchangeCoinsMap
 	exchangeTickers ExchangeTickersMap
 
-	tickersStatus map[string]coin.TickerStatus
-	accountStatus AccountStatus
+	tickersStatus  map[string]coin.TickerStatus
+	tickersFilters map[string]coin.TickerFilters
+	accountStatus  AccountStatus
+}
+
+func init() {
+	DefaultRegistry.Register("Gate", NewGate)
 }
 
 func NewGate(config Config) (IBroker, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config.HTTP = config.httpConfig(gateRateLimits)
+
 	return &Gate{
-		config:        config,
-		tickersStatus: make(map[string]coin.TickerStatus),
-		accountStatus: NewAccountStatus(false),
+		config:         config,
+		httpClient:     config.HTTP.HTTPClient(),
+		tickersStatus:  make(map[string]coin.TickerStatus),
+		tickersFilters: make(map[string]coin.TickerFilters),
+		accountStatus:  NewAccountStatus(false),
 	}, nil
 }
 
 func (b Gate) GetBrokerName() string { return b.config.InternalName }
 
+// Format prints the broker as its redacted configuration, its clients holding the key being left out
+func (b Gate) Format(f fmt.State, verb rune) { formatBroker(f, verb, "Gate", b.config) }
+
+// newConfiguration returns the SDK configuration targeting the endpoint set in b.config.HTTP
+func (b Gate) newConfiguration() *gateapi.Configuration {
+	config := gateapi.NewConfiguration()
+	if b.config.HTTP.BaseURL != "" {
+		config.BasePath = b.config.HTTP.BaseURL + "/api/v4"
+	}
+	config.HTTPClient = b.httpClient
+	return config
+}
+
+// newSignedClient returns a client authenticated with the keys of the account
+func (b Gate) newSignedClient() *gateapi.APIClient {
+	config := b.newConfiguration()
+	config.Key = b.config.Key
+	config.Secret = b.config.Secret
+	return gateapi.NewAPIClient(config)
+}
+
 func (b Gate) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
-	client := gateapi.NewAPIClient(gateapi.NewConfiguration())
+	client := gateapi.NewAPIClient(b.newConfiguration())
 	tickers, _, err := client.SpotApi.ListTickers(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, gateError(err)
 	}
 
 	tickersInfo := make(map[coin.TickerPair]CoinAllInfo)
@@ -94,20 +131,20 @@ func (b Gate) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]Co
 func (b Gate) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
 	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
 
-	client := gateapi.NewAPIClient(gateapi.NewConfiguration())
+	client := gateapi.NewAPIClient(b.newConfiguration())
 	orders, _, err := client.SpotApi.ListOrderBook(ctx, currencyPair, &gateapi.ListOrderBookOpts{
 		Limit: optional.NewInt32(50),
 	})
 	if err != nil {
-		return coin.OrderBook{}, err
+		return coin.OrderBook{}, gateError(err)
 	}
 
 	orderbook := coin.OrderBook{
-		Bids: make([]coin.Offer, len(orders.Bids)),
-		Asks: make([]coin.Offer, len(orders.Asks)),
+		Bids: make([]coin.Offer, 0, len(orders.Bids)),
+		Asks: make([]coin.Offer, 0, len(orders.Asks)),
 	}
 
-	for i, bid := range orders.Bids {
+	for _, bid := range orders.Bids {
 		price, err := decimal.NewFromString(bid[0])
 		if err != nil {
 			return orderbook, err
@@ -116,16 +153,16 @@ func (b Gate) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeT
 		if err != nil {
 			return orderbook, err
 		}
-		if quantity.Equal(decimal.Zero) {
-			break
+		if !quantity.GreaterThan(decimal.Zero) {
+			continue
 		}
-		orderbook.Bids[i] = coin.Offer{
+		orderbook.Bids = append(orderbook.Bids, coin.Offer{
 			Price:    price,
 			Quantity: quantity,
-		}
+		})
 	}
 
-	for i, ask := range orders.Asks {
+	for _, ask := range orders.Asks {
 		price, err := decimal.NewFromString(ask[0])
 		if err != nil {
 			return orderbook, err
@@ -135,12 +172,12 @@ func (b Gate) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeT
 			return orderbook, err
 		}
 		if !quantity.GreaterThan(decimal.Zero) {
-			break
+			continue
 		}
-		orderbook.Asks[i] = coin.Offer{
+		orderbook.Asks = append(orderbook.Asks, coin.Offer{
 			Price:    price,
 			Quantity: quantity,
-		}
+		})
 	}
 
 	orderbook.SortAsks()
@@ -150,13 +187,13 @@ func (b Gate) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeT
 }
 
 func (b Gate) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
-	config := gateapi.NewConfiguration()
+	config := b.newConfiguration()
 	config.Key = b.config.Key
 	config.Secret = b.config.Secret
 	client := gateapi.NewAPIClient(config)
 	coins, _, err := client.SpotApi.ListSpotAccounts(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, gateError(err)
 	}
 
 	if len(coins) == 0 {
@@ -207,6 +244,100 @@ func (b Gate) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance
 	return balance, nil
 }
 
+func (b Gate) GetFees() FeeSchedule {
+	return b.config.Fees
+}
+
+// GetNetworks lists the networks from the chains of each coin. The currencies, suffixed with their chain when they
+// have several, e.g. USDT_ALGO, only give the coins: they list chains that cannot be used, e.g. BNB for USDT, and
+// withdrawing on one of them would lose the funds. The chains being listed one coin at a time, only the coins
+// tracked on Gate are, once known from RefreshCoinsInformation.
+func (b Gate) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	client := b.newSignedClient()
+	currencies, _, err := client.SpotApi.ListCurrencies(ctx)
+	if err != nil {
+		return nil, gateError(err)
+	}
+
+	withdrawStatuses, _, err := client.WalletApi.ListWithdrawStatus(ctx, nil)
+	if err != nil {
+		return nil, gateError(err)
+	}
+	fees := make(map[string]gateapi.WithdrawStatus)
+	for _, status := range withdrawStatuses {
+		fees[strings.ToUpper(status.Currency)] = status
+	}
+
+	tracked := make(map[string]bool)
+	for _, exchangeCoin := range b.exchangeCoins {
+		tracked[strings.ToUpper(exchangeCoin.Base)] = true
+	}
+
+	// The currencies of each coin, keyed by their chain, tell whether its withdrawals are delayed
+	var coins []string
+	listed := make(map[string]map[string]gateapi.Currency)
+	for _, currency := range currencies {
+		if currency.Delisted || currency.Chain == "" {
+			continue
+		}
+
+		coinBase := strings.ToUpper(currency.Currency)
+		parts := strings.Split(coinBase, "_")
+		if len(parts) == 2 && parts[1] == strings.ToUpper(currency.Chain) {
+			coinBase = parts[0]
+		}
+		if len(tracked) > 0 && !tracked[coinBase] {
+			continue
+		}
+
+		if _, ok := listed[coinBase]; !ok {
+			listed[coinBase] = make(map[string]gateapi.Currency)
+			coins = append(coins, coinBase)
+		}
+		listed[coinBase][strings.ToUpper(currency.Chain)] = currency
+	}
+
+	networks := []coin.Network{}
+	for _, coinBase := range coins {
+		chains, _, err := client.WalletApi.ListCurrencyChains(ctx, coinBase)
+		if err != nil {
+			return nil, gateError(err)
+		}
+
+		for _, chain := range chains {
+			currency := listed[coinBase][strings.ToUpper(chain.Chain)]
+			network := coin.Network{
+				Coin:             coinBase,
+				Code:             chain.Chain,
+				Name:             chain.Chain,
+				DepositPossible:  chain.IsDisabled == 0 && chain.IsDepositDisabled == 0,
+				WithdrawPossible: chain.IsDisabled == 0 && chain.IsWithdrawDisabled == 0 && !currency.WithdrawDelayed,
+				WithdrawFee:      decimal.Zero,
+				WithdrawMin:      decimal.Zero,
+			}
+			if status, ok := fees[coinBase]; ok {
+				fee := status.WithdrawFix
+				if onChain, ok := status.WithdrawFixOnChains[chain.Chain]; ok {
+					fee = onChain
+				}
+				if fee != "" {
+					if network.WithdrawFee, err = decimal.NewFromString(fee); err != nil {
+						return nil, fmt.Errorf("invalid withdraw fee of %v on %v: %v", coinBase, chain.Chain, err)
+					}
+				}
+				if status.WithdrawAmountMini != "" {
+					if network.WithdrawMin, err = decimal.NewFromString(status.WithdrawAmountMini); err != nil {
+						return nil, fmt.Errorf("invalid withdraw minimum of %v: %v", coinBase, err)
+					}
+				}
+			}
+			networks = append(networks, network)
+		}
+	}
+
+	return networks, nil
+}
+
 func (b *Gate) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
 	b.coins = coins
 	b.exchangeCoins = exchangeCoins
@@ -214,14 +345,14 @@ func (b *Gate) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoi
 }
 
 func (b *Gate) RefreshExchangeInformation(ctx context.Context) error {
-	config := gateapi.NewConfiguration()
+	config := b.newConfiguration()
 	config.Key = b.config.Key
 	config.Secret = b.config.Secret
 	client := gateapi.NewAPIClient(config)
 
 	respAccount, _, err := client.AccountApi.GetAccountDetail(ctx)
 	if err != nil {
-		return err
+		return gateError(err)
 	}
 
 	b.accountStatus.CanDeposit = respAccount.Key.Mode == 1
@@ -231,7 +362,7 @@ func (b *Gate) RefreshExchangeInformation(ctx context.Context) error {
 
 	respTickers, _, err := client.SpotApi.ListCurrencyPairs(ctx)
 	if err != nil {
-		return err
+		return gateError(err)
 	}
 
 	// untradable: cannot be bought or sold - buyable: can be bought - sellable: can be sold - tradable: can be bought or sold
@@ -244,6 +375,12 @@ func (b *Gate) RefreshExchangeInformation(ctx context.Context) error {
 			IsBuyable:            tradableStatus == "BUYABLE" || tradableStatus == "TRADABLE",
 			IsSellable:           tradableStatus == "SELLABLE" || tradableStatus == "TRADABLE",
 		}
+
+		filters, err := gateFilters(ticker)
+		if err != nil {
+			return fmt.Errorf("%v: %v", ticker.Id, err)
+		}
+		b.tickersFilters[ticker.Id] = filters
 	}
 
 	// **CAREFULL**
@@ -258,60 +395,321 @@ func (b *Gate) RefreshExchangeInformation(ctx context.Context) error {
 	return nil
 }
 
-func (b *Gate) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error {
-	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+// gateFilters reads the precisions and the limits of a currency pair, the prices and the amounts being given with
+// precision and amount_precision decimals
+func gateFilters(pair gateapi.CurrencyPair) (coin.TickerFilters, error) {
+	filters := coin.TickerFilters{
+		TickSize: coin.StepFromPrecision(int(pair.Precision)),
+		StepSize: coin.StepFromPrecision(int(pair.AmountPrecision)),
+	}
 
-	config := gateapi.NewConfiguration()
-	config.Key = b.config.Key
-	config.Secret = b.config.Secret
-	client := gateapi.NewAPIClient(config)
+	var err error
+	if filters.MinQuantity, err = decimalOrZero(pair.MinBaseAmount); err != nil {
+		return coin.TickerFilters{}, fmt.Errorf("invalid minimum base amount: %v", err)
+	}
+	if filters.MaxQuantity, err = decimalOrZero(pair.MaxBaseAmount); err != nil {
+		return coin.TickerFilters{}, fmt.Errorf("invalid maximum base amount: %v", err)
+	}
+	if filters.MinNotional, err = decimalOrZero(pair.MinQuoteAmount); err != nil {
+		return coin.TickerFilters{}, fmt.Errorf("invalid minimum quote amount: %v", err)
+	}
+	return filters, nil
+}
 
-	quoteQuantityReal := quoteQuantity.Div(maxPrice)
+func (b Gate) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return tickerFilters(b.tickersFilters, strings.ToUpper(ticker.Base)+"_"+strings.ToUpper(ticker.Quote))
+}
+
+func (b *Gate) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, "buy", "fok", maxPrice, quoteQuantity)
+}
 
-	_, _, err := client.SpotApi.CreateOrder(ctx, gateapi.Order{
+func (b *Gate) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, "sell", "ioc", minPrice, quoteQuantity)
+}
+
+// placeOrder sets a limit order, whose result is given by the answer of the creation as IOC and FOK orders are finished at once
+func (b *Gate) placeOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, side, timeInForce string, price, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+	filters, err := tickerFilters(b.tickersFilters, currencyPair)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	price, quantity, err := normalizeOrder(filters, side == "buy", price, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("%v: %w", currencyPair, err)
+	}
+
+	client := b.newSignedClient()
+
+	order, _, err := client.SpotApi.CreateOrder(ctx, gateapi.Order{
+		// the custom IDs must start with "t-" and be at most 28 characters long
+		Text:         "t-" + newClientOrderID(),
 		Account:      "spot",
 		CurrencyPair: currencyPair,
-		Side:         "buy",
+		Side:         side,
 		Type:         "limit",
-		Amount:       quoteQuantityReal.String(),
-		Price:        maxPrice.String(),
-		TimeInForce:  "fok",
+		Amount:       quantity.String(),
+		Price:        price.String(),
+		TimeInForce:  timeInForce,
 	})
 	if err != nil {
-		return err
+		return OrderResult{}, gateError(err)
 	}
 
-	return nil
+	result, err := gateOrderResult(order)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.IsFilled() {
+		return result, fmt.Errorf("the %v order has not been filled", side)
+	}
+	return result, nil
 }
 
-func (b *Gate) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error {
+func (b *Gate) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
 	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
 
-	config := gateapi.NewConfiguration()
-	config.Key = b.config.Key
-	config.Secret = b.config.Secret
-	client := gateapi.NewAPIClient(config)
+	order, _, err := b.newSignedClient().SpotApi.GetOrder(ctx, orderID, currencyPair, nil)
+	if err != nil {
+		return OrderResult{}, gateError(err)
+	}
 
-	quoteQuantityReal := quoteQuantity.Div(minPrice)
+	return gateOrderResult(order)
+}
 
-	sellOrder, _, err := client.SpotApi.CreateOrder(ctx, gateapi.Order{
-		Account:      "spot",
-		CurrencyPair: currencyPair,
-		Side:         "sell",
-		Type:         "limit",
-		Amount:       quoteQuantityReal.String(),
-		Price:        minPrice.String(),
-		TimeInForce:  "ioc",
+func (b *Gate) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+
+	_, _, err := b.newSignedClient().SpotApi.CancelOrder(ctx, orderID, currencyPair, nil)
+	return gateError(err)
+}
+
+func (b *Gate) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+
+	// without the account, the margin orders would be cancelled too
+	_, _, err := b.newSignedClient().SpotApi.CancelOrders(ctx, currencyPair, &gateapi.CancelOrdersOpts{
+		Account: optional.NewString("spot"),
+	})
+	return gateError(err)
+}
+
+func (b *Gate) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	currencyPair := strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+
+	openOrders, _, err := b.newSignedClient().SpotApi.ListOrders(ctx, currencyPair, "open", &gateapi.ListOrdersOpts{
+		Account: optional.NewString("spot"),
 	})
 	if err != nil {
-		return err
+		return nil, gateError(err)
+	}
+
+	results := make([]OrderResult, 0, len(openOrders))
+	for _, order := range openOrders {
+		result, err := gateOrderResult(order)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
 	}
+	return results, nil
+}
 
-	if strings.ToUpper(sellOrder.Status) != "CLOSED" {
-		return fmt.Errorf("the sell order has not been filled")
+// gateOrderResult converts an order, whose status is only open, closed or cancelled, the reason being in finish_as
+func gateOrderResult(order gateapi.Order) (OrderResult, error) {
+	result := OrderResult{
+		OrderID:       order.Id,
+		ClientOrderID: order.Text,
+		CreatedAt:     millisToTime(order.CreateTimeMs),
+		UpdatedAt:     millisToTime(order.UpdateTimeMs),
 	}
 
-	return nil
+	parse := func(value string) (decimal.Decimal, error) {
+		if value == "" {
+			return decimal.Zero, nil
+		}
+		return decimal.NewFromString(value)
+	}
+
+	filledAmount, err := parse(order.FilledAmount)
+	if err != nil {
+		return result, fmt.Errorf("invalid filled amount: %v", err)
+	}
+	filledTotal, err := parse(order.FilledTotal)
+	if err != nil {
+		return result, fmt.Errorf("invalid filled total: %v", err)
+	}
+	left, err := parse(order.Left)
+	if err != nil {
+		return result, fmt.Errorf("invalid left amount: %v", err)
+	}
+	fee, err := parse(order.Fee)
+	if err != nil {
+		return result, fmt.Errorf("invalid fee: %v", err)
+	}
+	result.setFilled(filledAmount, filledTotal)
+	result.addFee(fee, order.FeeCurrency)
+
+	switch {
+	case strings.ToLower(order.Status) == "open" && filledAmount.IsZero():
+		result.Status = OrderStatusNew
+	case strings.ToLower(order.Status) == "open":
+		result.Status = OrderStatusPartiallyFilled
+	case strings.ToLower(order.FinishAs) == "filled" || left.IsZero():
+		result.Status = OrderStatusFilled
+	case strings.ToLower(order.FinishAs) == "ioc" || strings.ToLower(order.FinishAs) == "fok":
+		result.Status = OrderStatusExpired
+	default:
+		result.Status = OrderStatusCanceled
+	}
+
+	return result, nil
+}
+
+func (b *Gate) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	address, _, err := b.newSignedClient().WalletApi.GetDepositAddress(ctx, strings.ToUpper(asset))
+	if err != nil {
+		return DepositAddress{}, gateError(err)
+	}
+
+	for _, item := range address.MultichainAddresses {
+		if sameNetwork(item.Chain, network) && item.ObtainFailed == 0 {
+			return DepositAddress{
+				Asset:   address.Currency,
+				Network: item.Chain,
+				Address: item.Address,
+				Memo:    item.PaymentId,
+			}, nil
+		}
+	}
+	return DepositAddress{}, fmt.Errorf("no deposit address for %v on %v", asset, network)
+}
+
+func (b *Gate) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	withdrawal, _, err := b.newSignedClient().WithdrawalApi.Withdraw(ctx, gateapi.LedgerRecord{
+		Currency: strings.ToUpper(asset),
+		Chain:    network,
+		Address:  address,
+		Memo:     memo,
+		Amount:   amount.String(),
+	})
+	if err != nil {
+		return Transfer{}, gateError(err)
+	}
+
+	// the withdrawal returned does not have its fee
+	return acceptedWithdrawal(ctx, b.GetWithdrawal, withdrawal.Id, asset, network, address, memo, amount), nil
+}
+
+// gateHistoryLimit is the largest page of the withdrawal and deposit histories
+const gateHistoryLimit = 100
+
+func (b *Gate) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	client := b.newSignedClient()
+	// The history has no filter on the ID, it is read page by page from the latest withdrawal
+	for offset := int32(0); ; offset += gateHistoryLimit {
+		withdrawals, _, err := client.WalletApi.ListWithdrawals(ctx, &gateapi.ListWithdrawalsOpts{
+			Currency: optional.NewString(strings.ToUpper(asset)),
+			Limit:    optional.NewInt32(gateHistoryLimit),
+			Offset:   optional.NewInt32(offset),
+		})
+		if err != nil {
+			return Transfer{}, gateError(err)
+		}
+
+		for _, withdrawal := range withdrawals {
+			if withdrawal.Id != withdrawalID || !sameNetwork(withdrawal.Chain, network) {
+				continue
+			}
+
+			transfer, err := gateTransfer(gateapi.LedgerRecord{
+				Id:        withdrawal.Id,
+				Txid:      withdrawal.Txid,
+				Timestamp: withdrawal.Timestamp,
+				Amount:    withdrawal.Amount,
+				Currency:  withdrawal.Currency,
+				Address:   withdrawal.Address,
+				Memo:      withdrawal.Memo,
+				Status:    withdrawal.Status,
+				Chain:     withdrawal.Chain,
+			})
+			if err != nil {
+				return Transfer{}, err
+			}
+			if withdrawal.Fee != "" {
+				transfer.Fee, err = decimal.NewFromString(withdrawal.Fee)
+				if err != nil {
+					return Transfer{}, fmt.Errorf("invalid withdrawal fee: %v", err)
+				}
+			}
+			return transfer, nil
+		}
+
+		if len(withdrawals) < gateHistoryLimit {
+			return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+		}
+	}
+}
+
+func (b *Gate) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	client := b.newSignedClient()
+	for offset := int32(0); ; offset += gateHistoryLimit {
+		deposits, _, err := client.WalletApi.ListDeposits(ctx, &gateapi.ListDepositsOpts{
+			Currency: optional.NewString(strings.ToUpper(asset)),
+			Limit:    optional.NewInt32(gateHistoryLimit),
+			Offset:   optional.NewInt32(offset),
+		})
+		if err != nil {
+			return Transfer{}, gateError(err)
+		}
+
+		for _, deposit := range deposits {
+			if deposit.Txid == txID && sameNetwork(deposit.Chain, network) {
+				return gateTransfer(deposit)
+			}
+		}
+
+		if len(deposits) < gateHistoryLimit {
+			return Transfer{}, errTransferNotFound("deposit", txID)
+		}
+	}
+}
+
+// gateTransfer converts a withdrawal or a deposit, whose status is DONE when completed, CANCEL, FAIL
+// or INVALID when failed, EXTPEND when sent on-chain and any other while processing
+func gateTransfer(record gateapi.LedgerRecord) (Transfer, error) {
+	transfer := Transfer{
+		ID:      record.Id,
+		Asset:   record.Currency,
+		Network: record.Chain,
+		Address: record.Address,
+		Memo:    record.Memo,
+		Fee:     decimal.Zero,
+		TxID:    record.Txid,
+	}
+
+	var err error
+	transfer.Amount, err = decimal.NewFromString(record.Amount)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("invalid transfer amount: %v", err)
+	}
+	if seconds, err := strconv.ParseInt(record.Timestamp, 10, 64); err == nil {
+		transfer.CreatedAt = time.Unix(seconds, 0)
+	}
+
+	switch record.Status {
+	case "DONE":
+		transfer.Status = TransferStatusCompleted
+	case "CANCEL", "FAIL", "INVALID":
+		transfer.Status = TransferStatusFailed
+	case "EXTPEND":
+		transfer.Status = TransferStatusConfirming
+	default:
+		transfer.Status = inProgressStatus(record.Txid)
+	}
+	return transfer, nil
 }
 
 func (b Gate) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
@@ -322,16 +720,16 @@ func (b Gate) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExcha
 	}
 
 	if !tickerStatus.CanBeBought() {
-		return fmt.Errorf("%v cannot be bought", currencyPair)
+		return fmt.Errorf("%w: %v cannot be bought", ErrSymbolNotTrading, currencyPair)
 	}
 
-	config := gateapi.NewConfiguration()
+	config := b.newConfiguration()
 	config.Key = b.config.Key
 	config.Secret = b.config.Secret
 	client := gateapi.NewAPIClient(config)
 	networks, _, err := client.WalletApi.ListCurrencyChains(ctx, ticker.Base)
 	if err != nil {
-		return err
+		return gateError(err)
 	}
 
 	for _, network := range networks {
@@ -340,7 +738,7 @@ func (b Gate) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExcha
 		}
 	}
 
-	return nil
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
 
 func (b Gate) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
@@ -351,16 +749,16 @@ func (b Gate) CanDepositAndSell(ctx context.Context, ticker database.SelectExcha
 	}
 
 	if !tickerStatus.CanBeSold() {
-		return fmt.Errorf("%v cannot be sold", currencyPair)
+		return fmt.Errorf("%w: %v cannot be sold", ErrSymbolNotTrading, currencyPair)
 	}
 
-	config := gateapi.NewConfiguration()
+	config := b.newConfiguration()
 	config.Key = b.config.Key
 	config.Secret = b.config.Secret
 	client := gateapi.NewAPIClient(config)
 	networks, _, err := client.WalletApi.ListCurrencyChains(ctx, ticker.Base)
 	if err != nil {
-		return err
+		return gateError(err)
 	}
 
 	for _, network := range networks {
@@ -369,5 +767,5 @@ func (b Gate) CanDepositAndSell(ctx context.Context, ticker database.SelectExcha
 		}
 	}
 
-	return nil
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
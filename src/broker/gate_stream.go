@@ -0,0 +1,131 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/antihax/optional"
+	"github.com/gateio/gateapi-go/v6"
+	"github.com/shopspring/decimal"
+)
+
+const gateStreamURL = "wss://api.gateio.ws/ws/v4/"
+
+func (b Gate) NewMarketDataStream() *MarketDataStream {
+	return newMarketDataStream(gateStream{b})
+}
+
+// gateStream follows the spot.order_book_update channel of the APIv4 websocket
+type gateStream struct {
+	broker Gate
+}
+
+type gateDepthEvent struct {
+	Channel string `json:"channel"`
+	Event   string `json:"event"`
+	Result  struct {
+		FirstID int64               `json:"U"`
+		LastID  int64               `json:"u"`
+		Bids    [][]decimal.Decimal `json:"b"`
+		Asks    [][]decimal.Decimal `json:"a"`
+	} `json:"result"`
+}
+
+func (s gateStream) symbol(ticker database.SelectExchangeTickersRow) string {
+	return strings.ToUpper(ticker.Base) + "_" + strings.ToUpper(ticker.Quote)
+}
+
+func (s gateStream) url(symbol string) string {
+	if s.broker.config.StreamURL != "" {
+		return s.broker.config.StreamURL
+	}
+	return gateStreamURL
+}
+
+func (s gateStream) subscribe(symbol string) [][]byte {
+	return [][]byte{
+		[]byte(fmt.Sprintf(`{"time":%d,"channel":"spot.order_book_update","event":"subscribe","payload":["%v","100ms"]}`, time.Now().Unix(), symbol)),
+	}
+}
+
+func (s gateStream) ping() []byte {
+	return []byte(fmt.Sprintf(`{"time":%d,"channel":"spot.ping"}`, time.Now().Unix()))
+}
+
+func (s gateStream) decode(message []byte) (depthUpdate, bool, error) {
+	var event gateDepthEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return depthUpdate{}, false, err
+	}
+	if event.Channel != "spot.order_book_update" || event.Event != "update" {
+		return depthUpdate{}, false, nil
+	}
+
+	bids, err := toOffers(event.Result.Bids)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+	asks, err := toOffers(event.Result.Asks)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+
+	return depthUpdate{
+		FirstID: event.Result.FirstID,
+		LastID:  event.Result.LastID,
+		Bids:    bids,
+		Asks:    asks,
+	}, true, nil
+}
+
+func (s gateStream) snapshot(ctx context.Context, symbol string) (depthSnapshot, error) {
+	client := gateapi.NewAPIClient(s.broker.newConfiguration())
+	orders, _, err := client.SpotApi.ListOrderBook(ctx, symbol, &gateapi.ListOrderBookOpts{
+		Limit:  optional.NewInt32(100),
+		WithId: optional.NewBool(true),
+	})
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	bids, err := gateOffers(orders.Bids)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+	asks, err := gateOffers(orders.Asks)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	return depthSnapshot{
+		LastID: orders.Id,
+		Book: coin.OrderBook{
+			Bids: bids,
+			Asks: asks,
+		},
+	}, nil
+}
+
+func gateOffers(levels [][]string) ([]coin.Offer, error) {
+	offers := make([]coin.Offer, len(levels))
+	for i, level := range levels {
+		price, err := decimal.NewFromString(level[0])
+		if err != nil {
+			return nil, err
+		}
+		quantity, err := decimal.NewFromString(level[1])
+		if err != nil {
+			return nil, err
+		}
+		offers[i] = coin.Offer{
+			Price:    price,
+			Quantity: quantity,
+		}
+	}
+	return offers, nil
+}
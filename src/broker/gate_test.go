@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
 	"github.com/shopspring/decimal"
 )
 
@@ -62,3 +65,39 @@ func TestGateRefreshExchangeInformation(t *testing.T) {
 
 	gate.RefreshExchangeInformation(context.Background())
 }
+
+func TestGateBaseURL(t *testing.T) {
+	ex := fakeexchange.NewGate("key", "secret")
+	defer ex.Close()
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+		Asks: []coin.Offer{{Price: decimal.NewFromInt(101), Quantity: decimal.NewFromInt(2)}},
+	})
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+	gate, _ := broker.NewGate(broker.Config{
+		InternalName: "Gate",
+		Key:          "key",
+		Secret:       "secret",
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+
+	orderbook, err := gate.GetOrderBooks(context.Background(), database.SelectExchangeTickersRow{
+		Base:  "btc",
+		Quote: "usdt",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderbook.Asks) != 1 || !orderbook.Asks[0].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("unexpected orderbook: %v", orderbook)
+	}
+
+	balance, err := gate.GetBalance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(1000)) {
+		t.Fatalf("unexpected balance: %v", balance)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
@@ -13,29 +14,62 @@ import (
 
 type MEXC struct {
 	config Config
+	client *mexcsdk.Client
 
 	coins           CoinsMap
 	exchangeCoins   ExchangeCoinsMap
 	exchangeTickers ExchangeTickersMap
 
-	tickersStatus map[string]coin.TickerStatus
-	accountStatus AccountStatus
+	tickersStatus  map[string]coin.TickerStatus
+	tickersFilters map[string]coin.TickerFilters
+	coinsNetwork   map[string]mexcsdk.GetAllDepositResponse
+	accountStatus  AccountStatus
+	clock          *Clock
+}
+
+func init() {
+	DefaultRegistry.Register("MEXC", NewMEXC)
 }
 
 func NewMEXC(config Config) (IBroker, error) {
-	return &MEXC{
-		config:        config,
-		tickersStatus: make(map[string]coin.TickerStatus),
-		accountStatus: NewAccountStatus(false),
-	}, nil
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config.HTTP = config.httpConfig(mexcRateLimits)
+
+	b := &MEXC{
+		config:         config,
+		client:         mexcsdk.NewClient(config.HTTP.BaseURL, config.HTTP.HTTPClient()),
+		tickersStatus:  make(map[string]coin.TickerStatus),
+		tickersFilters: make(map[string]coin.TickerFilters),
+		coinsNetwork:   make(map[string]mexcsdk.GetAllDepositResponse),
+		accountStatus:  NewAccountStatus(false),
+	}
+	b.clock = newClock(func(ctx context.Context) (time.Time, error) { return b.serverTime(ctx) })
+	b.client.Now = b.clock.Now
+	return b, nil
 }
 
 func (b MEXC) GetBrokerName() string { return b.config.InternalName }
 
+// Format prints the broker as its redacted configuration, its clients holding the key being left out
+func (b MEXC) Format(f fmt.State, verb rune) { formatBroker(f, verb, "MEXC", b.config) }
+
+func (b MEXC) Clock() *Clock { return b.clock }
+
+// serverTime returns the time of the exchange, for Clock
+func (b MEXC) serverTime(ctx context.Context) (time.Time, error) {
+	resp, err := b.client.GetServerTime(ctx)
+	if err != nil {
+		return time.Time{}, mexcError(err)
+	}
+	return millisToTime(resp.ServerTime), nil
+}
+
 func (b MEXC) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
-	tickers, err := mexcsdk.GetBookTickers()
+	tickers, err := b.client.GetBookTickers(ctx)
 	if err != nil {
-		return nil, err
+		return nil, mexcError(err)
 	}
 
 	tickersInfo := make(map[coin.TickerPair]CoinAllInfo)
@@ -85,34 +119,34 @@ func (b MEXC) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeT
 	base := strings.ToUpper(ticker.Base)
 	quote := strings.ToUpper(ticker.Quote)
 
-	orders, err := mexcsdk.GetDepth(base + quote)
+	orders, err := b.client.GetDepth(ctx, base+quote)
 	if err != nil {
-		return coin.OrderBook{}, err
+		return coin.OrderBook{}, mexcError(err)
 	}
 
 	orderbook := coin.OrderBook{
-		Bids: make([]coin.Offer, len(orders.Bids)),
-		Asks: make([]coin.Offer, len(orders.Asks)),
+		Bids: make([]coin.Offer, 0, len(orders.Bids)),
+		Asks: make([]coin.Offer, 0, len(orders.Asks)),
 	}
 
-	for i, bid := range orders.Bids {
+	for _, bid := range orders.Bids {
 		if !bid[1].GreaterThan(decimal.Zero) {
-			break
+			continue
 		}
-		orderbook.Bids[i] = coin.Offer{
+		orderbook.Bids = append(orderbook.Bids, coin.Offer{
 			Price:    bid[0],
 			Quantity: bid[1],
-		}
+		})
 	}
 
-	for i, ask := range orders.Asks {
+	for _, ask := range orders.Asks {
 		if !ask[1].GreaterThan(decimal.Zero) {
-			break
+			continue
 		}
-		orderbook.Asks[i] = coin.Offer{
+		orderbook.Asks = append(orderbook.Asks, coin.Offer{
 			Price:    ask[0],
 			Quantity: ask[1],
-		}
+		})
 	}
 
 	orderbook.SortAsks()
@@ -122,9 +156,9 @@ func (b MEXC) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeT
 }
 
 func (b MEXC) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
-	coins, err := mexcsdk.GetBalance(b.config.Key, b.config.Secret)
+	coins, err := b.client.GetBalance(ctx, b.config.Key, b.config.Secret)
 	if err != nil {
-		return nil, err
+		return nil, mexcError(err)
 	}
 
 	if len(coins.Balances) == 0 {
@@ -147,6 +181,40 @@ func (b MEXC) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance
 	return balance, nil
 }
 
+func (b MEXC) GetFees() FeeSchedule {
+	return b.config.Fees
+}
+
+func (b MEXC) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	coinsNetwork, err := b.client.GetAllDeposit(ctx, b.config.Key, b.config.Secret)
+	if err != nil {
+		return nil, mexcError(err)
+	}
+
+	networks := []coin.Network{}
+	for _, c := range coinsNetwork {
+		for _, network := range c.NetworkList {
+			// netWork is the code expected by the other endpoints, e.g. TRX, whereas network is its name, e.g. TRC20
+			code := network.NetWork
+			if code == "" {
+				code = network.Network
+			}
+			networks = append(networks, coin.Network{
+				Coin:             strings.ToUpper(c.Coin),
+				Code:             code,
+				Name:             network.Network,
+				DepositPossible:  network.DepositEnable,
+				WithdrawPossible: network.WithdrawEnable,
+				WithdrawFee:      network.WithdrawFee,
+				WithdrawMin:      network.WithdrawMin,
+				MinConfirm:       network.MinConfirm,
+			})
+		}
+	}
+
+	return networks, nil
+}
+
 func (b *MEXC) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
 	b.coins = coins
 	b.exchangeCoins = exchangeCoins
@@ -154,9 +222,13 @@ func (b *MEXC) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoi
 }
 
 func (b *MEXC) RefreshExchangeInformation(ctx context.Context) error {
-	respExchange, err := mexcsdk.GetExchangeInfo(ctx)
+	if err := b.clock.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to synchronize the clock: %w", err)
+	}
+
+	respExchange, err := b.client.GetExchangeInfo(ctx)
 	if err != nil {
-		return err
+		return mexcError(err)
 	}
 
 	for _, ticker := range respExchange.Symbols {
@@ -183,11 +255,26 @@ func (b *MEXC) RefreshExchangeInformation(ctx context.Context) error {
 			IsBuyable:            strings.ToUpper(ticker.Status) == "ENABLED",
 			IsSellable:           strings.ToUpper(ticker.Status) == "ENABLED",
 		}
+
+		filters, err := mexcFilters(ticker)
+		if err != nil {
+			return fmt.Errorf("%v: %v", ticker.Symbol, err)
+		}
+		b.tickersFilters[ticker.Symbol] = filters
+	}
+
+	// The networks are kept for CanBuyAndWithdraw and CanDepositAndSell, called for every opportunity
+	coinsNetwork, err := b.client.GetAllDeposit(ctx, b.config.Key, b.config.Secret)
+	if err != nil {
+		return mexcError(err)
+	}
+	for _, c := range coinsNetwork {
+		b.coinsNetwork[strings.ToUpper(c.Coin)] = c
 	}
 
-	respAccount, err := mexcsdk.GetBalance(b.config.Key, b.config.Secret)
+	respAccount, err := b.client.GetBalance(ctx, b.config.Key, b.config.Secret)
 	if err != nil {
-		return err
+		return mexcError(err)
 	}
 
 	b.accountStatus.CanDeposit = respAccount.CanDeposit
@@ -206,62 +293,284 @@ func (b *MEXC) RefreshExchangeInformation(ctx context.Context) error {
 	return nil
 }
 
-func (b *MEXC) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error {
+func (b MEXC) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return tickerFilters(b.tickersFilters, strings.ToUpper(ticker.Base)+strings.ToUpper(ticker.Quote))
+}
+
+// mexcFilters reads the precisions of a symbol: the prices and the quantities are given with quotePrecision and
+// baseAssetPrecision decimals, baseSizePrecision is the minimum quantity and quoteAmountPrecision the minimum notional
+func mexcFilters(symbol mexcsdk.SymbolGetExchangeInfo) (coin.TickerFilters, error) {
+	filters := coin.TickerFilters{
+		TickSize: coin.StepFromPrecision(symbol.QuotePrecision),
+		StepSize: coin.StepFromPrecision(symbol.BaseAssetPrecision),
+	}
+
+	var err error
+	if filters.MinQuantity, err = decimalOrZero(symbol.BaseSizePrecision); err != nil {
+		return coin.TickerFilters{}, fmt.Errorf("invalid base size precision: %v", err)
+	}
+	if filters.MinNotional, err = decimalOrZero(symbol.QuoteAmountPrecision); err != nil {
+		return coin.TickerFilters{}, fmt.Errorf("invalid quote amount precision: %v", err)
+	}
+	return filters, nil
+}
+
+func (b *MEXC) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, mexcsdk.BUY, mexcsdk.FILL_OR_KILL, maxPrice, quoteQuantity)
+}
+
+func (b *MEXC) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, ticker, mexcsdk.SELL, mexcsdk.IMMEDIATE_OR_CANCEL, minPrice, quoteQuantity)
+}
+
+// placeOrder sends the order, then fetches its state and its trades, which hold the fees
+func (b *MEXC) placeOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, side mexcsdk.OrderSide, orderType mexcsdk.OrderType, price, quoteQuantity decimal.Decimal) (OrderResult, error) {
 	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
 
-	postResp, err := mexcsdk.PostOrder(b.config.Key, b.config.Secret, mexcsdk.Order{
-		Symbol:   symbol,
-		Side:     mexcsdk.BUY,
-		Type:     mexcsdk.FILL_OR_KILL,
-		Quantity: quoteQuantity,
-		Price:    maxPrice,
-	})
+	filters, err := tickerFilters(b.tickersFilters, symbol)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	price, quantity, err := normalizeOrder(filters, side == mexcsdk.BUY, price, quoteQuantity)
 	if err != nil {
-		return err
+		return OrderResult{}, fmt.Errorf("%v: %w", symbol, err)
 	}
 
-	getResp, err := mexcsdk.GetOrder(b.config.Key, b.config.Secret, mexcsdk.GetOrderParams{
-		Symbol:  symbol,
-		OrderId: postResp.OrderID,
+	postResp, err := b.client.PostOrder(ctx, b.config.Key, b.config.Secret, mexcsdk.Order{
+		Symbol:           symbol,
+		Side:             side,
+		Type:             orderType,
+		Quantity:         quantity,
+		Price:            price,
+		NewClientOrderId: newClientOrderID(),
 	})
 	if err != nil {
-		return err
+		return OrderResult{}, mexcError(err)
 	}
 
-	if strings.ToUpper(getResp.Status) != "FILLED" {
-		return fmt.Errorf("the buy order has not been filled")
+	result, err := b.GetOrder(ctx, ticker, postResp.OrderID)
+	if err != nil {
+		return OrderResult{OrderID: postResp.OrderID}, err
 	}
 
-	return nil
+	if !result.IsFilled() {
+		return result, fmt.Errorf("the %v order has not been filled", strings.ToLower(string(side)))
+	}
+	return result, nil
 }
 
-func (b *MEXC) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error {
+func (b *MEXC) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
 	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
 
-	postResp, err := mexcsdk.PostOrder(b.config.Key, b.config.Secret, mexcsdk.Order{
-		Symbol:   symbol,
-		Side:     mexcsdk.SELL,
-		Type:     mexcsdk.IMMEDIATE_OR_CANCEL,
-		Quantity: quoteQuantity,
-		Price:    minPrice,
+	order, err := b.client.GetOrder(ctx, b.config.Key, b.config.Secret, mexcsdk.GetOrderParams{
+		Symbol:  symbol,
+		OrderId: orderID,
 	})
 	if err != nil {
-		return err
+		return OrderResult{}, mexcError(err)
 	}
 
-	getResp, err := mexcsdk.GetOrder(b.config.Key, b.config.Secret, mexcsdk.GetOrderParams{
-		Symbol:  symbol,
-		OrderId: postResp.OrderID,
+	return b.orderResult(ctx, order)
+}
+
+func (b *MEXC) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	_, err := b.client.CancelOrder(ctx, b.config.Key, b.config.Secret, mexcsdk.CancelOrderParams{
+		Symbol:  strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote),
+		OrderId: orderID,
+	})
+	return mexcError(err)
+}
+
+func (b *MEXC) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+
+	_, err := b.client.CancelOpenOrders(ctx, b.config.Key, b.config.Secret, symbol)
+	return mexcError(err)
+}
+
+func (b *MEXC) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	symbol := strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+
+	openOrders, err := b.client.GetOpenOrders(ctx, b.config.Key, b.config.Secret, symbol)
+	if err != nil {
+		return nil, mexcError(err)
+	}
+
+	results := make([]OrderResult, 0, len(openOrders))
+	for _, order := range openOrders {
+		result, err := b.orderResult(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// orderResult converts an order, fetching its trades for the fees when it has been executed
+func (b *MEXC) orderResult(ctx context.Context, order mexcsdk.GetOrderResult) (OrderResult, error) {
+	result := OrderResult{
+		OrderID:       order.OrderId,
+		ClientOrderID: order.ClientOrderId,
+		Status:        toOrderStatus(order.Status),
+		CreatedAt:     millisToTime(order.Time),
+		UpdatedAt:     millisToTime(order.UpdateTime),
+	}
+	result.setFilled(order.ExecutedQty, order.CummulativeQuoteQty)
+
+	if result.FilledQuantity.GreaterThan(decimal.Zero) {
+		trades, err := b.client.GetMyTrades(ctx, b.config.Key, b.config.Secret, mexcsdk.GetMyTradesParams{
+			Symbol:  order.Symbol,
+			OrderId: order.OrderId,
+		})
+		if err != nil {
+			return result, mexcError(err)
+		}
+		for _, trade := range trades {
+			result.addFee(trade.Commission, trade.CommissionAsset)
+		}
+	}
+
+	return result, nil
+}
+
+func (b *MEXC) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	addresses, err := b.client.GetDepositAddress(ctx, b.config.Key, b.config.Secret, strings.ToUpper(asset), network)
+	if err != nil {
+		return DepositAddress{}, mexcError(err)
+	}
+
+	for _, address := range addresses {
+		if sameNetwork(address.Network, network) {
+			return DepositAddress{
+				Asset:   strings.ToUpper(asset),
+				Network: address.Network,
+				Address: address.Address,
+				Memo:    address.Memo,
+			}, nil
+		}
+	}
+	return DepositAddress{}, fmt.Errorf("no deposit address for %v on %v", asset, network)
+}
+
+func (b *MEXC) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	withdrawal, err := b.client.Withdraw(ctx, b.config.Key, b.config.Secret, mexcsdk.WithdrawParams{
+		Coin:    strings.ToUpper(asset),
+		Network: network,
+		Address: address,
+		Memo:    memo,
+		Amount:  amount,
 	})
 	if err != nil {
-		return err
+		return Transfer{}, mexcError(err)
 	}
 
-	if strings.ToUpper(getResp.Status) != "FILLED" {
-		return fmt.Errorf("the sell order has not been filled")
+	return acceptedWithdrawal(ctx, b.GetWithdrawal, withdrawal.Id, asset, network, address, memo, amount), nil
+}
+
+// mexcHistoryLimit is the largest page of the withdrawal and deposit histories
+const mexcHistoryLimit = 1000
+
+func (b *MEXC) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	params := mexcsdk.HistoryParams{Coin: strings.ToUpper(asset), Limit: mexcHistoryLimit}
+	for {
+		withdrawals, err := b.client.GetWithdrawHistory(ctx, b.config.Key, b.config.Secret, params)
+		if err != nil {
+			return Transfer{}, mexcError(err)
+		}
+
+		times := make([]int64, len(withdrawals))
+		for i, withdrawal := range withdrawals {
+			times[i] = withdrawal.ApplyTime
+			if withdrawal.Id != withdrawalID || !sameNetwork(withdrawal.Network, network) {
+				continue
+			}
+
+			status := inProgressStatus(withdrawal.TxId)
+			switch withdrawal.Status {
+			case mexcsdk.WithdrawStatusSuccess:
+				status = TransferStatusCompleted
+			case mexcsdk.WithdrawStatusFailed, mexcsdk.WithdrawStatusCancel:
+				status = TransferStatusFailed
+			}
+			return Transfer{
+				ID:        withdrawal.Id,
+				Asset:     withdrawal.Coin,
+				Network:   withdrawal.Network,
+				Address:   withdrawal.Address,
+				Memo:      withdrawal.Memo,
+				Amount:    withdrawal.Amount,
+				Fee:       withdrawal.TransactionFee,
+				Status:    status,
+				TxID:      withdrawal.TxId,
+				CreatedAt: millisToTime(withdrawal.ApplyTime),
+			}, nil
+		}
+
+		var ok bool
+		if params.EndTime, ok = mexcNextPage(params.EndTime, times); !ok {
+			return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+		}
 	}
+}
 
-	return nil
+func (b *MEXC) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	params := mexcsdk.HistoryParams{Coin: strings.ToUpper(asset), Limit: mexcHistoryLimit}
+	for {
+		deposits, err := b.client.GetDepositHistory(ctx, b.config.Key, b.config.Secret, params)
+		if err != nil {
+			return Transfer{}, mexcError(err)
+		}
+
+		times := make([]int64, len(deposits))
+		for i, deposit := range deposits {
+			times[i] = deposit.InsertTime
+			// MEXC may append the output index to the hash, as in "hash:0"
+			if strings.Split(deposit.TxId, ":")[0] != txID || !sameNetwork(deposit.Network, network) {
+				continue
+			}
+
+			status := TransferStatusConfirming
+			switch deposit.Status {
+			case mexcsdk.DepositStatusSuccess:
+				status = TransferStatusCompleted
+			case mexcsdk.DepositStatusRejected:
+				status = TransferStatusFailed
+			}
+			return Transfer{
+				Asset:     deposit.Coin,
+				Network:   deposit.Network,
+				Address:   deposit.Address,
+				Memo:      deposit.Memo,
+				Amount:    deposit.Amount,
+				Fee:       decimal.Zero,
+				Status:    status,
+				TxID:      txID,
+				CreatedAt: millisToTime(deposit.InsertTime),
+			}, nil
+		}
+
+		var ok bool
+		if params.EndTime, ok = mexcNextPage(params.EndTime, times); !ok {
+			return Transfer{}, errTransferNotFound("deposit", txID)
+		}
+	}
+}
+
+// mexcNextPage returns the end time of the history page following the one ending at endTime, whose transfers
+// were created at times. The histories have neither an offset nor a filter on the ID, the next page ends with
+// the oldest transfer of this one, which is read again along with the others of the same millisecond.
+// ok is false on the last page, or when a whole page is in the same millisecond.
+func mexcNextPage(endTime int64, times []int64) (next int64, ok bool) {
+	if len(times) < mexcHistoryLimit {
+		return 0, false
+	}
+
+	next = times[0]
+	for _, t := range times {
+		next = min(next, t)
+	}
+	return next, next != endTime
 }
 
 func (b MEXC) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
@@ -272,25 +581,20 @@ func (b MEXC) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExcha
 	}
 
 	if !tickerStatus.CanBeBought() {
-		return fmt.Errorf("%v cannot be bought", symbol)
+		return fmt.Errorf("%w: %v cannot be bought", ErrSymbolNotTrading, symbol)
 	}
 
-	coinsNetwork, err := mexcsdk.GetAllDeposit(b.config.Key, b.config.Secret)
-	if err != nil {
-		return err
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
 	}
-
-	for _, coin := range coinsNetwork {
-		if coin.Coin == ticker.Base {
-			for _, network := range coin.NetworkList {
-				if network.WithdrawEnable {
-					return nil
-				}
-			}
+	for _, network := range coinNetwork.NetworkList {
+		if network.WithdrawEnable {
+			return nil
 		}
 	}
 
-	return fmt.Errorf("no network available")
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
 
 func (b MEXC) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
@@ -301,23 +605,18 @@ func (b MEXC) CanDepositAndSell(ctx context.Context, ticker database.SelectExcha
 	}
 
 	if !tickerStatus.CanBeSold() {
-		return fmt.Errorf("%v cannot be sold", symbol)
+		return fmt.Errorf("%w: %v cannot be sold", ErrSymbolNotTrading, symbol)
 	}
 
-	coinsNetwork, err := mexcsdk.GetAllDeposit(b.config.Key, b.config.Secret)
-	if err != nil {
-		return err
+	coinNetwork, ok := b.coinsNetwork[strings.ToUpper(ticker.Base)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no network", ErrNetworkDisabled, ticker.Base)
 	}
-
-	for _, coin := range coinsNetwork {
-		if coin.Coin == ticker.Base {
-			for _, network := range coin.NetworkList {
-				if network.DepositEnable {
-					return nil
-				}
-			}
+	for _, network := range coinNetwork.NetworkList {
+		if network.DepositEnable {
+			return nil
 		}
 	}
 
-	return fmt.Errorf("no network available")
+	return fmt.Errorf("%w: no network available", ErrNetworkDisabled)
 }
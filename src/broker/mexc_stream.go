@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/shopspring/decimal"
+)
+
+const mexcStreamURL = "wss://wbs.mexc.com/ws"
+
+func (b MEXC) NewMarketDataStream() *MarketDataStream {
+	return newMarketDataStream(mexcStream{b})
+}
+
+// mexcStream follows the increase.depth channel, each message carries a single version r
+type mexcStream struct {
+	broker MEXC
+}
+
+type mexcDepthLevel struct {
+	Price    decimal.Decimal `json:"p"`
+	Quantity decimal.Decimal `json:"v"`
+}
+
+type mexcDepthEvent struct {
+	Channel string `json:"c"`
+	Symbol  string `json:"s"`
+	Data    struct {
+		Bids    []mexcDepthLevel `json:"bids"`
+		Asks    []mexcDepthLevel `json:"asks"`
+		Version string           `json:"r"`
+	} `json:"d"`
+}
+
+func (s mexcStream) symbol(ticker database.SelectExchangeTickersRow) string {
+	return strings.ToUpper(ticker.Base) + strings.ToUpper(ticker.Quote)
+}
+
+func (s mexcStream) url(symbol string) string {
+	if s.broker.config.StreamURL != "" {
+		return s.broker.config.StreamURL
+	}
+	return mexcStreamURL
+}
+
+func (s mexcStream) subscribe(symbol string) [][]byte {
+	return [][]byte{
+		[]byte(`{"method":"SUBSCRIPTION","params":["spot@public.increase.depth.v3.api@` + symbol + `"]}`),
+	}
+}
+
+func (s mexcStream) ping() []byte { return []byte(`{"method":"PING"}`) }
+
+func (s mexcStream) decode(message []byte) (depthUpdate, bool, error) {
+	var event mexcDepthEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return depthUpdate{}, false, err
+	}
+	if event.Data.Version == "" {
+		return depthUpdate{}, false, nil
+	}
+
+	var version int64
+	if _, err := fmt.Sscan(event.Data.Version, &version); err != nil {
+		return depthUpdate{}, false, fmt.Errorf("invalid version %v: %v", event.Data.Version, err)
+	}
+
+	update := depthUpdate{
+		FirstID: version,
+		LastID:  version,
+		Bids:    make([]coin.Offer, len(event.Data.Bids)),
+		Asks:    make([]coin.Offer, len(event.Data.Asks)),
+	}
+	for i, bid := range event.Data.Bids {
+		update.Bids[i] = coin.Offer{Price: bid.Price, Quantity: bid.Quantity}
+	}
+	for i, ask := range event.Data.Asks {
+		update.Asks[i] = coin.Offer{Price: ask.Price, Quantity: ask.Quantity}
+	}
+	return update, true, nil
+}
+
+func (s mexcStream) snapshot(ctx context.Context, symbol string) (depthSnapshot, error) {
+	orders, err := s.broker.client.GetDepth(ctx, symbol)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	bids, err := toOffers(orders.Bids)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+	asks, err := toOffers(orders.Asks)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	return depthSnapshot{
+		LastID: orders.LastUpdateID,
+		Book: coin.OrderBook{
+			Bids: bids,
+			Asks: asks,
+		},
+	}, nil
+}
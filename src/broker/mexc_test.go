@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 func TestMEXCGetTickersInformation(t *testing.T) {
@@ -38,3 +41,32 @@ func TestMEXCRefreshExchangeInformation(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestMEXCGetTickersInformationReplay(t *testing.T) {
+	mexc, _ := broker.NewMEXC(broker.Config{HTTP: replay(t, "mexc_book_ticker.json")})
+
+	btc, usdt, eth := uuid.New(), uuid.New(), uuid.New()
+	exchangeBTC, exchangeUSDT, exchangeETH := uuid.New(), uuid.New(), uuid.New()
+	mexc.RefreshCoinsInformation(nil, broker.ExchangeCoinsMap{
+		exchangeBTC:  {ID: exchangeBTC, CoinID: btc, Base: "BTC"},
+		exchangeUSDT: {ID: exchangeUSDT, CoinID: usdt, Base: "USDT"},
+		exchangeETH:  {ID: exchangeETH, CoinID: eth, Base: "ETH"},
+	}, broker.ExchangeTickersMap{
+		"BTCUSDT": {BaseExchCoinID: exchangeBTC, QuoteExchCoinID: exchangeUSDT, Base: "BTC", Quote: "USDT"},
+		"ETHUSDT": {BaseExchCoinID: exchangeETH, QuoteExchCoinID: exchangeUSDT, Base: "ETH", Quote: "USDT"},
+	})
+
+	tickers, err := mexc.GetTickersInformation(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ETHUSDT has no bid and DOGEUSDT is not in the database
+	if len(tickers) != 1 {
+		t.Fatalf("expected only BTCUSDT, got %v", tickers)
+	}
+	values := tickers[coin.TickerPair{Base: btc, Quote: usdt}].Values
+	if !values.HighestBid.Equal(decimal.RequireFromString("62000.10")) || !values.LowestAsk.Equal(decimal.RequireFromString("62001")) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
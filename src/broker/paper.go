@@ -0,0 +1,298 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/shopspring/decimal"
+)
+
+// PaperBroker simulates the orders of a broker against the order book of a real (or recorded)
+// market-data source, and keeps a virtual balance instead of touching the real account.
+type PaperBroker struct {
+	config Config
+	market IBroker
+
+	mu      sync.Mutex
+	balance map[coin.CoinBaseStr]decimal.Decimal
+	orders  map[string]OrderResult
+	lastID  int64
+
+	withdrawals map[string]Transfer
+}
+
+func NewPaperBroker(config Config, market IBroker) (IBroker, error) {
+	if market == nil {
+		return nil, fmt.Errorf("a paper broker needs a market-data source")
+	}
+
+	balance := make(map[coin.CoinBaseStr]decimal.Decimal)
+	for asset, quantity := range config.PaperBalance {
+		balance[strings.ToUpper(asset)] = quantity
+	}
+
+	return &PaperBroker{
+		config:  config,
+		market:  market,
+		balance: balance,
+		orders:  make(map[string]OrderResult),
+
+		withdrawals: make(map[string]Transfer),
+	}, nil
+}
+
+func (b *PaperBroker) GetBrokerName() string { return b.market.GetBrokerName() }
+
+// Format prints the broker as its redacted configuration, leaving out the market it simulates the orders on
+func (b *PaperBroker) Format(f fmt.State, verb rune) { formatBroker(f, verb, "Paper", b.config) }
+
+func (b *PaperBroker) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
+	return b.market.GetTickersInformation(ctx)
+}
+
+func (b *PaperBroker) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
+	return b.market.GetOrderBooks(ctx, ticker)
+}
+
+func (b *PaperBroker) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	balance := make(map[coin.CoinBaseStr]coin.Balance)
+	for asset, quantity := range b.balance {
+		if !quantity.GreaterThan(decimal.Zero) {
+			continue
+		}
+		balance[asset] = coin.Balance{
+			Quantity: quantity,
+		}
+	}
+
+	return balance, nil
+}
+
+func (b *PaperBroker) GetFees() FeeSchedule {
+	return b.market.GetFees()
+}
+
+func (b *PaperBroker) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	return b.market.GetNetworks(ctx)
+}
+
+func (b *PaperBroker) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
+	b.market.RefreshCoinsInformation(coins, exchangeCoins, exchangeTickers)
+}
+
+func (b *PaperBroker) RefreshExchangeInformation(ctx context.Context) error {
+	return b.market.RefreshExchangeInformation(ctx)
+}
+
+func (b *PaperBroker) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return b.market.GetFilters(ticker)
+}
+
+// Buy simulates a FOK order: the whole quantity (quoteQuantity / maxPrice, rounded to the filters of the market)
+// must be filled by asks at a price lower or equal to maxPrice, otherwise nothing happens.
+func (b *PaperBroker) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	filters, err := b.market.GetFilters(ticker)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	maxPrice, toBuy, err := normalizeOrder(filters, true, maxPrice, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("invalid buy order: %v", err)
+	}
+
+	orderbook, err := b.market.GetOrderBooks(ctx, ticker)
+	if err != nil {
+		return OrderResult{}, err
+	}
+
+	base := strings.ToUpper(ticker.Base)
+	quote := strings.ToUpper(ticker.Quote)
+
+	filled, spent := decimal.Zero, decimal.Zero
+	for _, ask := range orderbook.Asks {
+		if ask.Price.GreaterThan(maxPrice) || !filled.LessThan(toBuy) {
+			break
+		}
+		qty := decimal.Min(ask.Quantity, toBuy.Sub(filled))
+		filled = filled.Add(qty)
+		spent = spent.Add(qty.Mul(ask.Price))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if filled.LessThan(toBuy) {
+		return b.newResult(OrderStatusExpired, decimal.Zero, decimal.Zero), fmt.Errorf("the buy order has not been filled")
+	}
+
+	if b.balance[quote].LessThan(spent) {
+		return OrderResult{}, fmt.Errorf("%w of %v: %v < %v", ErrInsufficientBalance, quote, b.balance[quote], spent)
+	}
+
+	b.balance[quote] = b.balance[quote].Sub(spent)
+	b.balance[base] = b.balance[base].Add(filled)
+
+	return b.newResult(OrderStatusFilled, filled, spent), nil
+}
+
+// Sell simulates an IOC order: bids at a price greater or equal to minPrice are taken until
+// quoteQuantity / minPrice, rounded to the filters of the market, is sold, and the remaining quantity is cancelled.
+func (b *PaperBroker) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	filters, err := b.market.GetFilters(ticker)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	minPrice, toSell, err := normalizeOrder(filters, false, minPrice, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("invalid sell order: %v", err)
+	}
+
+	orderbook, err := b.market.GetOrderBooks(ctx, ticker)
+	if err != nil {
+		return OrderResult{}, err
+	}
+
+	base := strings.ToUpper(ticker.Base)
+	quote := strings.ToUpper(ticker.Quote)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.balance[base].LessThan(toSell) {
+		return OrderResult{}, fmt.Errorf("%w of %v: %v < %v", ErrInsufficientBalance, base, b.balance[base], toSell)
+	}
+
+	filled, received := decimal.Zero, decimal.Zero
+	for _, bid := range orderbook.Bids {
+		if bid.Price.LessThan(minPrice) || !filled.LessThan(toSell) {
+			break
+		}
+		qty := decimal.Min(bid.Quantity, toSell.Sub(filled))
+		filled = filled.Add(qty)
+		received = received.Add(qty.Mul(bid.Price))
+	}
+
+	b.balance[base] = b.balance[base].Sub(filled)
+	b.balance[quote] = b.balance[quote].Add(received)
+
+	if filled.LessThan(toSell) {
+		return b.newResult(OrderStatusExpired, filled, received), fmt.Errorf("the sell order has not been filled")
+	}
+
+	return b.newResult(OrderStatusFilled, filled, received), nil
+}
+
+// newResult returns the result of a simulated order, which is executed at once and without fees.
+// It must be called with mu held.
+func (b *PaperBroker) newResult(status OrderStatus, quantity, quoteQuantity decimal.Decimal) OrderResult {
+	b.lastID++
+	now := time.Now()
+
+	result := OrderResult{
+		OrderID:       "paper-" + strconv.FormatInt(b.lastID, 10),
+		ClientOrderID: newClientOrderID(),
+		Status:        status,
+		Fee:           decimal.Zero,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	result.setFilled(quantity, quoteQuantity)
+
+	b.orders[result.OrderID] = result
+	return result
+}
+
+func (b *PaperBroker) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result, ok := b.orders[orderID]
+	if !ok {
+		return OrderResult{}, fmt.Errorf("unknown order %v", orderID)
+	}
+	return result, nil
+}
+
+// CancelOrder always fails, as the simulated orders are finished as soon as they are placed
+func (b *PaperBroker) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	if _, err := b.GetOrder(ctx, ticker, orderID); err != nil {
+		return err
+	}
+	return fmt.Errorf("order %v is not open", orderID)
+}
+
+func (b *PaperBroker) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	return nil
+}
+
+func (b *PaperBroker) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	return []OrderResult{}, nil
+}
+
+func (b *PaperBroker) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	return b.market.GetDepositAddress(ctx, asset, network)
+}
+
+// Withdraw debits amount from the virtual balance, the withdrawal being completed at once
+func (b *PaperBroker) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	asset = strings.ToUpper(asset)
+	if !amount.IsPositive() {
+		return Transfer{}, fmt.Errorf("the amount to withdraw must be positive: %v", amount)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.balance[asset].LessThan(amount) {
+		return Transfer{}, fmt.Errorf("%w of %v: %v < %v", ErrInsufficientBalance, asset, b.balance[asset], amount)
+	}
+	b.balance[asset] = b.balance[asset].Sub(amount)
+
+	b.lastID++
+	transfer := Transfer{
+		ID:        "paper-" + strconv.FormatInt(b.lastID, 10),
+		Asset:     asset,
+		Network:   network,
+		Address:   address,
+		Memo:      memo,
+		Amount:    amount,
+		Fee:       decimal.Zero,
+		Status:    TransferStatusCompleted,
+		CreatedAt: time.Now(),
+	}
+	b.withdrawals[transfer.ID] = transfer
+	return transfer, nil
+}
+
+func (b *PaperBroker) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	transfer, ok := b.withdrawals[withdrawalID]
+	if !ok {
+		return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+	}
+	return transfer, nil
+}
+
+// GetDeposit always fails, as nothing can be sent to the virtual account
+func (b *PaperBroker) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	return Transfer{}, errTransferNotFound("deposit", txID)
+}
+
+func (b *PaperBroker) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	return b.market.CanBuyAndWithdraw(ctx, ticker)
+}
+
+func (b *PaperBroker) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	return b.market.CanDepositAndSell(ctx, ticker)
+}
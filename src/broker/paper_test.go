@@ -0,0 +1,109 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/shopspring/decimal"
+)
+
+type staticMarket struct {
+	broker.IBroker
+	orderbook coin.OrderBook
+}
+
+func (m staticMarket) GetBrokerName() string { return "Static" }
+
+// GetFilters returns no constraint, the orders are taken as they are
+func (m staticMarket) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return coin.TickerFilters{}, nil
+}
+
+func (m staticMarket) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTickersRow) (coin.OrderBook, error) {
+	return m.orderbook, nil
+}
+
+func newPaper(t *testing.T, balance map[string]decimal.Decimal) broker.IBroker {
+	paper, err := broker.NewPaperBroker(broker.Config{PaperBalance: balance}, staticMarket{
+		orderbook: coin.OrderBook{
+			Bids: []coin.Offer{
+				{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)},
+				{Price: decimal.NewFromInt(98), Quantity: decimal.NewFromInt(1)},
+			},
+			Asks: []coin.Offer{
+				{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1)},
+				{Price: decimal.NewFromInt(101), Quantity: decimal.NewFromInt(1)},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paper
+}
+
+var paperTicker = database.SelectExchangeTickersRow{Base: "btc", Quote: "usdt"}
+
+func TestPaperBuyFillOrKill(t *testing.T) {
+	paper := newPaper(t, map[string]decimal.Decimal{"USDT": decimal.NewFromInt(1000)})
+
+	// 303 / 101 = 3 BTC, but only 2 are available under 101
+	if _, err := paper.Buy(context.Background(), paperTicker, decimal.NewFromInt(101), decimal.NewFromInt(303)); err == nil {
+		t.Fatal("expected the order to be killed")
+	}
+
+	if _, err := paper.Buy(context.Background(), paperTicker, decimal.NewFromInt(101), decimal.NewFromInt(202)); err != nil {
+		t.Fatal(err)
+	}
+
+	balance, _ := paper.GetBalance(context.Background())
+	if !balance["BTC"].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("expected 2 BTC, got %v", balance["BTC"].Quantity)
+	}
+	if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(799)) {
+		t.Fatalf("expected 799 USDT, got %v", balance["USDT"].Quantity)
+	}
+}
+
+func TestPaperBuyInsufficientBalance(t *testing.T) {
+	paper := newPaper(t, map[string]decimal.Decimal{"USDT": decimal.NewFromInt(50)})
+
+	if _, err := paper.Buy(context.Background(), paperTicker, decimal.NewFromInt(100), decimal.NewFromInt(100)); err == nil {
+		t.Fatal("expected an insufficient balance error")
+	}
+}
+
+func TestPaperSellImmediateOrCancel(t *testing.T) {
+	paper := newPaper(t, map[string]decimal.Decimal{"BTC": decimal.NewFromInt(3)})
+
+	// 294 / 98 = 3 BTC, but only 2 bids are at 98 or more
+	if _, err := paper.Sell(context.Background(), paperTicker, decimal.NewFromInt(98), decimal.NewFromInt(294)); err == nil {
+		t.Fatal("expected a partial fill error")
+	}
+
+	balance, _ := paper.GetBalance(context.Background())
+	if !balance["BTC"].Quantity.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("expected 1 BTC left, got %v", balance["BTC"].Quantity)
+	}
+	if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(197)) {
+		t.Fatalf("expected 197 USDT, got %v", balance["USDT"].Quantity)
+	}
+}
+
+func TestPaperWithdrawInvalidAmount(t *testing.T) {
+	paper := newPaper(t, map[string]decimal.Decimal{"USDT": decimal.NewFromInt(100)})
+
+	for _, amount := range []decimal.Decimal{decimal.Zero, decimal.NewFromInt(-50)} {
+		if _, err := paper.Withdraw(context.Background(), "USDT", "TRX", "TAddress", "", amount); err == nil {
+			t.Fatalf("a withdrawal of %v should fail", amount)
+		}
+	}
+
+	balance, _ := paper.GetBalance(context.Background())
+	if !balance["USDT"].Quantity.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("expected 100 USDT, got %v", balance["USDT"].Quantity)
+	}
+}
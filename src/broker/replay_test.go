@@ -0,0 +1,82 @@
+package broker_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/ArbitrageCoin/crypto-sdk/src/recorder"
+	"github.com/shopspring/decimal"
+)
+
+// replay returns an HTTP configuration serving the exchanges' answers from testdata/<fixture>.
+// With RECORD_FIXTURES=1, the live endpoints are called instead and the fixture is overwritten.
+func replay(t *testing.T, fixture string) httpclient.Config {
+	mode := recorder.ModeReplay
+	if os.Getenv("RECORD_FIXTURES") == "1" {
+		mode = recorder.ModeRecord
+	}
+
+	rec, err := recorder.New(filepath.Join("testdata", fixture), mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	return httpclient.Config{Transport: rec}
+}
+
+func TestGetOrderBooksReplay(t *testing.T) {
+	offer := func(price, quantity string) coin.Offer {
+		return coin.Offer{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+	}
+	for _, test := range []struct {
+		name      string
+		newBroker broker.Factory
+		fixture   string
+		levels    int
+		bid, ask  coin.Offer
+	}{
+		{"Binance", broker.NewBinance, "binance_depth_btcusdt.json", 5, offer("67234.01", "1.53421"), offer("67234.02", "0.87215")},
+		{"MEXC", broker.NewMEXC, "mexc_depth_btcusdt.json", 3, offer("62000.1", "0.5"), offer("62001", "0.3")},
+		{"Gate", broker.NewGate, "gate_order_book_btc_usdt.json", 4, offer("67236.4", "0.0218"), offer("67236.5", "0.34921")},
+		// XT sends the levels unsorted
+		{"XT", broker.NewXT, "xt_depth_btc_usdt.json", 2, offer("61991", "0.1"), offer("61994.1", "0.05")},
+		{"Bitrue", broker.NewBitrue, "bitrue_depth_btcusdt.json", 3, offer("67228.62", "0.119741"), offer("67231.87", "0.083342")},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := test.newBroker(broker.Config{InternalName: test.name, HTTP: replay(t, test.fixture)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			orderbook, err := b.GetOrderBooks(context.Background(), database.SelectExchangeTickersRow{Base: "btc", Quote: "usdt"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(orderbook.Bids) != test.levels || len(orderbook.Asks) != test.levels {
+				t.Fatalf("unexpected orderbook size: %v", orderbook)
+			}
+			for i := 1; i < test.levels; i++ {
+				if !orderbook.Bids[i].Price.LessThan(orderbook.Bids[i-1].Price) || !orderbook.Asks[i].Price.GreaterThan(orderbook.Asks[i-1].Price) {
+					t.Fatalf("the levels are not sorted from the best offer: %v", orderbook)
+				}
+			}
+			if !orderbook.Bids[0].Price.Equal(test.bid.Price) || !orderbook.Bids[0].Quantity.Equal(test.bid.Quantity) {
+				t.Fatalf("unexpected highest bid: %v", orderbook.Bids[0])
+			}
+			if !orderbook.Asks[0].Price.Equal(test.ask.Price) || !orderbook.Asks[0].Quantity.Equal(test.ask.Quantity) {
+				t.Fatalf("unexpected lowest ask: %v", orderbook.Asks[0])
+			}
+		})
+	}
+}
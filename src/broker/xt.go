@@ -3,7 +3,11 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
@@ -13,25 +17,103 @@ import (
 
 type XT struct {
 	config Config
+	http   xt_com.HttpOption
 
 	coins           CoinsMap
 	exchangeCoins   ExchangeCoinsMap
 	exchangeTickers ExchangeTickersMap
+
+	tickersStatus  map[string]coin.TickerStatus
+	tickersFilters map[string]coin.TickerFilters
+	currencies     map[string]xt_com.CurrencyGetCoinsInfo
+	accountStatus  AccountStatus
+	clock          *Clock
+}
+
+func init() {
+	DefaultRegistry.Register("XT", NewXT)
 }
 
 func NewXT(config Config) (IBroker, error) {
-	return &XT{
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config.HTTP = config.httpConfig(xtRateLimits)
+
+	b := &XT{
 		config: config,
-	}, nil
+		http: xt_com.HttpOption{
+			BaseURL:    config.HTTP.BaseURL,
+			HTTPClient: config.HTTP.HTTPClient(),
+		},
+		tickersStatus:  make(map[string]coin.TickerStatus),
+		tickersFilters: make(map[string]coin.TickerFilters),
+		currencies:     make(map[string]xt_com.CurrencyGetCoinsInfo),
+		accountStatus:  NewAccountStatus(false),
+	}
+	b.clock = newClock(func(ctx context.Context) (time.Time, error) { return b.serverTime(ctx) })
+	b.http.Now = b.clock.Now
+	return b, nil
 }
 
 func (b XT) GetBrokerName() string { return b.config.InternalName }
 
+// Format prints the broker as its redacted configuration, its clients holding the key being left out
+func (b XT) Format(f fmt.State, verb rune) { formatBroker(f, verb, "XT", b.config) }
+
+func (b XT) Clock() *Clock { return b.clock }
+
+// serverTime returns the time of the exchange, for Clock
+func (b XT) serverTime(ctx context.Context) (time.Time, error) {
+	var resp xt_com.ResponseGetServerTime
+	if err := xtUnmarshal(xt_com.PublicHttpAPI{HttpOption: b.http}.GetServerTime(ctx), &resp); err != nil {
+		return time.Time{}, err
+	}
+	return millisToTime(resp.Result.ServerTime), nil
+}
+
+func (b XT) signedClient() xt_com.SignedHttpAPI {
+	return xt_com.SignedHttpAPI{
+		Accesskey:  b.config.Key,
+		Secretkey:  b.config.Secret,
+		HttpOption: b.http,
+	}
+}
+
+// xtSymbol returns the name of the ticker for XT, e.g. btc_usdt
+func xtSymbol(ticker database.SelectExchangeTickersRow) string {
+	return strings.ToLower(ticker.Base) + "_" + strings.ToLower(ticker.Quote)
+}
+
+// xtUnmarshal decodes resp into result, failing when the request or the API (rc != 0) failed
+func xtUnmarshal(resp *xt_com.APIBody, result interface{}) error {
+	if !resp.Status {
+		return xtError(resp, "", nil)
+	}
+
+	var envelope struct {
+		RC int      `json:"rc"`
+		MC string   `json:"mc"`
+		MA []string `json:"ma"`
+	}
+	if err := json.Unmarshal([]byte(resp.Data), &envelope); err != nil {
+		if resp.StatusCode >= 400 {
+			return xtError(resp, "", []string{resp.Data})
+		}
+		return err
+	}
+	if envelope.RC != 0 {
+		return xtError(resp, envelope.MC, envelope.MA)
+	}
+
+	return json.Unmarshal([]byte(resp.Data), result)
+}
+
 func (b XT) GetTickersInformation(ctx context.Context) (map[coin.TickerPair]CoinAllInfo, error) {
-	client := xt_com.PublicHttpAPI{}
-	resp := client.GetFullTicker(nil)
+	client := xt_com.PublicHttpAPI{HttpOption: b.http}
+	resp := client.GetFullTicker(ctx, nil)
 	var tickers xt_com.ResponseGetFullTicker
-	if err := json.Unmarshal([]byte(resp.Data), &tickers); err != nil {
+	if err := xtUnmarshal(resp, &tickers); err != nil {
 		return nil, err
 	}
 
@@ -82,38 +164,38 @@ func (b XT) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTic
 	base := strings.ToLower(ticker.Base)
 	quote := strings.ToLower(ticker.Quote)
 
-	client := xt_com.PublicHttpAPI{}
-	resp := client.GetDepth(map[string]interface{}{
+	client := xt_com.PublicHttpAPI{HttpOption: b.http}
+	resp := client.GetDepth(ctx, map[string]interface{}{
 		"symbol": base + "_" + quote,
 	})
 	var orders xt_com.ResponseGetDepth
-	if err := json.Unmarshal([]byte(resp.Data), &orders); err != nil {
+	if err := xtUnmarshal(resp, &orders); err != nil {
 		return coin.OrderBook{}, err
 	}
 
 	orderbook := coin.OrderBook{
-		Bids: make([]coin.Offer, len(orders.Result.Bids)),
-		Asks: make([]coin.Offer, len(orders.Result.Asks)),
+		Bids: make([]coin.Offer, 0, len(orders.Result.Bids)),
+		Asks: make([]coin.Offer, 0, len(orders.Result.Asks)),
 	}
 
-	for i, bid := range orders.Result.Bids {
+	for _, bid := range orders.Result.Bids {
 		if !bid[1].GreaterThan(decimal.Zero) {
-			break
+			continue
 		}
-		orderbook.Bids[i] = coin.Offer{
+		orderbook.Bids = append(orderbook.Bids, coin.Offer{
 			Price:    bid[0],
 			Quantity: bid[1],
-		}
+		})
 	}
 
-	for i, ask := range orders.Result.Asks {
+	for _, ask := range orders.Result.Asks {
 		if !ask[1].GreaterThan(decimal.Zero) {
-			break
+			continue
 		}
-		orderbook.Asks[i] = coin.Offer{
+		orderbook.Asks = append(orderbook.Asks, coin.Offer{
 			Price:    ask[0],
 			Quantity: ask[1],
-		}
+		})
 	}
 
 	orderbook.SortAsks()
@@ -123,15 +205,11 @@ func (b XT) GetOrderBooks(ctx context.Context, ticker database.SelectExchangeTic
 }
 
 func (b XT) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance, error) {
-	client := xt_com.SignedHttpAPI{
-		Accesskey: b.config.Key,
-		Secretkey: b.config.Secret,
-	}
-	resp := client.GetBalance(nil)
-	var coins xt_com.ResultGetBalance
-	if err := json.Unmarshal([]byte(resp.Data), &coins); err != nil {
+	var resp xt_com.ResponseGetBalance
+	if err := xtUnmarshal(b.signedClient().GetBalance(ctx, nil), &resp); err != nil {
 		return nil, err
 	}
+	coins := resp.Result
 
 	if len(coins.Assets) == 0 {
 		return nil, nil
@@ -170,6 +248,34 @@ func (b XT) GetBalance(ctx context.Context) (map[coin.CoinBaseStr]coin.Balance,
 	return balance, nil
 }
 
+func (b XT) GetFees() FeeSchedule {
+	return b.config.Fees
+}
+
+func (b XT) GetNetworks(ctx context.Context) ([]coin.Network, error) {
+	var resp xt_com.ResponseGetSupportCurrency
+	if err := xtUnmarshal(xt_com.PublicHttpAPI{HttpOption: b.http}.GetSupportCurrency(ctx), &resp); err != nil {
+		return nil, err
+	}
+
+	networks := []coin.Network{}
+	for _, currency := range resp.Result {
+		for _, chain := range currency.SupportChains {
+			networks = append(networks, coin.Network{
+				Coin:             strings.ToUpper(currency.Currency),
+				Code:             chain.Chain,
+				Name:             chain.Chain,
+				DepositPossible:  chain.DepositEnabled,
+				WithdrawPossible: chain.WithdrawEnabled,
+				WithdrawFee:      chain.WithdrawFeeAmount,
+				WithdrawMin:      chain.WithdrawMinAmount,
+			})
+		}
+	}
+
+	return networks, nil
+}
+
 func (b *XT) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoinsMap, exchangeTickers ExchangeTickersMap) {
 	b.coins = coins
 	b.exchangeCoins = exchangeCoins
@@ -177,21 +283,405 @@ func (b *XT) RefreshCoinsInformation(coins CoinsMap, exchangeCoins ExchangeCoins
 }
 
 func (b *XT) RefreshExchangeInformation(ctx context.Context) error {
+	if err := b.clock.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to synchronize the clock: %w", err)
+	}
+
+	var markets xt_com.ResponseGetMarketConfig
+	if err := xtUnmarshal(xt_com.PublicHttpAPI{HttpOption: b.http}.GetAllMarketConfig(ctx), &markets); err != nil {
+		return err
+	}
+
+	for _, ticker := range markets.Result.Symbols {
+		isLimitOrderAllowed := false
+		for _, orderType := range ticker.OrderTypes {
+			if strings.ToUpper(orderType) == "LIMIT" {
+				isLimitOrderAllowed = true
+				break
+			}
+		}
+
+		isFOKAllowed, isIOCAllowed := false, false
+		for _, timeInForce := range ticker.TimeInForces {
+			switch strings.ToUpper(timeInForce) {
+			case "FOK":
+				isFOKAllowed = true
+			case "IOC":
+				isIOCAllowed = true
+			}
+		}
+
+		isEnabled := strings.ToUpper(ticker.State) == "ONLINE"
+		b.tickersStatus[ticker.Symbol] = coin.TickerStatus{
+			IsEnabled:            isEnabled,
+			IsLimitOrderAllowed:  isLimitOrderAllowed,
+			IsSpotTradingAllowed: ticker.TradingEnabled && ticker.OpenapiEnabled,
+			IsBuyable:            isEnabled && isFOKAllowed,
+			IsSellable:           isEnabled && isIOCAllowed,
+		}
+
+		filters, err := xtFilters(ticker)
+		if err != nil {
+			return fmt.Errorf("%v: %v", ticker.Symbol, err)
+		}
+		b.tickersFilters[ticker.Symbol] = filters
+	}
+
+	// The currencies are kept for CanBuyAndWithdraw and CanDepositAndSell, called for every opportunity
+	var coinsInfo xt_com.ResponseGetCoinsInfo
+	if err := xtUnmarshal(xt_com.PublicHttpAPI{HttpOption: b.http}.GetCoinsInfo(ctx), &coinsInfo); err != nil {
+		return err
+	}
+	for _, c := range coinsInfo.Result.Currencies {
+		b.currencies[strings.ToUpper(c.Currency)] = c
+	}
+
+	// XT does not expose the permissions of the API key, reading the spot balances is the only check available
+	var balances xt_com.ResponseGetBalance
+	if err := xtUnmarshal(b.signedClient().GetBalance(ctx, nil), &balances); err != nil {
+		b.accountStatus = NewAccountStatus(false)
+		return err
+	}
+	b.accountStatus = NewAccountStatus(true)
+
 	return nil
 }
 
-func (b *XT) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) error {
-	return nil
+// xtFilters reads the precisions of a symbol and its QUANTITY, PRICE and QUOTE_QTY filters, whose tick sizes
+// take precedence over the precisions
+func xtFilters(symbol xt_com.SymbolGetMarketConfig) (coin.TickerFilters, error) {
+	filters := coin.TickerFilters{
+		TickSize: coin.StepFromPrecision(symbol.PricePrecision),
+		StepSize: coin.StepFromPrecision(symbol.QuantityPrecision),
+	}
+
+	for _, filter := range symbol.Filters {
+		var err error
+		switch filter.Filter {
+		case "PRICE":
+			err = parseFilter(&filters.TickSize, filter.TickSize)
+		case "QUANTITY":
+			err = errors.Join(
+				parseFilter(&filters.StepSize, filter.TickSize),
+				parseFilter(&filters.MinQuantity, filter.Min),
+				parseFilter(&filters.MaxQuantity, filter.Max),
+			)
+		case "QUOTE_QTY":
+			err = parseFilter(&filters.MinNotional, filter.Min)
+		}
+		if err != nil {
+			return coin.TickerFilters{}, fmt.Errorf("invalid %v filter: %v", filter.Filter, err)
+		}
+	}
+
+	return filters, nil
 }
 
-func (b *XT) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) error {
-	return nil
+func (b XT) GetFilters(ticker database.SelectExchangeTickersRow) (coin.TickerFilters, error) {
+	return tickerFilters(b.tickersFilters, xtSymbol(ticker))
+}
+
+func (b *XT) Buy(ctx context.Context, ticker database.SelectExchangeTickersRow, maxPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, xtSymbol(ticker), "BUY", "FOK", maxPrice, quoteQuantity)
+}
+
+func (b *XT) Sell(ctx context.Context, ticker database.SelectExchangeTickersRow, minPrice, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	return b.placeOrder(ctx, xtSymbol(ticker), "SELL", "IOC", minPrice, quoteQuantity)
+}
+
+// placeOrder sends a spot limit order, then fetches its state and its trades, which hold the fees
+func (b *XT) placeOrder(ctx context.Context, symbol, side, timeInForce string, price, quoteQuantity decimal.Decimal) (OrderResult, error) {
+	filters, err := tickerFilters(b.tickersFilters, symbol)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	price, quantity, err := normalizeOrder(filters, side == "BUY", price, quoteQuantity)
+	if err != nil {
+		return OrderResult{}, fmt.Errorf("%v: %w", symbol, err)
+	}
+
+	client := b.signedClient()
+
+	var sendResp xt_com.ResponseSendOrder
+	if err := xtUnmarshal(client.SendOrder(ctx, map[string]interface{}{
+		"symbol":        symbol,
+		"clientOrderId": newClientOrderID(),
+		"side":          side,
+		"type":          "LIMIT",
+		"timeInForce":   timeInForce,
+		"bizType":       "SPOT",
+		"price":         price.String(),
+		"quantity":      quantity.String(),
+	}), &sendResp); err != nil {
+		return OrderResult{}, err
+	}
+
+	result, err := b.getOrder(ctx, client, sendResp.Result.OrderID)
+	if err != nil {
+		return OrderResult{OrderID: sendResp.Result.OrderID}, err
+	}
+
+	if !result.IsFilled() {
+		return result, fmt.Errorf("the %v order has not been filled", strings.ToLower(side))
+	}
+	return result, nil
+}
+
+func (b *XT) GetOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) (OrderResult, error) {
+	return b.getOrder(ctx, b.signedClient(), orderID)
+}
+
+func (b *XT) CancelOrder(ctx context.Context, ticker database.SelectExchangeTickersRow, orderID string) error {
+	var resp xt_com.ResponseCancelOrder
+	return xtUnmarshal(b.signedClient().CancelOrder(ctx, orderID), &resp)
+}
+
+func (b *XT) CancelAllOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	var resp xt_com.ResponseCancelOrder
+	return xtUnmarshal(b.signedClient().CancelOpenOrder(ctx, map[string]interface{}{
+		"symbol":  xtSymbol(ticker),
+		"bizType": "SPOT",
+	}), &resp)
+}
+
+func (b *XT) ListOpenOrders(ctx context.Context, ticker database.SelectExchangeTickersRow) ([]OrderResult, error) {
+	client := b.signedClient()
+
+	var resp xt_com.ResponseGetOpenOrder
+	if err := xtUnmarshal(client.GetOpenOrder(ctx, map[string]interface{}{
+		"symbol":  xtSymbol(ticker),
+		"bizType": "SPOT",
+	}), &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]OrderResult, 0, len(resp.Result))
+	for _, order := range resp.Result {
+		result, err := b.orderResult(ctx, client, order)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (b *XT) getOrder(ctx context.Context, client xt_com.SignedHttpAPI, orderID string) (OrderResult, error) {
+	var resp xt_com.ResponseGetOrder
+	if err := xtUnmarshal(client.GetOrder(ctx, map[string]interface{}{
+		"orderId": orderID,
+	}), &resp); err != nil {
+		return OrderResult{}, err
+	}
+
+	return b.orderResult(ctx, client, resp.Result)
+}
+
+// orderResult converts an order, fetching its trades for the fees when it has been executed
+func (b *XT) orderResult(ctx context.Context, client xt_com.SignedHttpAPI, order xt_com.ResultGetOrder) (OrderResult, error) {
+	result := OrderResult{
+		OrderID:       order.OrderID,
+		ClientOrderID: order.ClientOrderID,
+		Status:        toOrderStatus(order.State),
+		CreatedAt:     millisToTime(order.Time),
+		UpdatedAt:     millisToTime(order.UpdatedTime),
+	}
+	result.setFilled(order.ExecutedQty, order.ExecutedQty.Mul(order.AvgPrice))
+
+	if result.FilledQuantity.GreaterThan(decimal.Zero) {
+		var tradesResp xt_com.ResponseGetUserTrade
+		if err := xtUnmarshal(client.GetUserTrade(ctx, map[string]interface{}{
+			"symbol":  order.Symbol,
+			"bizType": "SPOT",
+			"orderId": order.OrderID,
+		}), &tradesResp); err != nil {
+			return result, err
+		}
+		for _, trade := range tradesResp.Result.Items {
+			result.addFee(trade.Fee, trade.FeeCurrency)
+		}
+	}
+
+	return result, nil
+}
+
+func (b *XT) GetDepositAddress(ctx context.Context, asset, network string) (DepositAddress, error) {
+	var resp xt_com.ResponseGetDepositAddress
+	if err := xtUnmarshal(b.signedClient().GetDepositAddress(ctx, map[string]interface{}{
+		"currency": strings.ToLower(asset),
+		"chain":    network,
+	}), &resp); err != nil {
+		return DepositAddress{}, err
+	}
+
+	return DepositAddress{
+		Asset:   strings.ToUpper(asset),
+		Network: network,
+		Address: resp.Result.Address,
+		Memo:    resp.Result.Memo,
+	}, nil
+}
+
+func (b *XT) Withdraw(ctx context.Context, asset, network, address, memo string, amount decimal.Decimal) (Transfer, error) {
+	params := map[string]interface{}{
+		"currency": strings.ToLower(asset),
+		"chain":    network,
+		"amount":   amount.String(),
+		"address":  address,
+	}
+	if memo != "" {
+		params["memo"] = memo
+	}
+
+	var resp xt_com.ResponseWithdraw
+	if err := xtUnmarshal(b.signedClient().Withdraw(ctx, params), &resp); err != nil {
+		return Transfer{}, err
+	}
+
+	return acceptedWithdrawal(ctx, b.GetWithdrawal, strconv.FormatInt(resp.Result.ID, 10), asset, network, address, memo, amount), nil
+}
+
+func (b *XT) GetWithdrawal(ctx context.Context, asset, network, withdrawalID string) (Transfer, error) {
+	item, err := b.findTransfer(ctx, b.signedClient().GetWithdrawHistory, asset, network, func(item xt_com.ItemGetTransferHistory) bool {
+		return strconv.FormatInt(item.ID, 10) == withdrawalID
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	if item == nil {
+		return Transfer{}, errTransferNotFound("withdrawal", withdrawalID)
+	}
+	return xtTransfer(*item), nil
+}
+
+func (b *XT) GetDeposit(ctx context.Context, asset, network, txID string) (Transfer, error) {
+	item, err := b.findTransfer(ctx, b.signedClient().GetDepositHistory, asset, network, func(item xt_com.ItemGetTransferHistory) bool {
+		return item.TransactionID == txID
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	if item == nil {
+		return Transfer{}, errTransferNotFound("deposit", txID)
+	}
+	return xtTransfer(*item), nil
+}
+
+// xtHistoryLimit is the largest page of the withdrawal and deposit histories
+const xtHistoryLimit = 200
+
+// findTransfer reads a history of asset on network page by page from the latest transfer, it returns the first
+// one matching found, nil when none does
+func (b *XT) findTransfer(ctx context.Context, history func(ctx context.Context, data map[string]interface{}) *xt_com.APIBody,
+	asset, network string, found func(xt_com.ItemGetTransferHistory) bool) (*xt_com.ItemGetTransferHistory, error) {
+	params := map[string]interface{}{
+		"currency": strings.ToLower(asset),
+		"chain":    network,
+		"limit":    xtHistoryLimit,
+	}
+	for {
+		var resp xt_com.ResponseGetTransferHistory
+		if err := xtUnmarshal(history(ctx, params), &resp); err != nil {
+			return nil, err
+		}
+
+		items := resp.Result.Items
+		for i := range items {
+			if found(items[i]) {
+				return &items[i], nil
+			}
+		}
+
+		if !resp.Result.HasNext || len(items) == 0 {
+			return nil, nil
+		}
+		// the next page starts after the last transfer of this one, going back in time
+		params["fromId"] = items[len(items)-1].ID
+		params["direction"] = "NEXT"
+	}
+}
+
+// xtTransfer converts a withdrawal or a deposit, whose status is SUCCESS when completed, FAIL, FAILURE
+// or CANCEL when failed, and any other while processing
+func xtTransfer(item xt_com.ItemGetTransferHistory) Transfer {
+	transfer := Transfer{
+		ID:        strconv.FormatInt(item.ID, 10),
+		Asset:     strings.ToUpper(item.Currency),
+		Network:   item.Chain,
+		Address:   item.Address,
+		Memo:      item.Memo,
+		Amount:    item.Amount,
+		Fee:       item.Fee,
+		TxID:      item.TransactionID,
+		CreatedAt: millisToTime(item.CreatedTime),
+	}
+
+	switch item.Status {
+	case "SUCCESS":
+		transfer.Status = TransferStatusCompleted
+	case "FAIL", "FAILURE", "CANCEL":
+		transfer.Status = TransferStatusFailed
+	default:
+		transfer.Status = inProgressStatus(item.TransactionID)
+	}
+	return transfer
+}
+
+// getCurrency returns the deposit and withdrawal status of a currency, as of the last RefreshExchangeInformation
+func (b XT) getCurrency(currency string) (xt_com.CurrencyGetCoinsInfo, error) {
+	c, ok := b.currencies[strings.ToUpper(currency)]
+	if !ok {
+		return xt_com.CurrencyGetCoinsInfo{}, fmt.Errorf("%v is not listed", currency)
+	}
+	return c, nil
 }
 
 func (b XT) CanBuyAndWithdraw(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	symbol := xtSymbol(ticker)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeBought() {
+		return fmt.Errorf("%w: %v cannot be bought", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanBuyAndWithdraw() {
+		return fmt.Errorf("the account cannot buy and withdraw")
+	}
+
+	currency, err := b.getCurrency(ticker.Base)
+	if err != nil {
+		return err
+	}
+	if currency.WithdrawStatus != 1 {
+		return fmt.Errorf("%w: the withdrawals of %v are closed", ErrNetworkDisabled, ticker.Base)
+	}
+
 	return nil
 }
 
 func (b XT) CanDepositAndSell(ctx context.Context, ticker database.SelectExchangeTickersRow) error {
+	symbol := xtSymbol(ticker)
+	tickerStatus, ok := b.tickersStatus[symbol]
+	if !ok {
+		return fmt.Errorf("%v is not in tickerStatus", symbol)
+	}
+
+	if !tickerStatus.CanBeSold() {
+		return fmt.Errorf("%w: %v cannot be sold", ErrSymbolNotTrading, symbol)
+	}
+	if !b.accountStatus.CanDepositAndSell() {
+		return fmt.Errorf("the account cannot deposit and sell")
+	}
+
+	currency, err := b.getCurrency(ticker.Base)
+	if err != nil {
+		return err
+	}
+	if currency.DepositStatus != 1 {
+		return fmt.Errorf("%w: the deposits of %v are closed", ErrNetworkDisabled, ticker.Base)
+	}
+
 	return nil
 }
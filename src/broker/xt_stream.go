@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/xt_com"
+	"github.com/shopspring/decimal"
+)
+
+const xtStreamURL = "wss://stream.xt.com/public"
+
+func (b XT) NewMarketDataStream() *MarketDataStream {
+	return newMarketDataStream(xtStream{b})
+}
+
+// xtStream follows the depth_update topic of the public websocket
+type xtStream struct {
+	broker XT
+}
+
+type xtDepthEvent struct {
+	Topic string `json:"topic"`
+	Data  struct {
+		FirstID int64               `json:"fi"`
+		LastID  int64               `json:"i"`
+		Bids    [][]decimal.Decimal `json:"b"`
+		Asks    [][]decimal.Decimal `json:"a"`
+	} `json:"data"`
+}
+
+func (s xtStream) symbol(ticker database.SelectExchangeTickersRow) string {
+	return xtSymbol(ticker)
+}
+
+func (s xtStream) url(symbol string) string {
+	if s.broker.config.StreamURL != "" {
+		return s.broker.config.StreamURL
+	}
+	return xtStreamURL
+}
+
+func (s xtStream) subscribe(symbol string) [][]byte {
+	return [][]byte{
+		[]byte(`{"method":"subscribe","params":["depth_update@` + symbol + `"],"id":"` + symbol + `"}`),
+	}
+}
+
+func (s xtStream) ping() []byte { return []byte("ping") }
+
+func (s xtStream) decode(message []byte) (depthUpdate, bool, error) {
+	// the pongs are not JSON
+	if string(message) == "pong" {
+		return depthUpdate{}, false, nil
+	}
+
+	var event xtDepthEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return depthUpdate{}, false, err
+	}
+	if event.Topic != "depth_update" {
+		return depthUpdate{}, false, nil
+	}
+
+	bids, err := toOffers(event.Data.Bids)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+	asks, err := toOffers(event.Data.Asks)
+	if err != nil {
+		return depthUpdate{}, false, err
+	}
+
+	return depthUpdate{
+		FirstID: event.Data.FirstID,
+		LastID:  event.Data.LastID,
+		Bids:    bids,
+		Asks:    asks,
+	}, true, nil
+}
+
+func (s xtStream) snapshot(ctx context.Context, symbol string) (depthSnapshot, error) {
+	client := xt_com.PublicHttpAPI{HttpOption: s.broker.http}
+	var orders xt_com.ResponseGetDepth
+	if err := xtUnmarshal(client.GetDepth(ctx, map[string]interface{}{
+		"symbol": symbol,
+		"limit":  500,
+	}), &orders); err != nil {
+		return depthSnapshot{}, err
+	}
+
+	bids, err := toOffers(orders.Result.Bids)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+	asks, err := toOffers(orders.Result.Asks)
+	if err != nil {
+		return depthSnapshot{}, err
+	}
+
+	return depthSnapshot{
+		LastID: orders.Result.LastUpdateID,
+		Book: coin.OrderBook{
+			Bids: bids,
+			Asks: asks,
+		},
+	}, nil
+}
@@ -5,7 +5,11 @@ import (
 	"testing"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/broker"
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
 	"github.com/ArbitrageCoin/crypto-sdk/src/database/database"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+	"github.com/shopspring/decimal"
 )
 
 func TestXTGetOrderBooks(t *testing.T) {
@@ -41,3 +45,55 @@ func TestXTGetBalance(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestXTTrading(t *testing.T) {
+	ex := fakeexchange.NewXT("key", "secret")
+	defer ex.Close()
+	ex.SetOrderBook("BTC", "USDT", coin.OrderBook{
+		Bids: []coin.Offer{{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)}},
+		Asks: []coin.Offer{{Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(2)}},
+	})
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+	ex.SetNetwork(fakeexchange.Network{Coin: "BTC", Network: "BTC", WithdrawEnable: true})
+
+	xt, _ := broker.NewXT(broker.Config{
+		InternalName: "XT",
+		Key:          "key",
+		Secret:       "secret",
+		HTTP:         httpclient.Config{BaseURL: ex.URL()},
+	})
+	ticker := database.SelectExchangeTickersRow{Base: "BTC", Quote: "USDT"}
+	ctx := context.Background()
+
+	if err := xt.CanBuyAndWithdraw(ctx, ticker); err == nil {
+		t.Fatalf("the status should be unknown before the refresh")
+	}
+	if err := xt.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := xt.CanBuyAndWithdraw(ctx, ticker); err != nil {
+		t.Fatal(err)
+	}
+	if err := xt.CanDepositAndSell(ctx, ticker); err == nil {
+		t.Fatalf("the deposits of BTC are closed")
+	}
+
+	// 300 USDT at 100 is more than the 2 BTC available, the FOK order is killed
+	if _, err := xt.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(300)); err == nil {
+		t.Fatalf("the buy order should not have been filled")
+	}
+	if _, err := xt.Buy(ctx, ticker, decimal.NewFromInt(100), decimal.NewFromInt(150)); err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Balance("BTC").Equal(decimal.RequireFromString("1.5")) {
+		t.Fatalf("unexpected BTC balance: %v", ex.Balance("BTC"))
+	}
+
+	ex.SetTradable("BTC", "USDT", false)
+	if err := xt.RefreshExchangeInformation(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := xt.CanBuyAndWithdraw(ctx, ticker); err == nil {
+		t.Fatalf("a halted ticker should not be bought")
+	}
+}
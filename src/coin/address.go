@@ -1,9 +1,3 @@
 package coin
 
 type Address = string
-
-type Network struct {
-	Name             string
-	DepositPossible  bool
-	WithdrawPossible bool
-}
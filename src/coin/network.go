@@ -0,0 +1,106 @@
+package coin
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Network is a blockchain through which a coin can be deposited to, or withdrawn from, an exchange
+type Network struct {
+	Coin CoinBaseStr
+	// Code is the name of the network on the exchange, e.g. "TRC20", "TRX" or "Tron" for the same chain
+	Code string
+	// Name is the human readable name of the network, when the exchange gives one
+	Name string
+
+	DepositPossible  bool
+	WithdrawPossible bool
+	WithdrawFee      decimal.Decimal
+	WithdrawMin      decimal.Decimal
+	// MinConfirm is the number of confirmations needed before a deposit is credited, 0 when unknown
+	MinConfirm int
+
+	// AddressRegex and MemoRegex validate the addresses and memos of the network, when the exchange gives them
+	AddressRegex string
+	MemoRegex    string
+}
+
+// CanonicalCode returns the canonical name of the network, the same for every exchange
+func (n Network) CanonicalCode() string {
+	return CanonicalNetwork(n.Coin, n.Code)
+}
+
+// networkAliases maps the names the exchanges give to a chain to its canonical name, which is the one used by
+// Binance. The names already canonical are not listed.
+var networkAliases = map[string]string{
+	"BITCOIN": "BTC",
+
+	"ERC20":    "ETH",
+	"ETHEREUM": "ETH",
+
+	"TRC20": "TRX",
+	"TRON":  "TRX",
+
+	"BEP20":           "BSC",
+	"BEP20(BSC)":      "BSC",
+	"BNB SMART CHAIN": "BSC",
+	"BSC_BNB":         "BSC",
+
+	"BEP2":             "BNB",
+	"BNB BEACON CHAIN": "BNB",
+
+	"SOLANA": "SOL",
+	"SPL":    "SOL",
+
+	"POLYGON": "MATIC",
+	"POL":     "MATIC",
+
+	"ARBITRUM ONE": "ARBITRUM",
+	"ARBEVM":       "ARBITRUM",
+	"ARB":          "ARBITRUM",
+
+	"OPETH": "OPTIMISM",
+	"OP":    "OPTIMISM",
+
+	"AVAX C-CHAIN": "AVAXC",
+	"AVAX_C":       "AVAXC",
+	"AVAX-C":       "AVAXC",
+	"CCHAIN":       "AVAXC",
+
+	"ALGORAND": "ALGO",
+	"RIPPLE":   "XRP",
+	"TONCOIN":  "TON",
+	"KAVAEVM":  "KAVA",
+}
+
+// CanonicalNetwork returns the canonical name of the network named code on an exchange, for coin.
+// The exchanges prefixing the network with the coin, as Gate does with "USDT_ALGO", are handled. The prefix is
+// only trimmed when the rest is a known network, as the code of a native coin may start with its own symbol, as
+// "AVAX_C" does.
+func CanonicalNetwork(coin CoinBaseStr, code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if canonical, ok := networkAliases[code]; ok {
+		return canonical
+	}
+
+	if network, ok := strings.CutPrefix(code, strings.ToUpper(coin)+"_"); ok {
+		if canonical, ok := networkAliases[network]; ok {
+			return canonical
+		}
+		if isCanonicalNetwork(network) {
+			return network
+		}
+	}
+	return code
+}
+
+// isCanonicalNetwork tells whether code is the canonical name of a network
+func isCanonicalNetwork(code string) bool {
+	for _, canonical := range networkAliases {
+		if canonical == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package coin_test
+
+import (
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+)
+
+func TestCanonicalNetwork(t *testing.T) {
+	for _, test := range []struct {
+		coin     coin.CoinBaseStr
+		code     string
+		expected string
+	}{
+		{"USDT", "TRC20", "TRX"},
+		{"USDT", " tron ", "TRX"},
+		{"USDT", "USDT_ALGO", "ALGO"},
+		{"USDT", "USDT_ERC20", "ETH"},
+		{"ETH", "ETH", "ETH"},
+		// The codes of native coins starting with their own symbol
+		{"AVAX", "AVAX_C", "AVAXC"},
+		{"BNB", "BSC_BNB", "BSC"},
+		{"AVAX", "AVAX_X", "AVAX_X"},
+		// An unknown network is left as is
+		{"USDT", "NEWCHAIN", "NEWCHAIN"},
+	} {
+		if canonical := coin.CanonicalNetwork(test.coin, test.code); canonical != test.expected {
+			t.Errorf("%v on %v: expected %v, got %v", test.coin, test.code, test.expected, canonical)
+		}
+	}
+}
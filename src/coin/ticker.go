@@ -1,6 +1,8 @@
 package coin
 
 import (
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -45,3 +47,72 @@ func (ts TickerStatus) CanBeBought() bool {
 func (ts TickerStatus) CanBeSold() bool {
 	return ts.CanTrade() && ts.IsSellable
 }
+
+// TickerFilters are the constraints set by the exchange on the orders of a ticker, a zero value meaning none
+type TickerFilters struct {
+	TickSize    decimal.Decimal
+	StepSize    decimal.Decimal
+	MinQuantity decimal.Decimal
+	MaxQuantity decimal.Decimal
+	MinNotional decimal.Decimal
+}
+
+// StepFromPrecision returns the step of the values given with precision decimals, e.g. 0.01 for 2
+func StepFromPrecision(precision int) decimal.Decimal {
+	return decimal.New(1, -int32(precision))
+}
+
+// RoundPrice rounds price to a multiple of the tick size, up or down
+func (f TickerFilters) RoundPrice(price decimal.Decimal, up bool) decimal.Decimal {
+	return roundToStep(price, f.TickSize, up)
+}
+
+// RoundQuantity rounds quantity down to a multiple of the step size, never buying or selling more than asked
+func (f TickerFilters) RoundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	return roundToStep(quantity, f.StepSize, false)
+}
+
+// Validate checks an order of quantity at price, both being already rounded
+func (f TickerFilters) Validate(price, quantity decimal.Decimal) error {
+	if !price.IsPositive() {
+		return fmt.Errorf("the price %v must be positive", price)
+	}
+	if !quantity.IsPositive() {
+		return fmt.Errorf("the quantity %v must be positive, once rounded to the step %v", quantity, f.StepSize)
+	}
+	if !isMultiple(price, f.TickSize) {
+		return fmt.Errorf("the price %v is not a multiple of the tick size %v", price, f.TickSize)
+	}
+	if !isMultiple(quantity, f.StepSize) {
+		return fmt.Errorf("the quantity %v is not a multiple of the step size %v", quantity, f.StepSize)
+	}
+	if quantity.LessThan(f.MinQuantity) {
+		return fmt.Errorf("the quantity %v is lower than the minimum %v", quantity, f.MinQuantity)
+	}
+	if f.MaxQuantity.IsPositive() && quantity.GreaterThan(f.MaxQuantity) {
+		return fmt.Errorf("the quantity %v is greater than the maximum %v", quantity, f.MaxQuantity)
+	}
+	if notional := price.Mul(quantity); notional.LessThan(f.MinNotional) {
+		return fmt.Errorf("the notional %v is lower than the minimum %v", notional, f.MinNotional)
+	}
+	return nil
+}
+
+func roundToStep(value, step decimal.Decimal, up bool) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	remainder := value.Mod(step)
+	if remainder.IsZero() {
+		return value
+	}
+	rounded := value.Sub(remainder)
+	if up {
+		rounded = rounded.Add(step)
+	}
+	return rounded
+}
+
+func isMultiple(value, step decimal.Decimal) bool {
+	return !step.IsPositive() || value.Mod(step).IsZero()
+}
@@ -5,7 +5,10 @@
 package database
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type Coin struct {
@@ -29,9 +32,29 @@ type ExchangeCoin struct {
 	Base       string    `json:"base"`
 }
 
+type ExchangeCoinNetwork struct {
+	ID              uuid.UUID       `json:"id"`
+	ExchangeCoinID  uuid.UUID       `json:"exchange_coin_id"`
+	NetworkID       uuid.UUID       `json:"network_id"`
+	Code            string          `json:"code"`
+	DepositEnabled  bool            `json:"deposit_enabled"`
+	WithdrawEnabled bool            `json:"withdraw_enabled"`
+	WithdrawFee     decimal.Decimal `json:"withdraw_fee"`
+	WithdrawMin     decimal.Decimal `json:"withdraw_min"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
 type ExchangeTicker struct {
 	ID              uuid.UUID `json:"id"`
 	ExchangeID      uuid.UUID `json:"exchange_id"`
 	BaseExchCoinID  uuid.UUID `json:"base_exch_coin_id"`
 	QuoteExchCoinID uuid.UUID `json:"quote_exch_coin_id"`
 }
+
+type Network struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	AddressRegex string    `json:"address_regex"`
+	MemoRegex    string    `json:"memo_regex"`
+}
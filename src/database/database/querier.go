@@ -17,9 +17,14 @@ type Querier interface {
 	SelectAllCoins(ctx context.Context) ([]Coin, error)
 	SelectExchangeCoinFromCoinID(ctx context.Context, coinID uuid.UUID) ([]SelectExchangeCoinFromCoinIDRow, error)
 	SelectExchangeCoinIDFromBase(ctx context.Context, arg SelectExchangeCoinIDFromBaseParams) (uuid.UUID, error)
+	SelectExchangeCoinNetworks(ctx context.Context) ([]SelectExchangeCoinNetworksRow, error)
 	SelectExchangeCoins(ctx context.Context) ([]SelectExchangeCoinsRow, error)
 	SelectExchangeTickers(ctx context.Context) ([]SelectExchangeTickersRow, error)
 	SelectExchanges(ctx context.Context) ([]Exchange, error)
+	SelectNetworks(ctx context.Context) ([]Network, error)
+	UpsertExchangeCoinNetwork(ctx context.Context, arg UpsertExchangeCoinNetworkParams) error
+	// the regexes are only replaced by non-empty ones, as few exchanges give them
+	UpsertNetwork(ctx context.Context, arg UpsertNetworkParams) (uuid.UUID, error)
 }
 
 var _ Querier = (*Queries)(nil)
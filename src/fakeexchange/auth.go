@@ -0,0 +1,128 @@
+package fakeexchange
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recvWindow is how far the timestamp of a signed request may be from the clock of the exchange
+const recvWindow = 5 * time.Second
+
+// checkTimestamp verifies that the timestamp of a signed request, in milliseconds, is within recvWindow of the
+// clock of the exchange
+func (e *Exchange) checkTimestamp(value string) bool {
+	timestamp, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	offset := e.now().Sub(time.UnixMilli(timestamp))
+	return offset < recvWindow && offset > -recvWindow
+}
+
+func hmacHex(secret, message string, sha512Hash bool) string {
+	hash := sha256.New
+	if sha512Hash {
+		hash = sha512.New
+	}
+	h := hmac.New(hash, []byte(secret))
+	h.Write([]byte(message))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkQuerySignature verifies the scheme of MEXC, Binance and Bitrue: the signature is the last
+// query parameter, and is the HMAC-SHA256 of everything before it followed by the body
+func (e *Exchange) checkQuerySignature(r *http.Request, body []byte, keyHeader string) bool {
+	if r.Header.Get(keyHeader) != e.key {
+		return false
+	}
+
+	message, signature, ok := strings.Cut(r.URL.RawQuery, "&signature=")
+	if !ok {
+		if !strings.HasPrefix(r.URL.RawQuery, "signature=") {
+			return false
+		}
+		message, signature = "", strings.TrimPrefix(r.URL.RawQuery, "signature=")
+	}
+
+	return hmac.Equal([]byte(signature), []byte(hmacHex(e.secret, message+string(body), false)))
+}
+
+// checkGateSignature verifies the APIv4 scheme of Gate: HMAC-SHA512 of the method, path, query,
+// SHA512 of the body and timestamp, separated by new lines
+func (e *Exchange) checkGateSignature(r *http.Request, body []byte) bool {
+	if r.Header.Get("KEY") != e.key {
+		return false
+	}
+
+	query, err := url.QueryUnescape(r.URL.RawQuery)
+	if err != nil {
+		return false
+	}
+	hashedBody := sha512.Sum512(body)
+	message := fmt.Sprintf("%s\n%s\n%s\n%s\n%s", r.Method, r.URL.Path, query, hex.EncodeToString(hashedBody[:]), r.Header.Get("Timestamp"))
+
+	return hmac.Equal([]byte(r.Header.Get("SIGN")), []byte(hmacHex(e.secret, message, true)))
+}
+
+// checkXTSignature verifies the scheme of XT: HMAC-SHA256 of the validate-* headers, followed by
+// #method#path and, when there are parameters, #query or #body
+func (e *Exchange) checkXTSignature(r *http.Request, body []byte) bool {
+	if r.Header.Get("validate-appkey") != e.key {
+		return false
+	}
+
+	headers := url.Values{}
+	for _, name := range []string{"validate-algorithms", "validate-appkey", "validate-recvwindow", "validate-timestamp"} {
+		headers.Set(name, r.Header.Get(name))
+	}
+
+	message := headers.Encode() + fmt.Sprintf("#%s#%s", r.Method, r.URL.Path)
+	if r.URL.RawQuery != "" {
+		message += "#" + r.URL.Query().Encode()
+	} else if len(body) > 0 && string(body) != "{}" && string(body) != "null" {
+		message += "#" + string(body)
+	}
+
+	return hmac.Equal([]byte(r.Header.Get("validate-signature")), []byte(hmacHex(e.secret, message, false)))
+}
+
+type transport struct {
+	servers map[string]*url.URL
+	next    http.RoundTripper
+}
+
+// Transport sends the requests made to the real exchanges to the matching fake ones. Requests to
+// any other host go through http.DefaultTransport as it was when Transport was called.
+func Transport(exchanges ...*Exchange) http.RoundTripper {
+	t := &transport{
+		servers: make(map[string]*url.URL),
+		next:    http.DefaultTransport,
+	}
+	for _, e := range exchanges {
+		u, _ := url.Parse(e.URL())
+		t.servers[e.Host()] = u
+	}
+	return t
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	server, ok := t.servers[req.URL.Host]
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = server.Scheme
+	req.URL.Host = server.Host
+	req.Host = server.Host
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,527 @@
+package fakeexchange
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func binanceSymbol(pair Pair) string { return pair.Base + pair.Quote }
+
+// binanceDecimal is the format Binance accepts the prices and quantities in, rejecting the exponents, e.g. 1e-05
+var binanceDecimal = regexp.MustCompile(`^([0-9]{1,20})(\.[0-9]{1,20})?$`)
+
+// binanceDecimalParam parses the decimal param of query the way Binance does
+func binanceDecimalParam(query url.Values, param string) (decimal.Decimal, error) {
+	value := query.Get(param)
+	if !binanceDecimal.MatchString(value) {
+		return decimal.Decimal{}, fmt.Errorf("Illegal characters found in parameter '%v'; legal range is '%v'.", param, binanceDecimal)
+	}
+	return decimal.NewFromString(value)
+}
+
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func binanceWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnknownSymbol):
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -1121, Msg: "Invalid symbol."})
+	case errors.Is(err, errNotTradable):
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -1013, Msg: "Market is closed."})
+	case errors.Is(err, errInsufficientBalance):
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -2010, Msg: "Account has insufficient balance for requested action."})
+	case errors.Is(err, errUnknownOrder):
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -2013, Msg: "Order does not exist."})
+	case errors.Is(err, errFilterFailure):
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -1013, Msg: err.Error()})
+	default:
+		writeJSON(w, http.StatusBadRequest, binanceError{Code: -1102, Msg: err.Error()})
+	}
+}
+
+// NewBinance starts a fake https://api.binance.com accepting orders signed with key and secret
+func NewBinance(key, secret string) *Exchange {
+	return newExchange("Binance", "api.binance.com", key, secret, binanceRoutes)
+}
+
+func binanceRoutes(e *Exchange, mux *http.ServeMux) {
+	signed := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !e.checkTimestamp(r.URL.Query().Get("timestamp")) {
+				writeJSON(w, http.StatusBadRequest, binanceError{Code: -1021, Msg: "Timestamp for this request is outside of the recvWindow."})
+				return
+			}
+			if !e.checkQuerySignature(r, readBody(r), "X-MBX-APIKEY") {
+				writeJSON(w, http.StatusBadRequest, binanceError{Code: -1022, Msg: "Signature for this request is not valid."})
+				return
+			}
+			handler(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET /api/v3/time", e.serverTime)
+	mux.HandleFunc("GET /api/v3/depth", e.binanceDepth)
+	mux.HandleFunc("GET /api/v3/ticker/bookTicker", e.binanceBookTicker)
+	mux.HandleFunc("GET /api/v3/exchangeInfo", e.binanceExchangeInfo)
+	mux.HandleFunc("GET /api/v3/account", signed(e.binanceAccount))
+	mux.HandleFunc("GET /sapi/v1/capital/config/getall", signed(e.binanceCapitalConfig))
+	mux.HandleFunc("GET /sapi/v1/capital/deposit/address", signed(e.binanceDepositAddress))
+	mux.HandleFunc("POST /sapi/v1/capital/withdraw/apply", signed(e.binanceWithdraw))
+	mux.HandleFunc("GET /sapi/v1/capital/withdraw/history", signed(e.binanceWithdrawHistory))
+	mux.HandleFunc("GET /sapi/v1/capital/deposit/hisrec", signed(e.binanceDepositHistory))
+	mux.HandleFunc("POST /api/v3/order", signed(e.binancePostOrder))
+	mux.HandleFunc("GET /api/v3/order", signed(e.binanceGetOrder))
+	mux.HandleFunc("DELETE /api/v3/order", signed(e.binanceCancelOrder))
+	mux.HandleFunc("GET /api/v3/openOrders", signed(e.binanceOpenOrders))
+	mux.HandleFunc("DELETE /api/v3/openOrders", signed(e.binanceCancelOpenOrders))
+	mux.HandleFunc("GET /api/v3/myTrades", signed(e.binanceMyTrades))
+}
+
+func (e *Exchange) binanceDepth(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), binanceSymbol)
+	if !ok {
+		binanceWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orderbook := e.orderbooks[pair]
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lastUpdateId": e.lastID,
+		"bids":         levels(orderbook.Bids),
+		"asks":         levels(orderbook.Asks),
+	})
+}
+
+func (e *Exchange) binanceBookTicker(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickers := []map[string]string{}
+	for _, pair := range e.pairs() {
+		bid, ask := bestOffers(e.orderbooks[pair])
+		tickers = append(tickers, map[string]string{
+			"symbol":   binanceSymbol(pair),
+			"bidPrice": bid.Price.String(),
+			"bidQty":   bid.Quantity.String(),
+			"askPrice": ask.Price.String(),
+			"askQty":   ask.Quantity.String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, tickers)
+}
+
+func (e *Exchange) binanceExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	symbols := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		status := "TRADING"
+		if e.halted[pair] {
+			status = "HALT"
+		}
+		filters := e.filtersOf(pair)
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":               binanceSymbol(pair),
+			"status":               status,
+			"baseAsset":            pair.Base,
+			"quoteAsset":           pair.Quote,
+			"orderTypes":           []string{"LIMIT", "LIMIT_MAKER", "MARKET"},
+			"isSpotTradingAllowed": true,
+			"permissions":          []string{},
+			"filters": []map[string]string{
+				{"filterType": "PRICE_FILTER", "minPrice": filters.TickSize.String(), "maxPrice": "1000000", "tickSize": filters.TickSize.String()},
+				{"filterType": "LOT_SIZE", "minQty": filters.MinQuantity.String(), "maxQty": filters.MaxQuantity.String(), "stepSize": filters.StepSize.String()},
+				{"filterType": "NOTIONAL", "minNotional": filters.MinNotional.String()},
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":        "UTC",
+		"serverTime":      millis(time.Now()),
+		"rateLimits":      []string{},
+		"exchangeFilters": []string{},
+		"symbols":         symbols,
+	})
+}
+
+func (e *Exchange) binanceAccount(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	balances := []map[string]string{}
+	for _, asset := range e.assets() {
+		balances = append(balances, map[string]string{
+			"asset":  asset,
+			"free":   e.balances[asset].String(),
+			"locked": e.locked[asset].String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"canTrade":    true,
+		"canWithdraw": true,
+		"canDeposit":  true,
+		"accountType": "SPOT",
+		"balances":    balances,
+		"permissions": []string{"SPOT"},
+	})
+}
+
+// binanceCapitalConfig mixes the balances and the networks, as Binance does
+func (e *Exchange) binanceCapitalConfig(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	assets := make(map[string]struct{})
+	for _, asset := range e.assets() {
+		assets[asset] = struct{}{}
+	}
+	for _, c := range e.coins() {
+		assets[c] = struct{}{}
+	}
+
+	coins := []map[string]interface{}{}
+	for asset := range assets {
+		networkList := []map[string]interface{}{}
+		depositAll, withdrawAll := false, false
+		for _, network := range e.networks[asset] {
+			depositAll = depositAll || network.DepositEnable
+			withdrawAll = withdrawAll || network.WithdrawEnable
+			networkList = append(networkList, map[string]interface{}{
+				"coin":           asset,
+				"name":           network.Network,
+				"network":        network.Network,
+				"depositEnable":  network.DepositEnable,
+				"withdrawEnable": network.WithdrawEnable,
+				"withdrawFee":    network.WithdrawFee.String(),
+				"withdrawMin":    network.WithdrawMin.String(),
+				"withdrawMax":    network.WithdrawMax.String(),
+				"minConfirm":     network.MinConfirm,
+				"addressRegex":   network.AddressRegex,
+				"memoRegex":      network.MemoRegex,
+			})
+		}
+		coins = append(coins, map[string]interface{}{
+			"coin":              asset,
+			"name":              asset,
+			"free":              e.balances[asset].String(),
+			"locked":            e.locked[asset].String(),
+			"depositAllEnable":  depositAll,
+			"withdrawAllEnable": withdrawAll,
+			"trading":           true,
+			"networkList":       networkList,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, coins)
+}
+
+func binanceOrder(order *Order) map[string]interface{} {
+	status := string(order.Status)
+	if order.Status == StatusCanceled && order.TimeInForce != "GTC" {
+		// Binance expires the IOC and FOK orders it cannot fill
+		status = "EXPIRED"
+	}
+
+	fills := []map[string]string{}
+	if order.ExecutedQty.GreaterThan(decimal.Zero) {
+		fills = append(fills, map[string]string{
+			"price":           order.ExecutedQuote.Div(order.ExecutedQty).String(),
+			"qty":             order.ExecutedQty.String(),
+			"commission":      order.Fee.String(),
+			"commissionAsset": order.FeeAsset,
+		})
+	}
+
+	orderID, _ := strconv.ParseInt(order.ID, 10, 64)
+	return map[string]interface{}{
+		"symbol":              binanceSymbol(order.Pair),
+		"orderId":             orderID,
+		"orderListId":         -1,
+		"clientOrderId":       order.ClientOrderID,
+		"transactTime":        millis(order.Time),
+		"time":                millis(order.Time),
+		"updateTime":          millis(order.Time),
+		"isWorking":           order.IsOpen(),
+		"price":               order.Price.String(),
+		"origQty":             order.Quantity.String(),
+		"executedQty":         order.ExecutedQty.String(),
+		"cummulativeQuoteQty": order.ExecutedQuote.String(),
+		"status":              status,
+		"timeInForce":         order.TimeInForce,
+		"type":                "LIMIT",
+		"side":                order.Side,
+		"fills":               fills,
+	}
+}
+
+func (e *Exchange) binancePostOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), binanceSymbol)
+	if !ok {
+		binanceWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	timeInForce := query.Get("timeInForce")
+	if timeInForce == "" {
+		timeInForce = "GTC"
+	}
+
+	price, err := binanceDecimalParam(query, "price")
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+	quantity, err := binanceDecimalParam(query, "quantity")
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+
+	order, err := e.placeOrder(pair, query.Get("newClientOrderId"), query.Get("side"), timeInForce, price, quantity)
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, binanceOrder(order))
+}
+
+func (e *Exchange) binanceGetOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[r.URL.Query().Get("orderId")]
+	if !ok {
+		binanceWriteError(w, errUnknownOrder)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, binanceOrder(order))
+}
+
+func (e *Exchange) binanceCancelOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.cancelOrder(r.URL.Query().Get("orderId"))
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, binanceOrder(order))
+}
+
+func (e *Exchange) binanceOpenOrders(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), binanceSymbol)
+	if !ok {
+		binanceWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		orders = append(orders, binanceOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// binanceCancelOpenOrders fails when there is nothing to cancel, as the real API does
+func (e *Exchange) binanceCancelOpenOrders(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), binanceSymbol)
+	if !ok {
+		binanceWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	open := e.openOrders(pair)
+	if len(open) == 0 {
+		binanceWriteError(w, errUnknownOrder)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range open {
+		order, _ = e.cancelOrder(order.ID)
+		orders = append(orders, binanceOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// binanceMyTrades answers a single trade per order, filled at its average price
+func (e *Exchange) binanceMyTrades(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), binanceSymbol)
+	if !ok {
+		binanceWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	trades := []map[string]interface{}{}
+	for _, order := range e.trades(pair, query.Get("orderId")) {
+		orderID, _ := strconv.ParseInt(order.ID, 10, 64)
+		trades = append(trades, map[string]interface{}{
+			"symbol":          binanceSymbol(order.Pair),
+			"id":              orderID,
+			"orderId":         orderID,
+			"orderListId":     -1,
+			"price":           order.ExecutedQuote.Div(order.ExecutedQty).String(),
+			"qty":             order.ExecutedQty.String(),
+			"quoteQty":        order.ExecutedQuote.String(),
+			"commission":      order.Fee.String(),
+			"commissionAsset": order.FeeAsset,
+			"time":            millis(order.Time),
+			"isBuyer":         order.Side == "BUY",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, trades)
+}
+
+func (e *Exchange) binanceDepositAddress(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	address, err := e.depositAddress(query.Get("coin"), query.Get("network"))
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"address": address,
+		"coin":    strings.ToUpper(query.Get("coin")),
+		"tag":     "",
+		"url":     "",
+	})
+}
+
+func (e *Exchange) binanceWithdraw(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	amount, _ := decimal.NewFromString(query.Get("amount"))
+	transfer, err := e.withdraw(query.Get("coin"), query.Get("network"), query.Get("address"), query.Get("addressTag"), amount)
+	if err != nil {
+		binanceWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": transfer.ID})
+}
+
+// binanceWithdrawStatus returns 4 (processing), 6 (completed) or 5 (failure)
+func binanceWithdrawStatus(status TransferStatus) int {
+	switch status {
+	case TransferCompleted:
+		return 6
+	case TransferFailed:
+		return 5
+	default:
+		return 4
+	}
+}
+
+func (e *Exchange) binanceWithdrawHistory(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	transfers, _ := historyPage(e.transfersOf(query.Get("coin"), true), queryInt(query, "offset", 0), queryInt(query, "limit", 1000))
+	withdrawals := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		withdrawals = append(withdrawals, map[string]interface{}{
+			"id":             transfer.ID,
+			"amount":         transfer.Amount.Sub(transfer.Fee).String(),
+			"transactionFee": transfer.Fee.String(),
+			"coin":           transfer.Coin,
+			"status":         binanceWithdrawStatus(transfer.Status),
+			"address":        transfer.Address,
+			"addressTag":     transfer.Memo,
+			"txId":           transfer.TxID,
+			"applyTime":      transfer.Time.UTC().Format(time.DateTime),
+			"network":        transfer.Network,
+			"transferType":   0,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, withdrawals)
+}
+
+// binanceDepositStatus returns 0 (pending), 1 (success) or 7 (wrong deposit)
+func binanceDepositStatus(status TransferStatus) int {
+	switch status {
+	case TransferCompleted:
+		return 1
+	case TransferFailed:
+		return 7
+	default:
+		return 0
+	}
+}
+
+func (e *Exchange) binanceDepositHistory(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	transfers := []*Transfer{}
+	for _, transfer := range e.transfersOf(query.Get("coin"), false) {
+		if txID := query.Get("txId"); txID == "" || txID == transfer.TxID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	transfers, _ = historyPage(transfers, queryInt(query, "offset", 0), queryInt(query, "limit", 1000))
+
+	deposits := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		deposits = append(deposits, map[string]interface{}{
+			"id":           transfer.ID,
+			"amount":       transfer.Amount.String(),
+			"coin":         transfer.Coin,
+			"network":      transfer.Network,
+			"status":       binanceDepositStatus(transfer.Status),
+			"address":      transfer.Address,
+			"addressTag":   transfer.Memo,
+			"txId":         transfer.TxID,
+			"insertTime":   millis(transfer.Time),
+			"transferType": 0,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, deposits)
+}
@@ -0,0 +1,410 @@
+package fakeexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func bitrueSymbol(pair Pair) string { return pair.Base + "_" + pair.Quote }
+
+// bitrueAPISymbol is the name of the pair in the /api/v1 endpoints, the kline API using bitrueSymbol
+func bitrueAPISymbol(pair Pair) string { return pair.Base + pair.Quote }
+
+type bitrueError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func bitrueWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnknownSymbol):
+		writeJSON(w, http.StatusBadRequest, bitrueError{Code: -1121, Msg: "Invalid symbol."})
+	case errors.Is(err, errNotTradable):
+		writeJSON(w, http.StatusBadRequest, bitrueError{Code: -1013, Msg: "Symbol is not trading."})
+	case errors.Is(err, errInsufficientBalance):
+		writeJSON(w, http.StatusBadRequest, bitrueError{Code: -2010, Msg: "Account has insufficient balance for requested action."})
+	case errors.Is(err, errUnknownOrder):
+		writeJSON(w, http.StatusBadRequest, bitrueError{Code: -2013, Msg: "Order does not exist."})
+	default:
+		writeJSON(w, http.StatusBadRequest, bitrueError{Code: -1100, Msg: err.Error()})
+	}
+}
+
+// NewBitrue starts a fake https://www.bitrue.com answering the requests signed with key and secret
+func NewBitrue(key, secret string) *Exchange {
+	return newExchange("Bitrue", "www.bitrue.com", key, secret, bitrueRoutes)
+}
+
+func bitrueRoutes(e *Exchange, mux *http.ServeMux) {
+	signed := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !e.checkTimestamp(r.URL.Query().Get("timestamp")) {
+				writeJSON(w, http.StatusBadRequest, bitrueError{Code: -1021, Msg: "Timestamp for this request is outside of the recvWindow."})
+				return
+			}
+			if !e.checkQuerySignature(r, readBody(r), "X-MBX-APIKEY") {
+				writeJSON(w, http.StatusBadRequest, bitrueError{Code: -1022, Msg: "Signature for this request is not valid."})
+				return
+			}
+			handler(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET /kline-api/public.json", e.bitruePublic)
+	mux.HandleFunc("GET /api/v1/time", e.serverTime)
+	mux.HandleFunc("GET /api/v1/depth", e.bitrueDepth)
+	mux.HandleFunc("GET /api/v1/exchangeInfo", e.bitrueExchangeInfo)
+	mux.HandleFunc("GET /api/v1/account", signed(e.bitrueAccount))
+	mux.HandleFunc("POST /api/v1/withdraw/commit", signed(e.bitrueWithdraw))
+	mux.HandleFunc("GET /api/v1/withdraw/history", signed(e.bitrueWithdrawHistory))
+	mux.HandleFunc("GET /api/v1/deposit/history", signed(e.bitrueDepositHistory))
+	mux.HandleFunc("POST /api/v1/order", signed(e.bitruePostOrder))
+	mux.HandleFunc("GET /api/v1/order", signed(e.bitrueGetOrder))
+	mux.HandleFunc("DELETE /api/v1/order", signed(e.bitrueCancelOrder))
+	mux.HandleFunc("GET /api/v1/openOrders", signed(e.bitrueOpenOrders))
+	mux.HandleFunc("GET /api/v2/myTrades", signed(e.bitrueMyTrades))
+}
+
+func (e *Exchange) bitruePublic(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("command") != "returnTicker" {
+		writeJSON(w, http.StatusOK, map[string]string{"code": "1", "msg": "unknown command"})
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data := make(map[string]map[string]interface{})
+	for _, pair := range e.pairs() {
+		bid, ask := bestOffers(e.orderbooks[pair])
+		isFrozen := "0"
+		if e.halted[pair] {
+			isFrozen = "1"
+		}
+		data[bitrueSymbol(pair)] = map[string]interface{}{
+			"lowestAsk":  ask.Price.String(),
+			"highestBid": bid.Price.String(),
+			"isFrozen":   isFrozen,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code": "0",
+		"msg":  "success",
+		"data": data,
+	})
+}
+
+// bitrueLevels adds the empty third element that Bitrue sends with each level
+func bitrueLevels(offers [][]string) [][]interface{} {
+	out := make([][]interface{}, 0, len(offers))
+	for _, offer := range offers {
+		out = append(out, []interface{}{offer[0], offer[1], []string{}})
+	}
+	return out
+}
+
+func (e *Exchange) bitrueDepth(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), bitrueAPISymbol)
+	if !ok {
+		bitrueWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orderbook := e.orderbooks[pair]
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lastUpdateId": e.lastID,
+		"bids":         bitrueLevels(levels(orderbook.Bids)),
+		"asks":         bitrueLevels(levels(orderbook.Asks)),
+	})
+}
+
+func (e *Exchange) bitrueExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	symbols := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		status := "TRADING"
+		if e.halted[pair] {
+			status = "HALT"
+		}
+		filters := e.filtersOf(pair)
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":     bitrueAPISymbol(pair),
+			"status":     status,
+			"baseAsset":  strings.ToLower(pair.Base),
+			"quoteAsset": strings.ToLower(pair.Quote),
+			"orderTypes": []string{"MARKET", "LIMIT"},
+			"filters": []map[string]interface{}{
+				{"filterType": "PRICE_FILTER", "minPrice": filters.TickSize.String(), "maxPrice": "1000000", "priceScale": precision(filters.TickSize)},
+				{"filterType": "LOT_SIZE", "minQty": filters.MinQuantity.String(), "maxQty": filters.MaxQuantity.String(), "minVal": filters.MinNotional.String(), "volumeScale": precision(filters.StepSize)},
+			},
+		})
+	}
+
+	coins := []map[string]interface{}{}
+	for _, coinName := range e.coins() {
+		deposit, withdraw := false, false
+		chains := []string{}
+		chainDetail := []map[string]interface{}{}
+		for _, network := range e.networks[coinName] {
+			deposit = deposit || network.DepositEnable
+			withdraw = withdraw || network.WithdrawEnable
+			chains = append(chains, network.Network)
+			chainDetail = append(chainDetail, map[string]interface{}{
+				"chain":          network.Network,
+				"enableDeposit":  network.DepositEnable,
+				"enableWithdraw": network.WithdrawEnable,
+				"withdrawFee":    network.WithdrawFee.String(),
+				"minWithdraw":    network.WithdrawMin.String(),
+				"maxWithdraw":    network.WithdrawMax.String(),
+			})
+		}
+		coins = append(coins, map[string]interface{}{
+			"coin":           strings.ToLower(coinName),
+			"coinFulName":    coinName,
+			"enableDeposit":  deposit,
+			"enableWithdraw": withdraw,
+			"chains":         chains,
+			"chainDetail":    chainDetail,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": millis(time.Now()),
+		"symbols":    symbols,
+		"coins":      coins,
+	})
+}
+
+func (e *Exchange) bitrueAccount(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	balances := []map[string]string{}
+	for _, asset := range e.assets() {
+		balances = append(balances, map[string]string{
+			"asset":  asset,
+			"free":   e.balances[asset].String(),
+			"locked": e.locked[asset].String(),
+		})
+	}
+
+	// like the real API, canWithdraw and canDeposit are always false
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"makerCommission": 0,
+		"takerCommission": 0,
+		"canTrade":        true,
+		"canWithdraw":     false,
+		"canDeposit":      false,
+		"balances":        balances,
+	})
+}
+
+func bitrueOrder(order *Order) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":              bitrueAPISymbol(order.Pair),
+		"orderId":             json.Number(order.ID),
+		"clientOrderId":       order.ClientOrderID,
+		"price":               order.Price.String(),
+		"origQty":             order.Quantity.String(),
+		"executedQty":         order.ExecutedQty.String(),
+		"cummulativeQuoteQty": order.ExecutedQuote.String(),
+		"status":              string(order.Status),
+		"timeInForce":         order.TimeInForce,
+		"type":                "LIMIT",
+		"side":                order.Side,
+		"time":                millis(order.Time),
+		"updateTime":          millis(order.Time),
+		"isWorking":           order.IsOpen(),
+	}
+}
+
+func (e *Exchange) bitruePostOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), bitrueAPISymbol)
+	if !ok {
+		bitrueWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	timeInForce := query.Get("timeInForce")
+	if timeInForce == "" {
+		timeInForce = "GTC"
+	}
+
+	price, _ := decimal.NewFromString(query.Get("price"))
+	quantity, _ := decimal.NewFromString(query.Get("quantity"))
+
+	order, err := e.placeOrder(pair, query.Get("newClientOrderId"), query.Get("side"), timeInForce, price, quantity)
+	if err != nil {
+		bitrueWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"symbol":        bitrueAPISymbol(order.Pair),
+		"orderId":       json.Number(order.ID),
+		"clientOrderId": order.ClientOrderID,
+		"transactTime":  millis(order.Time),
+	})
+}
+
+func (e *Exchange) bitrueGetOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[r.URL.Query().Get("orderId")]
+	if !ok {
+		bitrueWriteError(w, errUnknownOrder)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bitrueOrder(order))
+}
+
+func (e *Exchange) bitrueCancelOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.cancelOrder(r.URL.Query().Get("orderId"))
+	if err != nil {
+		bitrueWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bitrueOrder(order))
+}
+
+// bitrueMyTrades answers a single trade per order, filled at its average price. Like the real API, the
+// trades cannot be filtered by order.
+func (e *Exchange) bitrueMyTrades(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), bitrueAPISymbol)
+	if !ok {
+		bitrueWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	trades := []map[string]interface{}{}
+	for _, order := range e.trades(pair, "") {
+		trades = append(trades, map[string]interface{}{
+			"symbol":           bitrueAPISymbol(order.Pair),
+			"id":               json.Number(order.ID),
+			"orderId":          json.Number(order.ID),
+			"price":            order.ExecutedQuote.Div(order.ExecutedQty).String(),
+			"qty":              order.ExecutedQty.String(),
+			"commission":       order.Fee.String(),
+			"commissionAssert": strings.ToLower(order.FeeAsset),
+			"time":             millis(order.Time),
+			"isBuyer":          order.Side == "BUY",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, trades)
+}
+
+func (e *Exchange) bitrueOpenOrders(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), bitrueAPISymbol)
+	if !ok {
+		bitrueWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		orders = append(orders, bitrueOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// bitrueWriteData wraps the answers of the wallet endpoints, which unlike the others have a code and a message
+func bitrueWriteData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code": 200,
+		"msg":  "succ",
+		"data": data,
+	})
+}
+
+func (e *Exchange) bitrueWithdraw(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	amount, _ := decimal.NewFromString(query.Get("amount"))
+	transfer, err := e.withdraw(query.Get("coin"), query.Get("chainName"), query.Get("addressTo"), query.Get("tag"), amount)
+	if err != nil {
+		bitrueWriteError(w, err)
+		return
+	}
+
+	bitrueWriteData(w, map[string]interface{}{"withdrawId": json.Number(transfer.ID)})
+}
+
+// bitrueTransfers answers the transfers of the coin, whose status are 0 (pending), 1 (success) and 2 (failure)
+// for the deposits, and 0 (pending), 6 (success) and 5 (failure) for the withdrawals
+func (e *Exchange) bitrueTransfers(w http.ResponseWriter, r *http.Request, withdrawal bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	page, _ := historyPage(e.transfersOf(query.Get("coin"), withdrawal), queryInt(query, "offset", 0), queryInt(query, "limit", 500))
+	transfers := []map[string]interface{}{}
+	for _, transfer := range page {
+		status := 0
+		switch {
+		case transfer.Status == TransferCompleted && withdrawal:
+			status = 6
+		case transfer.Status == TransferCompleted:
+			status = 1
+		case transfer.Status == TransferFailed && withdrawal:
+			status = 5
+		case transfer.Status == TransferFailed:
+			status = 2
+		}
+		transfers = append(transfers, map[string]interface{}{
+			"id":            json.Number(transfer.ID),
+			"symbol":        strings.ToLower(transfer.Coin),
+			"chainName":     transfer.Network,
+			"amount":        transfer.Amount.String(),
+			"fee":           transfer.Fee.String(),
+			"createdAt":     millis(transfer.Time),
+			"updatedAt":     millis(transfer.Time),
+			"addressTo":     transfer.Address,
+			"tag":           transfer.Memo,
+			"txid":          transfer.TxID,
+			"confirmations": 0,
+			"status":        status,
+		})
+	}
+
+	bitrueWriteData(w, transfers)
+}
+
+func (e *Exchange) bitrueWithdrawHistory(w http.ResponseWriter, r *http.Request) {
+	e.bitrueTransfers(w, r, true)
+}
+
+func (e *Exchange) bitrueDepositHistory(w http.ResponseWriter, r *http.Request) {
+	e.bitrueTransfers(w, r, false)
+}
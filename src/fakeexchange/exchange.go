@@ -0,0 +1,612 @@
+package fakeexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	errUnknownSymbol       = errors.New("unknown symbol")
+	errNotTradable         = errors.New("symbol is not tradable")
+	errInsufficientBalance = errors.New("insufficient balance")
+	errUnknownOrder        = errors.New("unknown order")
+	errInvalidOrder        = errors.New("invalid order")
+	errFilterFailure       = errors.New("filter failure")
+)
+
+// DefaultFilters are the filters of the pairs whose filters have not been set
+var DefaultFilters = coin.TickerFilters{
+	TickSize:    decimal.RequireFromString("0.01"),
+	StepSize:    decimal.RequireFromString("0.00001"),
+	MinQuantity: decimal.RequireFromString("0.00001"),
+	MaxQuantity: decimal.NewFromInt(9000),
+	MinNotional: decimal.NewFromInt(5),
+}
+
+type Pair struct {
+	Base  string
+	Quote string
+}
+
+func NewPair(base, quote string) Pair {
+	return Pair{Base: strings.ToUpper(base), Quote: strings.ToUpper(quote)}
+}
+
+// Network is the deposit/withdraw configuration of a coin on a blockchain, as returned by the
+// capital config endpoints of the exchanges
+type Network struct {
+	Coin           string
+	Network        string
+	DepositEnable  bool
+	WithdrawEnable bool
+	WithdrawFee    decimal.Decimal
+	WithdrawMin    decimal.Decimal
+	WithdrawMax    decimal.Decimal
+	MinConfirm     int
+
+	// AddressRegex and MemoRegex are only sent by the exchanges giving them, as Binance
+	AddressRegex string
+	MemoRegex    string
+
+	// Unusable is a chain that Gate lists in its currencies but not in the chains of the coin, as it cannot be
+	// used. Only the fake Gate reads it.
+	Unusable bool
+}
+
+type OrderStatus string
+
+const (
+	StatusNew             OrderStatus = "NEW"
+	StatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	StatusFilled          OrderStatus = "FILLED"
+	StatusCanceled        OrderStatus = "CANCELED"
+)
+
+type Order struct {
+	ID            string
+	ClientOrderID string
+	Pair          Pair
+	Side          string // BUY or SELL
+	TimeInForce   string // GTC, IOC or FOK
+	Price         decimal.Decimal
+	Quantity      decimal.Decimal
+	ExecutedQty   decimal.Decimal
+	ExecutedQuote decimal.Decimal
+	Fee           decimal.Decimal // charged on the received asset, FeeAsset
+	FeeAsset      string
+	Status        OrderStatus
+	Time          time.Time
+}
+
+func (o Order) IsOpen() bool {
+	return o.Status == StatusNew || o.Status == StatusPartiallyFilled
+}
+
+// Exchange is a local HTTP server impersonating the REST API of one exchange. Its order books,
+// balances and networks are set by the test, and the orders sent to it are matched against them.
+type Exchange struct {
+	server *httptest.Server
+	name   string
+	host   string
+	key    string
+	secret string
+
+	mu         sync.Mutex
+	orderbooks map[Pair]coin.OrderBook
+	halted     map[Pair]bool
+	filters    map[Pair]coin.TickerFilters
+	balances   map[string]decimal.Decimal
+	locked     map[string]decimal.Decimal
+	networks   map[string][]Network
+	orders     map[string]*Order
+	transfers  []*Transfer
+	lastID     int64
+	feeRate    decimal.Decimal
+
+	failures []int // statuses answered to the next requests, before they reach the routes
+	requests map[string]int
+	latency  time.Duration
+	// clockOffset is how far the clock of the exchange is ahead of the local one
+	clockOffset time.Duration
+}
+
+func newExchange(name, host, key, secret string, routes func(e *Exchange, mux *http.ServeMux)) *Exchange {
+	e := &Exchange{
+		name:       name,
+		host:       host,
+		key:        key,
+		secret:     secret,
+		orderbooks: make(map[Pair]coin.OrderBook),
+		halted:     make(map[Pair]bool),
+		filters:    make(map[Pair]coin.TickerFilters),
+		balances:   make(map[string]decimal.Decimal),
+		locked:     make(map[string]decimal.Decimal),
+		networks:   make(map[string][]Network),
+		orders:     make(map[string]*Order),
+		feeRate:    decimal.Zero,
+		requests:   make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	routes(e, mux)
+	e.server = httptest.NewServer(e.countAndFail(mux))
+
+	return e
+}
+
+func (e *Exchange) Name() string { return e.name }
+
+// URL is the base URL of the local server
+func (e *Exchange) URL() string { return e.server.URL }
+
+// Host is the host of the real exchange that this server impersonates
+func (e *Exchange) Host() string { return e.host }
+
+func (e *Exchange) Close() { e.server.Close() }
+
+// countAndFail counts the requests per method and path, delays them by the latency set by SetLatency, and answers
+// the statuses set by FailNext in their place
+func (e *Exchange) countAndFail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.mu.Lock()
+		e.requests[r.Method+" "+r.URL.Path]++
+		var status int
+		if len(e.failures) > 0 {
+			status, e.failures = e.failures[0], e.failures[1:]
+		}
+		latency := e.latency
+		e.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if status != 0 {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetClockOffset sets the clock of the exchange offset ahead of the local one, the signed requests being
+// rejected when their timestamp is not within the receive window of this clock
+func (e *Exchange) SetClockOffset(offset time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clockOffset = offset
+}
+
+// now returns the time of the exchange
+func (e *Exchange) now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().Add(e.clockOffset)
+}
+
+// SetLatency delays the answer to every request by latency, as a slow exchange does
+func (e *Exchange) SetLatency(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.latency = latency
+}
+
+// FailNext answers the next requests with statuses, one each, whatever they are
+func (e *Exchange) FailNext(statuses ...int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, statuses...)
+}
+
+// Requests returns the number of requests received with method on path, e.g. GET /api/v3/depth
+func (e *Exchange) Requests(method, path string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.requests[method+" "+path]
+}
+
+func (e *Exchange) SetOrderBook(base, quote string, orderbook coin.OrderBook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	orderbook = copyOrderBook(orderbook)
+	orderbook.SortAsks()
+	orderbook.SortBids()
+	e.orderbooks[NewPair(base, quote)] = orderbook
+}
+
+func (e *Exchange) OrderBook(base, quote string) coin.OrderBook {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return copyOrderBook(e.orderbooks[NewPair(base, quote)])
+}
+
+// SetTradable halts or resumes the trading of a symbol
+func (e *Exchange) SetTradable(base, quote string, tradable bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.halted[NewPair(base, quote)] = !tradable
+}
+
+// SetFilters sets the constraints on the orders of a pair, which are rejected when they do not respect them
+func (e *Exchange) SetFilters(base, quote string, filters coin.TickerFilters) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.filters[NewPair(base, quote)] = filters
+}
+
+// filtersOf returns the filters of pair, DefaultFilters when they have not been set
+func (e *Exchange) filtersOf(pair Pair) coin.TickerFilters {
+	if filters, ok := e.filters[pair]; ok {
+		return filters
+	}
+	return DefaultFilters
+}
+
+func (e *Exchange) SetBalance(asset string, quantity decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.balances[strings.ToUpper(asset)] = quantity
+}
+
+// Balance returns the free quantity of an asset, not counting what is locked by open orders
+func (e *Exchange) Balance(asset string) decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.balances[strings.ToUpper(asset)]
+}
+
+func (e *Exchange) SetNetwork(network Network) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	network.Coin = strings.ToUpper(network.Coin)
+	networks := e.networks[network.Coin]
+	for i := range networks {
+		if networks[i].Network == network.Network {
+			networks[i] = network
+			return
+		}
+	}
+	e.networks[network.Coin] = append(networks, network)
+}
+
+// SetFeeRate sets the taker fee, e.g. 0.001 for 0.1%, charged on the asset received by the orders
+func (e *Exchange) SetFeeRate(rate decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.feeRate = rate
+}
+
+// PlaceOrder places a GTC limit order, as another client of the account would, and returns its ID
+func (e *Exchange) PlaceOrder(base, quote, side string, price, quantity decimal.Decimal) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.placeOrder(NewPair(base, quote), "", side, "GTC", price, quantity)
+	if err != nil {
+		return "", err
+	}
+	return order.ID, nil
+}
+
+// Orders returns every order received by the exchange, sorted by creation
+func (e *Exchange) Orders() []Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	orders := make([]Order, 0, len(e.orders))
+	for _, o := range e.orders {
+		orders = append(orders, *o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		id1, _ := strconv.ParseInt(orders[i].ID, 10, 64)
+		id2, _ := strconv.ParseInt(orders[j].ID, 10, 64)
+		return id1 < id2
+	})
+	return orders
+}
+
+// trades returns the orders of pair that have been executed, only the one of orderID when not empty
+func (e *Exchange) trades(pair Pair, orderID string) []*Order {
+	orders := []*Order{}
+	for _, order := range e.orders {
+		if order.Pair != pair || !order.ExecutedQty.GreaterThan(decimal.Zero) {
+			continue
+		}
+		if orderID != "" && order.ID != orderID {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Time.Before(orders[j].Time) })
+	return orders
+}
+
+// openOrders returns the orders of pair resting in the book, sorted by creation
+func (e *Exchange) openOrders(pair Pair) []*Order {
+	orders := []*Order{}
+	for _, order := range e.orders {
+		if order.Pair == pair && order.IsOpen() {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Time.Before(orders[j].Time) })
+	return orders
+}
+
+// pairs returns the known pairs, sorted so that the answers are deterministic
+func (e *Exchange) pairs() []Pair {
+	pairs := make([]Pair, 0, len(e.orderbooks))
+	for pair := range e.orderbooks {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Base+pairs[i].Quote < pairs[j].Base+pairs[j].Quote
+	})
+	return pairs
+}
+
+// findPair returns the pair whose symbol, in the exchange's format, is symbol
+func (e *Exchange) findPair(symbol string, format func(Pair) string) (Pair, bool) {
+	for pair := range e.orderbooks {
+		if format(pair) == symbol {
+			return pair, true
+		}
+	}
+	return Pair{}, false
+}
+
+func (e *Exchange) placeOrder(pair Pair, clientOrderID, side, timeInForce string, price, quantity decimal.Decimal) (*Order, error) {
+	orderbook, ok := e.orderbooks[pair]
+	if !ok {
+		return nil, errUnknownSymbol
+	}
+	if e.halted[pair] {
+		return nil, errNotTradable
+	}
+	if !price.GreaterThan(decimal.Zero) || !quantity.GreaterThan(decimal.Zero) {
+		return nil, errInvalidOrder
+	}
+	if err := e.filtersOf(pair).Validate(price, quantity); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFilterFailure, err)
+	}
+
+	side = strings.ToUpper(side)
+	timeInForce = strings.ToUpper(timeInForce)
+
+	var offers []coin.Offer
+	switch side {
+	case "BUY":
+		if e.balances[pair.Quote].LessThan(price.Mul(quantity)) {
+			return nil, errInsufficientBalance
+		}
+		offers = orderbook.Asks
+	case "SELL":
+		if e.balances[pair.Base].LessThan(quantity) {
+			return nil, errInsufficientBalance
+		}
+		offers = orderbook.Bids
+	default:
+		return nil, errInvalidOrder
+	}
+
+	crosses := func(offer coin.Offer) bool {
+		if side == "BUY" {
+			return !offer.Price.GreaterThan(price)
+		}
+		return !offer.Price.LessThan(price)
+	}
+
+	available := decimal.Zero
+	for _, offer := range offers {
+		if !crosses(offer) {
+			break
+		}
+		available = available.Add(offer.Quantity)
+	}
+
+	feeAsset := pair.Base
+	if side == "SELL" {
+		feeAsset = pair.Quote
+	}
+
+	e.lastID++
+	order := &Order{
+		ID:            strconv.FormatInt(e.lastID, 10),
+		ClientOrderID: clientOrderID,
+		Pair:          pair,
+		Side:          side,
+		TimeInForce:   timeInForce,
+		Price:         price,
+		Quantity:      quantity,
+		ExecutedQty:   decimal.Zero,
+		ExecutedQuote: decimal.Zero,
+		Fee:           decimal.Zero,
+		FeeAsset:      feeAsset,
+		Status:        StatusNew,
+		Time:          time.Now(),
+	}
+	e.orders[order.ID] = order
+
+	if timeInForce == "FOK" && available.LessThan(quantity) {
+		order.Status = StatusCanceled
+		return order, nil
+	}
+
+	remaining := quantity
+	consumed := 0
+	for i := range offers {
+		if !remaining.GreaterThan(decimal.Zero) || !crosses(offers[i]) {
+			break
+		}
+		qty := decimal.Min(offers[i].Quantity, remaining)
+		remaining = remaining.Sub(qty)
+		offers[i].Quantity = offers[i].Quantity.Sub(qty)
+		order.ExecutedQty = order.ExecutedQty.Add(qty)
+		order.ExecutedQuote = order.ExecutedQuote.Add(qty.Mul(offers[i].Price))
+		if offers[i].Quantity.Equal(decimal.Zero) {
+			consumed++
+		}
+	}
+
+	if side == "BUY" {
+		orderbook.Asks = offers[consumed:]
+		order.Fee = order.ExecutedQty.Mul(e.feeRate)
+		e.balances[pair.Quote] = e.balances[pair.Quote].Sub(order.ExecutedQuote)
+		e.balances[pair.Base] = e.balances[pair.Base].Add(order.ExecutedQty.Sub(order.Fee))
+	} else {
+		orderbook.Bids = offers[consumed:]
+		order.Fee = order.ExecutedQuote.Mul(e.feeRate)
+		e.balances[pair.Base] = e.balances[pair.Base].Sub(order.ExecutedQty)
+		e.balances[pair.Quote] = e.balances[pair.Quote].Add(order.ExecutedQuote.Sub(order.Fee))
+	}
+	e.orderbooks[pair] = orderbook
+
+	switch {
+	case order.ExecutedQty.Equal(quantity):
+		order.Status = StatusFilled
+	case timeInForce == "IOC" || timeInForce == "FOK":
+		order.Status = StatusCanceled
+	default:
+		// The remaining of a GTC order rests, its funds are locked until it is filled or cancelled
+		if order.ExecutedQty.GreaterThan(decimal.Zero) {
+			order.Status = StatusPartiallyFilled
+		}
+		e.lock(order, true)
+	}
+
+	return order, nil
+}
+
+func (e *Exchange) cancelOrder(id string) (*Order, error) {
+	order, ok := e.orders[id]
+	if !ok {
+		return nil, errUnknownOrder
+	}
+	if !order.IsOpen() {
+		return nil, errUnknownOrder
+	}
+
+	e.lock(order, false)
+	order.Status = StatusCanceled
+
+	return order, nil
+}
+
+// lock moves the funds needed by the remaining of an order between the free and the locked balances
+func (e *Exchange) lock(order *Order, lock bool) {
+	asset := order.Pair.Base
+	amount := order.Quantity.Sub(order.ExecutedQty)
+	if order.Side == "BUY" {
+		asset = order.Pair.Quote
+		amount = amount.Mul(order.Price)
+	}
+	if !lock {
+		amount = amount.Neg()
+	}
+
+	e.balances[asset] = e.balances[asset].Sub(amount)
+	e.locked[asset] = e.locked[asset].Add(amount)
+}
+
+// assets returns the assets having a balance, sorted
+func (e *Exchange) assets() []string {
+	assets := make([]string, 0, len(e.balances))
+	for asset := range e.balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	return assets
+}
+
+// coins returns the coins having networks, sorted
+func (e *Exchange) coins() []string {
+	coins := make([]string, 0, len(e.networks))
+	for c := range e.networks {
+		coins = append(coins, c)
+	}
+	sort.Strings(coins)
+	return coins
+}
+
+func copyOrderBook(orderbook coin.OrderBook) coin.OrderBook {
+	return coin.OrderBook{
+		Bids: append([]coin.Offer{}, orderbook.Bids...),
+		Asks: append([]coin.Offer{}, orderbook.Asks...),
+	}
+}
+
+// serverTime answers the time of the exchange, in the format shared by Binance, MEXC and Bitrue
+func (e *Exchange) serverTime(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]int64{"serverTime": millis(e.now())})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func readBody(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+	body, _ := io.ReadAll(r.Body)
+	return body
+}
+
+// levels converts an order book side in the [["price","quantity"], ...] format used by every exchange
+func levels(offers []coin.Offer) [][]string {
+	out := make([][]string, 0, len(offers))
+	for _, offer := range offers {
+		out = append(out, []string{offer.Price.String(), offer.Quantity.String()})
+	}
+	return out
+}
+
+func bestOffers(orderbook coin.OrderBook) (bid, ask coin.Offer) {
+	bid = coin.Offer{Price: decimal.Zero, Quantity: decimal.Zero}
+	ask = coin.Offer{Price: decimal.Zero, Quantity: decimal.Zero}
+	if len(orderbook.Bids) > 0 {
+		bid = orderbook.Bids[0]
+	}
+	if len(orderbook.Asks) > 0 {
+		ask = orderbook.Asks[0]
+	}
+	return bid, ask
+}
+
+// precision returns the number of decimals of a step, e.g. 2 for 0.01, as the exchanges giving precisions expect
+func precision(step decimal.Decimal) int {
+	if !step.IsPositive() {
+		return 0
+	}
+	return int(-step.Exponent())
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
@@ -0,0 +1,183 @@
+package fakeexchange_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/coin"
+	"github.com/ArbitrageCoin/crypto-sdk/src/fakeexchange"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/mexcsdk"
+	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/xt_com"
+	"github.com/gateio/gateapi-go/v6"
+	"github.com/shopspring/decimal"
+)
+
+func orderBook() coin.OrderBook {
+	return coin.OrderBook{
+		Bids: []coin.Offer{
+			{Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(1)},
+			{Price: decimal.NewFromInt(98), Quantity: decimal.NewFromInt(2)},
+		},
+		Asks: []coin.Offer{
+			{Price: decimal.NewFromInt(101), Quantity: decimal.NewFromInt(1)},
+			{Price: decimal.NewFromInt(102), Quantity: decimal.NewFromInt(2)},
+		},
+	}
+}
+
+func TestMEXCSignature(t *testing.T) {
+	ex := fakeexchange.NewMEXC("key", "secret")
+	defer ex.Close()
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+	// The client targets the real endpoint, the transport reroutes it
+	client := mexcsdk.NewClient("", &http.Client{Transport: fakeexchange.Transport(ex)})
+	balance, err := client.GetBalance(context.Background(), "key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balance.Balances) != 1 || !balance.Balances[0].Free.Equal(decimal.NewFromInt(1000)) {
+		t.Fatalf("unexpected balance %+v", balance)
+	}
+
+	if _, err := client.GetBalance(context.Background(), "key", "wrong"); err == nil {
+		t.Fatal("expected a wrong secret to be rejected")
+	}
+}
+
+func TestMEXCFillOrKill(t *testing.T) {
+	ex := fakeexchange.NewMEXC("key", "secret")
+	t.Cleanup(ex.Close)
+	client := mexcsdk.NewClient(ex.URL(), nil)
+	ex.SetOrderBook("BTC", "USDT", orderBook())
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+	// Only 3 BTC are offered below 102, the order is killed
+	if _, err := client.PostOrder(context.Background(), "key", "secret", mexcsdk.Order{
+		Symbol:   "BTCUSDT",
+		Side:     mexcsdk.BUY,
+		Type:     mexcsdk.FILL_OR_KILL,
+		Quantity: decimal.NewFromInt(4),
+		Price:    decimal.NewFromInt(102),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Balance("USDT").Equal(decimal.NewFromInt(1000)) {
+		t.Fatalf("the killed order changed the balance: %s", ex.Balance("USDT"))
+	}
+
+	if _, err := client.PostOrder(context.Background(), "key", "secret", mexcsdk.Order{
+		Symbol:   "BTCUSDT",
+		Side:     mexcsdk.BUY,
+		Type:     mexcsdk.FILL_OR_KILL,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(102),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Balance("BTC").Equal(decimal.NewFromInt(2)) || !ex.Balance("USDT").Equal(decimal.NewFromInt(797)) {
+		t.Fatalf("unexpected balances BTC=%s USDT=%s", ex.Balance("BTC"), ex.Balance("USDT"))
+	}
+
+	orders := ex.Orders()
+	if len(orders) != 2 || orders[0].Status != fakeexchange.StatusCanceled || orders[1].Status != fakeexchange.StatusFilled {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+}
+
+func TestGateImmediateOrCancel(t *testing.T) {
+	ex := fakeexchange.NewGate("key", "secret")
+	t.Cleanup(ex.Close)
+	ex.SetOrderBook("BTC", "USDT", orderBook())
+	ex.SetBalance("BTC", decimal.NewFromInt(5))
+
+	config := gateapi.NewConfiguration()
+	config.Key = "key"
+	config.Secret = "secret"
+	config.BasePath = ex.URL() + "/api/v4"
+	client := gateapi.NewAPIClient(config)
+
+	ctx := context.Background()
+	order, _, err := client.SpotApi.CreateOrder(ctx, gateapi.Order{
+		Account:      "spot",
+		CurrencyPair: "BTC_USDT",
+		Side:         "sell",
+		Type:         "limit",
+		Amount:       "5",
+		Price:        "98",
+		TimeInForce:  "ioc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "cancelled" || order.FinishAs != "ioc" || order.FilledAmount != "3" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if !ex.Balance("BTC").Equal(decimal.NewFromInt(2)) || !ex.Balance("USDT").Equal(decimal.NewFromInt(295)) {
+		t.Fatalf("unexpected balances BTC=%s USDT=%s", ex.Balance("BTC"), ex.Balance("USDT"))
+	}
+
+	config.Secret = "wrong"
+	if _, _, err := gateapi.NewAPIClient(config).SpotApi.ListSpotAccounts(ctx, nil); err == nil {
+		t.Fatal("expected a wrong secret to be rejected")
+	}
+}
+
+func TestXTSignature(t *testing.T) {
+	ex := fakeexchange.NewXT("key", "secret")
+	t.Cleanup(ex.Close)
+	option := xt_com.HttpOption{BaseURL: ex.URL()}
+	ex.SetOrderBook("BTC", "USDT", orderBook())
+	ex.SetBalance("USDT", decimal.NewFromInt(1000))
+
+	client := xt_com.SignedHttpAPI{Accesskey: "key", Secretkey: "secret", HttpOption: option}
+	resp := client.SendOrder(context.Background(), map[string]interface{}{
+		"symbol":      "btc_usdt",
+		"side":        "BUY",
+		"type":        "LIMIT",
+		"timeInForce": "FOK",
+		"bizType":     "SPOT",
+		"price":       "101",
+		"quantity":    "1",
+	})
+
+	var sent struct {
+		RC     int `json:"rc"`
+		Result struct {
+			OrderID string `json:"orderId"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(resp.Data), &sent); err != nil {
+		t.Fatal(err, resp.Data)
+	}
+	if sent.RC != 0 || sent.Result.OrderID == "" {
+		t.Fatalf("unexpected answer %s", resp.Data)
+	}
+	if !ex.Balance("BTC").Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("unexpected BTC balance %s", ex.Balance("BTC"))
+	}
+
+	resp = client.GetOrder(context.Background(), map[string]interface{}{"orderId": sent.Result.OrderID})
+	var order struct {
+		Result struct {
+			State string `json:"state"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(resp.Data), &order); err != nil {
+		t.Fatal(err, resp.Data)
+	}
+	if order.Result.State != "FILLED" {
+		t.Fatalf("unexpected order %s", resp.Data)
+	}
+
+	wrong := xt_com.SignedHttpAPI{Accesskey: "key", Secretkey: "wrong", HttpOption: option}
+	resp = wrong.GetBalance(context.Background(), nil)
+	if err := json.Unmarshal([]byte(resp.Data), &sent); err != nil {
+		t.Fatal(err, resp.Data)
+	}
+	if sent.RC == 0 {
+		t.Fatalf("expected a wrong secret to be rejected, got %s", resp.Data)
+	}
+}
@@ -0,0 +1,500 @@
+package fakeexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func gateSymbol(pair Pair) string { return pair.Base + "_" + pair.Quote }
+
+func gateWriteLabel(w http.ResponseWriter, status int, label, message string) {
+	writeJSON(w, status, map[string]string{"label": label, "message": message})
+}
+
+func gateWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnknownSymbol):
+		gateWriteLabel(w, http.StatusBadRequest, "INVALID_CURRENCY_PAIR", err.Error())
+	case errors.Is(err, errNotTradable):
+		gateWriteLabel(w, http.StatusBadRequest, "TRADE_RESTRICTED", err.Error())
+	case errors.Is(err, errInsufficientBalance):
+		gateWriteLabel(w, http.StatusBadRequest, "BALANCE_NOT_ENOUGH", err.Error())
+	case errors.Is(err, errUnknownOrder):
+		gateWriteLabel(w, http.StatusNotFound, "ORDER_NOT_FOUND", err.Error())
+	default:
+		gateWriteLabel(w, http.StatusBadRequest, "INVALID_PARAM_VALUE", err.Error())
+	}
+}
+
+// NewGate starts a fake https://api.gateio.ws accepting orders signed with key and secret
+func NewGate(key, secret string) *Exchange {
+	return newExchange("Gate", "api.gateio.ws", key, secret, gateRoutes)
+}
+
+func gateRoutes(e *Exchange, mux *http.ServeMux) {
+	signed := func(handler func(http.ResponseWriter, *http.Request, []byte)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body := readBody(r)
+			if !e.checkGateSignature(r, body) {
+				gateWriteLabel(w, http.StatusUnauthorized, "INVALID_SIGNATURE", "Signature mismatch")
+				return
+			}
+			handler(w, r, body)
+		}
+	}
+
+	mux.HandleFunc("GET /api/v4/spot/tickers", e.gateTickers)
+	mux.HandleFunc("GET /api/v4/spot/order_book", e.gateOrderBook)
+	mux.HandleFunc("GET /api/v4/spot/currency_pairs", e.gateCurrencyPairs)
+	mux.HandleFunc("GET /api/v4/wallet/currency_chains", e.gateCurrencyChains)
+	mux.HandleFunc("GET /api/v4/spot/currencies", e.gateCurrencies)
+	mux.HandleFunc("GET /api/v4/wallet/withdraw_status", signed(e.gateWithdrawStatus))
+	mux.HandleFunc("GET /api/v4/spot/accounts", signed(e.gateAccounts))
+	mux.HandleFunc("GET /api/v4/account/detail", signed(e.gateAccountDetail))
+	mux.HandleFunc("GET /api/v4/wallet/deposit_address", signed(e.gateDepositAddress))
+	mux.HandleFunc("POST /api/v4/withdrawals", signed(e.gateWithdraw))
+	mux.HandleFunc("GET /api/v4/wallet/withdrawals", signed(e.gateWithdrawals))
+	mux.HandleFunc("GET /api/v4/wallet/deposits", signed(e.gateDeposits))
+	mux.HandleFunc("POST /api/v4/spot/orders", signed(e.gatePostOrder))
+	mux.HandleFunc("GET /api/v4/spot/orders", signed(e.gateListOrders))
+	mux.HandleFunc("DELETE /api/v4/spot/orders", signed(e.gateCancelOrders))
+	mux.HandleFunc("GET /api/v4/spot/orders/{id}", signed(e.gateGetOrder))
+	mux.HandleFunc("DELETE /api/v4/spot/orders/{id}", signed(e.gateCancelOrder))
+}
+
+func (e *Exchange) gateTickers(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickers := []map[string]string{}
+	for _, pair := range e.pairs() {
+		bid, ask := bestOffers(e.orderbooks[pair])
+		tickers = append(tickers, map[string]string{
+			"currency_pair": gateSymbol(pair),
+			"lowest_ask":    ask.Price.String(),
+			"highest_bid":   bid.Price.String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, tickers)
+}
+
+func (e *Exchange) gateOrderBook(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("currency_pair"), gateSymbol)
+	if !ok {
+		gateWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orderbook := e.orderbooks[pair]
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      e.lastID,
+		"current": millis(time.Now()),
+		"update":  millis(time.Now()),
+		"bids":    levels(orderbook.Bids),
+		"asks":    levels(orderbook.Asks),
+	})
+}
+
+func (e *Exchange) gateCurrencyPairs(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pairs := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		status := "tradable"
+		if e.halted[pair] {
+			status = "untradable"
+		}
+		filters := e.filtersOf(pair)
+		pairs = append(pairs, map[string]interface{}{
+			"id":               gateSymbol(pair),
+			"base":             pair.Base,
+			"quote":            pair.Quote,
+			"fee":              "0.2",
+			"min_base_amount":  filters.MinQuantity.String(),
+			"min_quote_amount": filters.MinNotional.String(),
+			"max_base_amount":  filters.MaxQuantity.String(),
+			"amount_precision": precision(filters.StepSize),
+			"precision":        precision(filters.TickSize),
+			"trade_status":     status,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, pairs)
+}
+
+func (e *Exchange) gateCurrencyChains(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	boolToInt := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	chains := []map[string]interface{}{}
+	for _, network := range e.networks[strings.ToUpper(r.URL.Query().Get("currency"))] {
+		if network.Unusable {
+			continue
+		}
+		chains = append(chains, map[string]interface{}{
+			"chain":                network.Network,
+			"name_en":              network.Network,
+			"is_disabled":          boolToInt(!network.DepositEnable && !network.WithdrawEnable),
+			"is_deposit_disabled":  boolToInt(!network.DepositEnable),
+			"is_withdraw_disabled": boolToInt(!network.WithdrawEnable),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, chains)
+}
+
+func (e *Exchange) gateAccounts(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	accounts := []map[string]string{}
+	for _, asset := range e.assets() {
+		accounts = append(accounts, map[string]string{
+			"currency":  asset,
+			"available": e.balances[asset].String(),
+			"locked":    e.locked[asset].String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (e *Exchange) gateAccountDetail(w http.ResponseWriter, r *http.Request, body []byte) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id": 1,
+		"key":     map[string]int{"mode": 1},
+	})
+}
+
+func gateOrder(order *Order) map[string]interface{} {
+	status, finishAs := "open", "open"
+	switch order.Status {
+	case StatusFilled:
+		status, finishAs = "closed", "filled"
+	case StatusCanceled:
+		status, finishAs = "cancelled", "cancelled"
+		switch order.TimeInForce {
+		case "IOC":
+			finishAs = "ioc"
+		case "FOK":
+			finishAs = "fok"
+		}
+	}
+
+	avgPrice := decimal.Zero
+	if order.ExecutedQty.GreaterThan(decimal.Zero) {
+		avgPrice = order.ExecutedQuote.Div(order.ExecutedQty)
+	}
+
+	return map[string]interface{}{
+		"id":             order.ID,
+		"text":           order.ClientOrderID,
+		"create_time":    strconv.FormatInt(order.Time.Unix(), 10),
+		"create_time_ms": millis(order.Time),
+		"status":         status,
+		"currency_pair":  gateSymbol(order.Pair),
+		"type":           "limit",
+		"account":        "spot",
+		"side":           strings.ToLower(order.Side),
+		"amount":         order.Quantity.String(),
+		"price":          order.Price.String(),
+		"time_in_force":  strings.ToLower(order.TimeInForce),
+		"left":           order.Quantity.Sub(order.ExecutedQty).String(),
+		"filled_amount":  order.ExecutedQty.String(),
+		"filled_total":   order.ExecutedQuote.String(),
+		"fill_price":     order.ExecutedQuote.String(),
+		"avg_deal_price": avgPrice.String(),
+		"fee":            order.Fee.String(),
+		"fee_currency":   order.FeeAsset,
+		"finish_as":      finishAs,
+	}
+}
+
+func (e *Exchange) gatePostOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var params struct {
+		Text         string `json:"text"`
+		CurrencyPair string `json:"currency_pair"`
+		Side         string `json:"side"`
+		Amount       string `json:"amount"`
+		Price        string `json:"price"`
+		TimeInForce  string `json:"time_in_force"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		gateWriteError(w, errInvalidOrder)
+		return
+	}
+
+	pair, ok := e.findPair(params.CurrencyPair, gateSymbol)
+	if !ok {
+		gateWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	timeInForce := params.TimeInForce
+	if timeInForce == "" {
+		timeInForce = "gtc"
+	}
+
+	price, _ := decimal.NewFromString(params.Price)
+	amount, _ := decimal.NewFromString(params.Amount)
+
+	order, err := e.placeOrder(pair, params.Text, params.Side, timeInForce, price, amount)
+	if err != nil {
+		gateWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, gateOrder(order))
+}
+
+func (e *Exchange) gateGetOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[r.PathValue("id")]
+	if !ok {
+		gateWriteError(w, errUnknownOrder)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateOrder(order))
+}
+
+func (e *Exchange) gateCancelOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.cancelOrder(r.PathValue("id"))
+	if err != nil {
+		gateWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateOrder(order))
+}
+
+// gateListOrders only knows the open orders, the finished ones are looked up one by one
+func (e *Exchange) gateListOrders(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("currency_pair"), gateSymbol)
+	if !ok {
+		gateWriteError(w, errUnknownSymbol)
+		return
+	}
+	if query.Get("status") != "open" {
+		gateWriteError(w, errInvalidOrder)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		orders = append(orders, gateOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (e *Exchange) gateCancelOrders(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("currency_pair"), gateSymbol)
+	if !ok {
+		gateWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		order, _ = e.cancelOrder(order.ID)
+		orders = append(orders, gateOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// gateDepositAddress answers the addresses of every network of the currency
+func (e *Exchange) gateDepositAddress(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	addresses := []map[string]interface{}{}
+	for _, network := range e.networks[currency] {
+		addresses = append(addresses, map[string]interface{}{
+			"chain":         network.Network,
+			"address":       depositAddress(e.name, currency, network.Network),
+			"payment_id":    "",
+			"payment_name":  "",
+			"obtain_failed": 0,
+		})
+	}
+	if len(addresses) == 0 {
+		gateWriteError(w, errUnknownNetwork)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"currency":             currency,
+		"address":              addresses[0]["address"],
+		"multichain_addresses": addresses,
+	})
+}
+
+// gateTransferStatus returns EXTPEND (sent, pending confirmation), DONE or CANCEL
+func gateTransferStatus(status TransferStatus) string {
+	switch status {
+	case TransferCompleted:
+		return "DONE"
+	case TransferFailed:
+		return "CANCEL"
+	default:
+		return "EXTPEND"
+	}
+}
+
+func gateTransfer(transfer *Transfer) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        transfer.ID,
+		"txid":      transfer.TxID,
+		"timestamp": strconv.FormatInt(transfer.Time.Unix(), 10),
+		"amount":    transfer.Amount.String(),
+		"fee":       transfer.Fee.String(),
+		"currency":  transfer.Coin,
+		"address":   transfer.Address,
+		"memo":      transfer.Memo,
+		"status":    gateTransferStatus(transfer.Status),
+		"chain":     transfer.Network,
+	}
+}
+
+func (e *Exchange) gateWithdraw(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var params struct {
+		Currency string `json:"currency"`
+		Chain    string `json:"chain"`
+		Address  string `json:"address"`
+		Memo     string `json:"memo"`
+		Amount   string `json:"amount"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		gateWriteError(w, errInvalidOrder)
+		return
+	}
+
+	amount, _ := decimal.NewFromString(params.Amount)
+	transfer, err := e.withdraw(params.Currency, params.Chain, params.Address, params.Memo, amount)
+	if err != nil {
+		gateWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateTransfer(transfer))
+}
+
+func (e *Exchange) gateWithdrawals(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	transfers, _ := historyPage(e.transfersOf(query.Get("currency"), true), queryInt(query, "offset", 0), queryInt(query, "limit", 100))
+	withdrawals := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		withdrawals = append(withdrawals, gateTransfer(transfer))
+	}
+
+	writeJSON(w, http.StatusOK, withdrawals)
+}
+
+func (e *Exchange) gateDeposits(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	transfers, _ := historyPage(e.transfersOf(query.Get("currency"), false), queryInt(query, "offset", 0), queryInt(query, "limit", 100))
+	deposits := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		deposits = append(deposits, gateTransfer(transfer))
+	}
+
+	writeJSON(w, http.StatusOK, deposits)
+}
+
+// gateCurrencies lists a currency per network, suffixed with the chain when the coin has several, e.g. USDT_TRX
+func (e *Exchange) gateCurrencies(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	currencies := []map[string]interface{}{}
+	for _, c := range e.coins() {
+		networks := e.networks[c]
+		for _, network := range networks {
+			currency := c
+			if len(networks) > 1 {
+				currency = c + "_" + network.Network
+			}
+			currencies = append(currencies, map[string]interface{}{
+				"currency":          currency,
+				"chain":             network.Network,
+				"deposit_disabled":  !network.DepositEnable,
+				"withdraw_disabled": !network.WithdrawEnable,
+				"withdraw_delayed":  false,
+				"trade_disabled":    false,
+				"delisted":          false,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, currencies)
+}
+
+func (e *Exchange) gateWithdrawStatus(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	statuses := []map[string]interface{}{}
+	for _, c := range e.coins() {
+		fees := map[string]string{}
+		minimum := decimal.Zero
+		for i, network := range e.networks[c] {
+			fees[network.Network] = network.WithdrawFee.String()
+			if i == 0 || network.WithdrawMin.LessThan(minimum) {
+				minimum = network.WithdrawMin
+			}
+		}
+		statuses = append(statuses, map[string]interface{}{
+			"currency":               c,
+			"name":                   c,
+			"withdraw_amount_mini":   minimum.String(),
+			"withdraw_fix_on_chains": fees,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, statuses)
+}
@@ -0,0 +1,480 @@
+package fakeexchange
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func mexcSymbol(pair Pair) string { return pair.Base + pair.Quote }
+
+type mexcError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func mexcWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnknownSymbol):
+		writeJSON(w, http.StatusBadRequest, mexcError{Code: -1121, Msg: "Invalid symbol."})
+	case errors.Is(err, errNotTradable):
+		writeJSON(w, http.StatusBadRequest, mexcError{Code: 10007, Msg: "symbol not support api"})
+	case errors.Is(err, errInsufficientBalance):
+		writeJSON(w, http.StatusBadRequest, mexcError{Code: 30004, Msg: "Insufficient position"})
+	case errors.Is(err, errUnknownOrder):
+		writeJSON(w, http.StatusBadRequest, mexcError{Code: -2013, Msg: "Order does not exist."})
+	default:
+		writeJSON(w, http.StatusBadRequest, mexcError{Code: 700004, Msg: err.Error()})
+	}
+}
+
+// NewMEXC starts a fake https://api.mexc.com accepting orders signed with key and secret
+func NewMEXC(key, secret string) *Exchange {
+	return newExchange("MEXC", "api.mexc.com", key, secret, mexcRoutes)
+}
+
+func mexcRoutes(e *Exchange, mux *http.ServeMux) {
+	signed := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !e.checkTimestamp(r.URL.Query().Get("timestamp")) {
+				writeJSON(w, http.StatusBadRequest, mexcError{Code: 700003, Msg: "Timestamp for this request is outside of the recvWindow."})
+				return
+			}
+			if !e.checkQuerySignature(r, readBody(r), "X-MEXC-APIKEY") {
+				writeJSON(w, http.StatusBadRequest, mexcError{Code: 700002, Msg: "Signature for this request is not valid."})
+				return
+			}
+			handler(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET /api/v3/time", e.serverTime)
+	mux.HandleFunc("GET /api/v3/depth", e.mexcDepth)
+	mux.HandleFunc("GET /api/v3/ticker/bookTicker", e.mexcBookTicker)
+	mux.HandleFunc("GET /api/v3/exchangeInfo", e.mexcExchangeInfo)
+	mux.HandleFunc("GET /api/v3/account", signed(e.mexcAccount))
+	mux.HandleFunc("GET /api/v3/capital/config/getall", signed(e.mexcCapitalConfig))
+	mux.HandleFunc("GET /api/v3/capital/deposit/address", signed(e.mexcDepositAddress))
+	mux.HandleFunc("POST /api/v3/capital/withdraw/apply", signed(e.mexcWithdraw))
+	mux.HandleFunc("GET /api/v3/capital/withdraw/history", signed(e.mexcWithdrawHistory))
+	mux.HandleFunc("GET /api/v3/capital/deposit/hisrec", signed(e.mexcDepositHistory))
+	mux.HandleFunc("POST /api/v3/order", signed(e.mexcPostOrder))
+	mux.HandleFunc("GET /api/v3/order", signed(e.mexcGetOrder))
+	mux.HandleFunc("DELETE /api/v3/order", signed(e.mexcCancelOrder))
+	mux.HandleFunc("GET /api/v3/openOrders", signed(e.mexcOpenOrders))
+	mux.HandleFunc("DELETE /api/v3/openOrders", signed(e.mexcCancelOpenOrders))
+	mux.HandleFunc("GET /api/v3/myTrades", signed(e.mexcMyTrades))
+}
+
+func (e *Exchange) mexcDepth(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), mexcSymbol)
+	if !ok {
+		mexcWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orderbook := e.orderbooks[pair]
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lastUpdateId": e.lastID,
+		"bids":         levels(orderbook.Bids),
+		"asks":         levels(orderbook.Asks),
+		"timestamp":    millis(time.Now()),
+	})
+}
+
+func (e *Exchange) mexcBookTicker(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickers := []map[string]string{}
+	for _, pair := range e.pairs() {
+		bid, ask := bestOffers(e.orderbooks[pair])
+		tickers = append(tickers, map[string]string{
+			"symbol":   mexcSymbol(pair),
+			"bidPrice": bid.Price.String(),
+			"bidQty":   bid.Quantity.String(),
+			"askPrice": ask.Price.String(),
+			"askQty":   ask.Quantity.String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, tickers)
+}
+
+func (e *Exchange) mexcExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	symbols := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		status := "ENABLED"
+		if e.halted[pair] {
+			status = "DISABLED"
+		}
+		filters := e.filtersOf(pair)
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":               mexcSymbol(pair),
+			"status":               status,
+			"baseAsset":            pair.Base,
+			"baseAssetPrecision":   precision(filters.StepSize),
+			"quoteAsset":           pair.Quote,
+			"quotePrecision":       precision(filters.TickSize),
+			"orderTypes":           []string{"LIMIT", "MARKET", "LIMIT_MAKER"},
+			"isSpotTradingAllowed": true,
+			"permissions":          []string{"SPOT"},
+			"filters":              []string{},
+			"baseSizePrecision":    filters.MinQuantity.String(),
+			"quoteAmountPrecision": filters.MinNotional.String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":        "CST",
+		"serverTime":      millis(time.Now()),
+		"rateLimits":      []string{},
+		"exchangeFilters": []string{},
+		"symbols":         symbols,
+	})
+}
+
+func (e *Exchange) mexcAccount(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	balances := []map[string]string{}
+	for _, asset := range e.assets() {
+		balances = append(balances, map[string]string{
+			"asset":  asset,
+			"free":   e.balances[asset].String(),
+			"locked": e.locked[asset].String(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"canTrade":    true,
+		"canWithdraw": true,
+		"canDeposit":  true,
+		"accountType": "SPOT",
+		"balances":    balances,
+		"permissions": []string{"SPOT"},
+	})
+}
+
+func (e *Exchange) mexcCapitalConfig(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	coins := []map[string]interface{}{}
+	for _, coinName := range e.coins() {
+		networkList := []map[string]interface{}{}
+		for _, network := range e.networks[coinName] {
+			networkList = append(networkList, map[string]interface{}{
+				"coin":           coinName,
+				"Name":           coinName,
+				"network":        network.Network,
+				"netWork":        network.Network,
+				"depositEnable":  network.DepositEnable,
+				"withdrawEnable": network.WithdrawEnable,
+				"withdrawFee":    network.WithdrawFee.String(),
+				"withdrawMin":    network.WithdrawMin.String(),
+				"withdrawMax":    network.WithdrawMax.String(),
+				"minConfirm":     network.MinConfirm,
+				"sameAddress":    false,
+			})
+		}
+		coins = append(coins, map[string]interface{}{
+			"coin":        coinName,
+			"Name":        coinName,
+			"networkList": networkList,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, coins)
+}
+
+func mexcOrder(order *Order) map[string]interface{} {
+	status := string(order.Status)
+	if order.Status == StatusCanceled && order.ExecutedQty.GreaterThan(decimal.Zero) {
+		status = "PARTIALLY_CANCELED"
+	}
+
+	orderType := "LIMIT"
+	switch order.TimeInForce {
+	case "IOC":
+		orderType = "IMMEDIATE_OR_CANCEL"
+	case "FOK":
+		orderType = "FILL_OR_KILL"
+	}
+
+	return map[string]interface{}{
+		"symbol":              mexcSymbol(order.Pair),
+		"orderId":             order.ID,
+		"orderListId":         -1,
+		"clientOrderId":       order.ClientOrderID,
+		"price":               order.Price.String(),
+		"origQty":             order.Quantity.String(),
+		"executedQty":         order.ExecutedQty.String(),
+		"cummulativeQuoteQty": order.ExecutedQuote.String(),
+		"status":              status,
+		"timeInForce":         order.TimeInForce,
+		"type":                orderType,
+		"side":                order.Side,
+		"time":                millis(order.Time),
+		"updateTime":          millis(order.Time),
+		"isWorking":           order.IsOpen(),
+		"transactTime":        millis(order.Time),
+	}
+}
+
+func (e *Exchange) mexcPostOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), mexcSymbol)
+	if !ok {
+		mexcWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	timeInForce := "GTC"
+	switch query.Get("type") {
+	case "IMMEDIATE_OR_CANCEL":
+		timeInForce = "IOC"
+	case "FILL_OR_KILL":
+		timeInForce = "FOK"
+	}
+
+	price, _ := decimal.NewFromString(query.Get("price"))
+	quantity, _ := decimal.NewFromString(query.Get("quantity"))
+
+	order, err := e.placeOrder(pair, query.Get("newClientOrderId"), query.Get("side"), timeInForce, price, quantity)
+	if err != nil {
+		mexcWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mexcOrder(order))
+}
+
+func (e *Exchange) mexcGetOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[r.URL.Query().Get("orderId")]
+	if !ok {
+		mexcWriteError(w, errUnknownOrder)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mexcOrder(order))
+}
+
+// mexcMyTrades answers a single trade per order, filled at its average price
+func (e *Exchange) mexcMyTrades(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), mexcSymbol)
+	if !ok {
+		mexcWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	trades := []map[string]interface{}{}
+	for _, order := range e.trades(pair, query.Get("orderId")) {
+		trades = append(trades, map[string]interface{}{
+			"symbol":          mexcSymbol(order.Pair),
+			"id":              order.ID,
+			"orderId":         order.ID,
+			"price":           order.ExecutedQuote.Div(order.ExecutedQty).String(),
+			"qty":             order.ExecutedQty.String(),
+			"quoteQty":        order.ExecutedQuote.String(),
+			"commission":      order.Fee.String(),
+			"commissionAsset": order.FeeAsset,
+			"time":            millis(order.Time),
+			"isBuyer":         order.Side == "BUY",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, trades)
+}
+
+func (e *Exchange) mexcCancelOrder(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.cancelOrder(r.URL.Query().Get("orderId"))
+	if err != nil {
+		mexcWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mexcOrder(order))
+}
+
+func (e *Exchange) mexcOpenOrders(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), mexcSymbol)
+	if !ok {
+		mexcWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		orders = append(orders, mexcOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (e *Exchange) mexcCancelOpenOrders(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), mexcSymbol)
+	if !ok {
+		mexcWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		order, _ = e.cancelOrder(order.ID)
+		orders = append(orders, mexcOrder(order))
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (e *Exchange) mexcDepositAddress(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	address, err := e.depositAddress(query.Get("coin"), query.Get("network"))
+	if err != nil {
+		mexcWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []map[string]string{{
+		"coin":    strings.ToUpper(query.Get("coin")),
+		"network": query.Get("network"),
+		"address": address,
+		"memo":    "",
+	}})
+}
+
+func (e *Exchange) mexcWithdraw(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	amount, _ := decimal.NewFromString(query.Get("amount"))
+	transfer, err := e.withdraw(query.Get("coin"), query.Get("network"), query.Get("address"), query.Get("memo"), amount)
+	if err != nil {
+		mexcWriteError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": transfer.ID})
+}
+
+// mexcWithdrawStatus returns 6 (WAIT_CONFIRM), 7 (SUCCESS) or 8 (FAILED)
+func mexcWithdrawStatus(status TransferStatus) int {
+	switch status {
+	case TransferCompleted:
+		return 7
+	case TransferFailed:
+		return 8
+	default:
+		return 6
+	}
+}
+
+func (e *Exchange) mexcWithdrawHistory(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	transfers, _ := historyPage(mexcHistory(e.transfersOf(r.URL.Query().Get("coin"), true), r.URL.Query()), 0, queryInt(r.URL.Query(), "limit", 1000))
+	withdrawals := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		withdrawals = append(withdrawals, map[string]interface{}{
+			"id":             transfer.ID,
+			"txId":           transfer.TxID,
+			"coin":           transfer.Coin,
+			"network":        transfer.Network,
+			"address":        transfer.Address,
+			"memo":           transfer.Memo,
+			"amount":         transfer.Amount.String(),
+			"transactionFee": transfer.Fee.String(),
+			"status":         mexcWithdrawStatus(transfer.Status),
+			"applyTime":      millis(transfer.Time),
+			"transferType":   0,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, withdrawals)
+}
+
+// mexcHistory returns the transfers created up to the endTime of query, all of them when missing
+func mexcHistory(transfers []*Transfer, query url.Values) []*Transfer {
+	endTime := int64(queryInt(query, "endTime", 0))
+	if endTime == 0 {
+		return transfers
+	}
+
+	history := []*Transfer{}
+	for _, transfer := range transfers {
+		if millis(transfer.Time) <= endTime {
+			history = append(history, transfer)
+		}
+	}
+	return history
+}
+
+// mexcDepositStatus returns 4 (PENDING), 5 (SUCCESS) or 7 (REJECTED)
+func mexcDepositStatus(status TransferStatus) int {
+	switch status {
+	case TransferCompleted:
+		return 5
+	case TransferFailed:
+		return 7
+	default:
+		return 4
+	}
+}
+
+func (e *Exchange) mexcDepositHistory(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	transfers, _ := historyPage(mexcHistory(e.transfersOf(r.URL.Query().Get("coin"), false), r.URL.Query()), 0, queryInt(r.URL.Query(), "limit", 1000))
+	deposits := []map[string]interface{}{}
+	for _, transfer := range transfers {
+		deposits = append(deposits, map[string]interface{}{
+			"amount":     transfer.Amount.String(),
+			"coin":       transfer.Coin,
+			"network":    transfer.Network,
+			"status":     mexcDepositStatus(transfer.Status),
+			"address":    transfer.Address,
+			"memo":       transfer.Memo,
+			"txId":       transfer.TxID,
+			"insertTime": millis(transfer.Time),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, deposits)
+}
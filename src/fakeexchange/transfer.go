@@ -0,0 +1,217 @@
+package fakeexchange
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	errUnknownNetwork   = errors.New("unknown network")
+	errWithdrawDisabled = errors.New("withdrawals are disabled")
+	errUnknownTransfer  = errors.New("unknown transfer")
+)
+
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "PENDING"
+	TransferCompleted TransferStatus = "COMPLETED"
+	TransferFailed    TransferStatus = "FAILED"
+)
+
+// Transfer is a withdrawal sent from the account, or a deposit received on it
+type Transfer struct {
+	ID         string
+	Withdrawal bool
+	Coin       string
+	Network    string
+	Address    string
+	Memo       string
+	Amount     decimal.Decimal
+	Fee        decimal.Decimal // only charged on withdrawals
+	TxID       string
+	Status     TransferStatus
+	Time       time.Time
+}
+
+// Deposit registers a pending deposit of amount coin, it is credited once completed by SetTransferStatus
+func (e *Exchange) Deposit(coinName, network, txID string, amount decimal.Decimal) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastID++
+	transfer := &Transfer{
+		ID:      strconv.FormatInt(e.lastID, 10),
+		Coin:    strings.ToUpper(coinName),
+		Network: network,
+		Address: depositAddress(e.name, coinName, network),
+		Amount:  amount,
+		Fee:     decimal.Zero,
+		TxID:    txID,
+		Status:  TransferPending,
+		Time:    e.transferTime(),
+	}
+	e.transfers = append(e.transfers, transfer)
+	return transfer.ID
+}
+
+// SetTransferStatus completes or fails a pending transfer: a completed deposit is credited and a
+// failed withdrawal is refunded
+func (e *Exchange) SetTransferStatus(id string, status TransferStatus) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	transfer, err := e.transfer(id)
+	if err != nil {
+		return err
+	}
+	if transfer.Status != TransferPending {
+		return fmt.Errorf("transfer %v is not pending", id)
+	}
+
+	transfer.Status = status
+	switch {
+	case !transfer.Withdrawal && status == TransferCompleted:
+		e.balances[transfer.Coin] = e.balances[transfer.Coin].Add(transfer.Amount)
+	case transfer.Withdrawal && status == TransferFailed:
+		e.balances[transfer.Coin] = e.balances[transfer.Coin].Add(transfer.Amount)
+	}
+	return nil
+}
+
+// Transfers returns every withdrawal and deposit, sorted by creation
+func (e *Exchange) Transfers() []Transfer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	transfers := make([]Transfer, len(e.transfers))
+	for i, transfer := range e.transfers {
+		transfers[i] = *transfer
+	}
+	return transfers
+}
+
+func (e *Exchange) network(coinName, network string) (Network, bool) {
+	for _, n := range e.networks[strings.ToUpper(coinName)] {
+		if strings.EqualFold(n.Network, network) {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// depositAddress returns the address of the account for coin on network, it fails when the network is unknown
+func (e *Exchange) depositAddress(coinName, network string) (string, error) {
+	if _, ok := e.network(coinName, network); !ok {
+		return "", errUnknownNetwork
+	}
+	return depositAddress(e.name, coinName, network), nil
+}
+
+func depositAddress(exchange, coinName, network string) string {
+	return strings.ToLower(exchange + "-" + coinName + "-" + network)
+}
+
+// withdraw debits amount from the balance, the fee of the network being taken from it
+func (e *Exchange) withdraw(coinName, network, address, memo string, amount decimal.Decimal) (*Transfer, error) {
+	n, ok := e.network(coinName, network)
+	if !ok {
+		return nil, errUnknownNetwork
+	}
+	if !n.WithdrawEnable {
+		return nil, errWithdrawDisabled
+	}
+	coinName = strings.ToUpper(coinName)
+	if address == "" || !amount.GreaterThan(n.WithdrawFee) || amount.LessThan(n.WithdrawMin) {
+		return nil, errInvalidOrder
+	}
+	if e.balances[coinName].LessThan(amount) {
+		return nil, errInsufficientBalance
+	}
+
+	e.balances[coinName] = e.balances[coinName].Sub(amount)
+
+	e.lastID++
+	transfer := &Transfer{
+		ID:         strconv.FormatInt(e.lastID, 10),
+		Withdrawal: true,
+		Coin:       coinName,
+		Network:    n.Network,
+		Address:    address,
+		Memo:       memo,
+		Amount:     amount,
+		Fee:        n.WithdrawFee,
+		TxID:       fmt.Sprintf("0x%064x", e.lastID),
+		Status:     TransferPending,
+		Time:       e.transferTime(),
+	}
+	e.transfers = append(e.transfers, transfer)
+	return transfer, nil
+}
+
+func (e *Exchange) transfer(id string) (*Transfer, error) {
+	for _, transfer := range e.transfers {
+		if transfer.ID == id {
+			return transfer, nil
+		}
+	}
+	return nil, errUnknownTransfer
+}
+
+// transfersOf returns the withdrawals or the deposits of coin, all the coins when empty
+func (e *Exchange) transfersOf(coinName string, withdrawal bool) []*Transfer {
+	transfers := []*Transfer{}
+	for _, transfer := range e.transfers {
+		if transfer.Withdrawal != withdrawal {
+			continue
+		}
+		if coinName != "" && !strings.EqualFold(transfer.Coin, coinName) {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+// transferTime returns the creation time of a new transfer, a millisecond after the previous one at least, as the
+// histories paged by time tell the transfers apart by their millisecond
+func (e *Exchange) transferTime() time.Time {
+	now := time.Now()
+	if len(e.transfers) == 0 {
+		return now
+	}
+	if previous := e.transfers[len(e.transfers)-1].Time; now.Sub(previous) < time.Millisecond {
+		return previous.Add(time.Millisecond)
+	}
+	return now
+}
+
+// historyPage returns the transfers from the latest, skipping offset of them and keeping limit at most, hasNext
+// telling whether older ones are left
+func historyPage(transfers []*Transfer, offset, limit int) (page []*Transfer, hasNext bool) {
+	latest := make([]*Transfer, len(transfers))
+	for i, transfer := range transfers {
+		latest[len(transfers)-1-i] = transfer
+	}
+
+	latest = latest[min(offset, len(latest)):]
+	if limit < len(latest) {
+		return latest[:limit], true
+	}
+	return latest, false
+}
+
+// queryInt returns the integer parameter key of query, fallback when it is missing or invalid
+func queryInt(query url.Values, key string, fallback int) int {
+	value, err := strconv.Atoi(query.Get(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
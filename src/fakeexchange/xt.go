@@ -0,0 +1,532 @@
+package fakeexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func xtSymbol(pair Pair) string { return strings.ToLower(pair.Base + "_" + pair.Quote) }
+
+// xtWrite wraps the result in the {"rc","mc","ma","result"} envelope of XT
+func xtWrite(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"rc":     0,
+		"mc":     "SUCCESS",
+		"ma":     []string{},
+		"result": result,
+	})
+}
+
+func xtWriteCode(w http.ResponseWriter, status int, code string, messages ...string) {
+	if messages == nil {
+		messages = []string{}
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"rc":     1,
+		"mc":     code,
+		"ma":     messages,
+		"result": nil,
+	})
+}
+
+func xtWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnknownSymbol):
+		xtWriteCode(w, http.StatusBadRequest, "SYMBOL_001")
+	case errors.Is(err, errNotTradable):
+		xtWriteCode(w, http.StatusBadRequest, "SYMBOL_002")
+	case errors.Is(err, errInsufficientBalance):
+		xtWriteCode(w, http.StatusBadRequest, "ORDER_F0101")
+	case errors.Is(err, errUnknownOrder):
+		xtWriteCode(w, http.StatusBadRequest, "ORDER_005")
+	default:
+		xtWriteCode(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+	}
+}
+
+// NewXT starts a fake https://sapi.xt.com accepting orders signed with key and secret
+func NewXT(key, secret string) *Exchange {
+	return newExchange("XT", "sapi.xt.com", key, secret, xtRoutes)
+}
+
+func xtRoutes(e *Exchange, mux *http.ServeMux) {
+	signed := func(handler func(http.ResponseWriter, *http.Request, []byte)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body := readBody(r)
+			if !e.checkTimestamp(r.Header.Get("validate-timestamp")) {
+				xtWriteCode(w, http.StatusUnauthorized, "AUTH_104")
+				return
+			}
+			if !e.checkXTSignature(r, body) {
+				xtWriteCode(w, http.StatusUnauthorized, "AUTH_105")
+				return
+			}
+			handler(w, r, body)
+		}
+	}
+
+	mux.HandleFunc("GET /v4/public/time", e.xtTime)
+	mux.HandleFunc("GET /v4/public/ticker", e.xtTicker)
+	mux.HandleFunc("GET /v4/public/ticker/book", e.xtTicker)
+	mux.HandleFunc("GET /v4/public/depth", e.xtDepth)
+	mux.HandleFunc("GET /v4/public/symbol", e.xtSymbols)
+	mux.HandleFunc("GET /v4/public/currencies", e.xtCurrencies)
+	mux.HandleFunc("GET /v4/public/wallet/support/currency", e.xtSupportCurrencies)
+	mux.HandleFunc("GET /v4/balances", signed(e.xtBalances))
+	mux.HandleFunc("GET /v4/deposit/address", signed(e.xtDepositAddress))
+	mux.HandleFunc("POST /v4/withdraw", signed(e.xtWithdraw))
+	mux.HandleFunc("GET /v4/withdraw/history", signed(e.xtWithdrawHistory))
+	mux.HandleFunc("GET /v4/deposit/history", signed(e.xtDepositHistory))
+	mux.HandleFunc("POST /v4/order", signed(e.xtPostOrder))
+	mux.HandleFunc("GET /v4/order", signed(e.xtGetOrder))
+	mux.HandleFunc("DELETE /v4/order/{id}", signed(e.xtCancelOrder))
+	mux.HandleFunc("GET /v4/open-order", signed(e.xtOpenOrders))
+	mux.HandleFunc("DELETE /v4/open-order", signed(e.xtCancelOpenOrders))
+	mux.HandleFunc("GET /v4/trade", signed(e.xtTrades))
+}
+
+func (e *Exchange) xtTime(w http.ResponseWriter, r *http.Request) {
+	xtWrite(w, map[string]int64{"serverTime": millis(e.now())})
+}
+
+// xtTicker answers both the full and the book tickers, the best offers being common to both
+func (e *Exchange) xtTicker(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickers := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		bid, ask := bestOffers(e.orderbooks[pair])
+		tickers = append(tickers, map[string]interface{}{
+			"s":  xtSymbol(pair),
+			"t":  millis(time.Now()),
+			"ap": ask.Price.String(),
+			"aq": ask.Quantity.String(),
+			"bp": bid.Price.String(),
+			"bq": bid.Quantity.String(),
+		})
+	}
+
+	xtWrite(w, tickers)
+}
+
+func (e *Exchange) xtDepth(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), xtSymbol)
+	if !ok {
+		xtWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orderbook := e.orderbooks[pair]
+	xtWrite(w, map[string]interface{}{
+		"timestamp":    millis(time.Now()),
+		"lastUpdateId": e.lastID,
+		"bids":         levels(orderbook.Bids),
+		"asks":         levels(orderbook.Asks),
+	})
+}
+
+func (e *Exchange) xtSymbols(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	symbols := []map[string]interface{}{}
+	for _, pair := range e.pairs() {
+		state := "ONLINE"
+		if e.halted[pair] {
+			state = "OFFLINE"
+		}
+		filters := e.filtersOf(pair)
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":            xtSymbol(pair),
+			"state":             state,
+			"tradingEnabled":    !e.halted[pair],
+			"openapiEnabled":    true,
+			"baseCurrency":      strings.ToLower(pair.Base),
+			"quoteCurrency":     strings.ToLower(pair.Quote),
+			"pricePrecision":    precision(filters.TickSize),
+			"quantityPrecision": precision(filters.StepSize),
+			"orderTypes":        []string{"LIMIT", "MARKET"},
+			"timeInForces":      []string{"GTC", "FOK", "IOC", "GTX"},
+			"filters": []map[string]string{
+				{"filter": "PRICE", "tickSize": filters.TickSize.String()},
+				{"filter": "QUANTITY", "min": filters.MinQuantity.String(), "max": filters.MaxQuantity.String(), "tickSize": filters.StepSize.String()},
+				{"filter": "QUOTE_QTY", "min": filters.MinNotional.String()},
+			},
+		})
+	}
+
+	xtWrite(w, map[string]interface{}{
+		"time":    millis(time.Now()),
+		"version": "fake",
+		"symbols": symbols,
+	})
+}
+
+// xtCurrencies reports a currency as depositable or withdrawable when one of its networks is
+func (e *Exchange) xtCurrencies(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	boolToInt := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	currencies := []map[string]interface{}{}
+	for _, c := range e.coins() {
+		deposit, withdraw := false, false
+		for _, network := range e.networks[c] {
+			deposit = deposit || network.DepositEnable
+			withdraw = withdraw || network.WithdrawEnable
+		}
+		currencies = append(currencies, map[string]interface{}{
+			"currency":       strings.ToLower(c),
+			"fullName":       c,
+			"depositStatus":  boolToInt(deposit),
+			"withdrawStatus": boolToInt(withdraw),
+			"isChainExist":   1,
+		})
+	}
+
+	xtWrite(w, map[string]interface{}{
+		"time":       millis(time.Now()),
+		"version":    "fake",
+		"currencies": currencies,
+	})
+}
+
+func (e *Exchange) xtBalances(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	assets := []map[string]interface{}{}
+	for _, asset := range e.assets() {
+		assets = append(assets, map[string]interface{}{
+			"currency":        strings.ToLower(asset),
+			"availableAmount": e.balances[asset].String(),
+			"frozenAmount":    e.locked[asset].String(),
+			"totalAmount":     e.balances[asset].Add(e.locked[asset]).String(),
+		})
+	}
+
+	xtWrite(w, map[string]interface{}{
+		"totalUsdtAmount": "0",
+		"totalBtcAmount":  "0",
+		"assets":          assets,
+	})
+}
+
+func xtOrder(order *Order) map[string]interface{} {
+	avgPrice := decimal.Zero
+	if order.ExecutedQty.GreaterThan(decimal.Zero) {
+		avgPrice = order.ExecutedQuote.Div(order.ExecutedQty)
+	}
+
+	return map[string]interface{}{
+		"symbol":        xtSymbol(order.Pair),
+		"orderId":       order.ID,
+		"clientOrderId": order.ClientOrderID,
+		"side":          order.Side,
+		"type":          "LIMIT",
+		"timeInForce":   order.TimeInForce,
+		"price":         order.Price.String(),
+		"origQty":       order.Quantity.String(),
+		"executedQty":   order.ExecutedQty.String(),
+		"leavingQty":    order.Quantity.Sub(order.ExecutedQty).String(),
+		"avgPrice":      avgPrice.String(),
+		"state":         string(order.Status),
+		"time":          millis(order.Time),
+		"updatedTime":   millis(order.Time),
+	}
+}
+
+func (e *Exchange) xtPostOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var params map[string]interface{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		xtWriteError(w, errInvalidOrder)
+		return
+	}
+	param := func(name string) string {
+		if v, ok := params[name]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+
+	pair, ok := e.findPair(param("symbol"), xtSymbol)
+	if !ok {
+		xtWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	price, _ := decimal.NewFromString(param("price"))
+	quantity, _ := decimal.NewFromString(param("quantity"))
+
+	order, err := e.placeOrder(pair, param("clientOrderId"), param("side"), param("timeInForce"), price, quantity)
+	if err != nil {
+		xtWriteError(w, err)
+		return
+	}
+
+	xtWrite(w, map[string]string{"orderId": order.ID})
+}
+
+func (e *Exchange) xtGetOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[r.URL.Query().Get("orderId")]
+	if !ok {
+		xtWriteError(w, errUnknownOrder)
+		return
+	}
+
+	xtWrite(w, xtOrder(order))
+}
+
+// xtTrades answers a single trade per order, filled at its average price
+func (e *Exchange) xtTrades(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	pair, ok := e.findPair(query.Get("symbol"), xtSymbol)
+	if !ok {
+		xtWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	items := []map[string]interface{}{}
+	for _, order := range e.trades(pair, query.Get("orderId")) {
+		items = append(items, map[string]interface{}{
+			"symbol":        xtSymbol(order.Pair),
+			"tradeId":       order.ID,
+			"orderId":       order.ID,
+			"orderSide":     order.Side,
+			"orderType":     "LIMIT",
+			"bizType":       "SPOT",
+			"time":          millis(order.Time),
+			"price":         order.ExecutedQuote.Div(order.ExecutedQty).String(),
+			"quantity":      order.ExecutedQty.String(),
+			"quoteQty":      order.ExecutedQuote.String(),
+			"baseCurrency":  strings.ToLower(order.Pair.Base),
+			"quoteCurrency": strings.ToLower(order.Pair.Quote),
+			"fee":           order.Fee.String(),
+			"feeCurrency":   strings.ToLower(order.FeeAsset),
+			"takerMaker":    "TAKER",
+		})
+	}
+
+	xtWrite(w, map[string]interface{}{
+		"hasPrev": false,
+		"hasNext": false,
+		"items":   items,
+	})
+}
+
+func (e *Exchange) xtCancelOrder(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, err := e.cancelOrder(r.PathValue("id"))
+	if err != nil {
+		xtWriteError(w, err)
+		return
+	}
+
+	xtWrite(w, map[string]string{"cancelId": order.ID})
+}
+
+func (e *Exchange) xtOpenOrders(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.findPair(r.URL.Query().Get("symbol"), xtSymbol)
+	if !ok {
+		xtWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	orders := []map[string]interface{}{}
+	for _, order := range e.openOrders(pair) {
+		orders = append(orders, xtOrder(order))
+	}
+
+	xtWrite(w, orders)
+}
+
+// xtCancelOpenOrders reads its parameters from the JSON body, like the real API
+func (e *Exchange) xtCancelOpenOrders(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var params struct {
+		Symbol string `json:"symbol"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		xtWriteError(w, errInvalidOrder)
+		return
+	}
+
+	pair, ok := e.findPair(params.Symbol, xtSymbol)
+	if !ok {
+		xtWriteError(w, errUnknownSymbol)
+		return
+	}
+
+	for _, order := range e.openOrders(pair) {
+		e.cancelOrder(order.ID)
+	}
+
+	xtWrite(w, map[string]interface{}{})
+}
+
+func (e *Exchange) xtDepositAddress(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	address, err := e.depositAddress(query.Get("currency"), query.Get("chain"))
+	if err != nil {
+		xtWriteError(w, err)
+		return
+	}
+
+	xtWrite(w, map[string]string{"address": address, "memo": ""})
+}
+
+func (e *Exchange) xtWithdraw(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var params struct {
+		Currency string          `json:"currency"`
+		Chain    string          `json:"chain"`
+		Amount   decimal.Decimal `json:"amount"`
+		Address  string          `json:"address"`
+		Memo     string          `json:"memo"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		xtWriteError(w, errInvalidOrder)
+		return
+	}
+
+	transfer, err := e.withdraw(params.Currency, params.Chain, params.Address, params.Memo, params.Amount)
+	if err != nil {
+		xtWriteError(w, err)
+		return
+	}
+
+	id, _ := strconv.ParseInt(transfer.ID, 10, 64)
+	xtWrite(w, map[string]int64{"id": id})
+}
+
+// xtTransfers answers a page of transfers of the currency and chain, with their status as given by status
+func (e *Exchange) xtTransfers(w http.ResponseWriter, r *http.Request, withdrawal bool, status func(TransferStatus) string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	query := r.URL.Query()
+	// the next pages are read from fromId, towards the oldest transfers
+	fromID := queryInt(query, "fromId", 0)
+	transfers := []*Transfer{}
+	for _, transfer := range e.transfersOf(query.Get("currency"), withdrawal) {
+		id, _ := strconv.Atoi(transfer.ID)
+		if strings.EqualFold(transfer.Network, query.Get("chain")) && (fromID == 0 || id < fromID) {
+			transfers = append(transfers, transfer)
+		}
+	}
+	page, hasNext := historyPage(transfers, 0, queryInt(query, "limit", 10))
+
+	items := []map[string]interface{}{}
+	for _, transfer := range page {
+		id, _ := strconv.ParseInt(transfer.ID, 10, 64)
+		items = append(items, map[string]interface{}{
+			"id":            id,
+			"currency":      strings.ToLower(transfer.Coin),
+			"chain":         transfer.Network,
+			"address":       transfer.Address,
+			"memo":          transfer.Memo,
+			"status":        status(transfer.Status),
+			"amount":        transfer.Amount.String(),
+			"fee":           transfer.Fee.String(),
+			"transactionId": transfer.TxID,
+			"createdTime":   millis(transfer.Time),
+		})
+	}
+
+	xtWrite(w, map[string]interface{}{
+		"hasPrev": fromID != 0,
+		"hasNext": hasNext,
+		"items":   items,
+	})
+}
+
+func (e *Exchange) xtWithdrawHistory(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.xtTransfers(w, r, true, func(status TransferStatus) string {
+		switch status {
+		case TransferCompleted:
+			return "SUCCESS"
+		case TransferFailed:
+			return "FAIL"
+		default:
+			return "PENDING"
+		}
+	})
+}
+
+func (e *Exchange) xtDepositHistory(w http.ResponseWriter, r *http.Request, body []byte) {
+	e.xtTransfers(w, r, false, func(status TransferStatus) string {
+		switch status {
+		case TransferCompleted:
+			return "SUCCESS"
+		case TransferFailed:
+			return "FAILURE"
+		default:
+			return "PROCESSING"
+		}
+	})
+}
+
+func (e *Exchange) xtSupportCurrencies(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	currencies := []map[string]interface{}{}
+	for _, c := range e.coins() {
+		chains := []map[string]interface{}{}
+		for _, network := range e.networks[c] {
+			chains = append(chains, map[string]interface{}{
+				"chain":               network.Network,
+				"depositEnabled":      network.DepositEnable,
+				"withdrawEnabled":     network.WithdrawEnable,
+				"withdrawFeeAmount":   network.WithdrawFee.String(),
+				"withdrawFeeCurrency": strings.ToLower(c),
+				"withdrawMinAmount":   network.WithdrawMin.String(),
+				"depositFeeRate":      "0",
+			})
+		}
+		currencies = append(currencies, map[string]interface{}{
+			"currency":      strings.ToLower(c),
+			"supportChains": chains,
+		})
+	}
+
+	xtWrite(w, currencies)
+}
@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Config is the HTTP configuration shared by the clients of the exchanges and aggregators
+type Config struct {
+	// BaseURL overrides the REST endpoint, e.g. to use a testnet, a proxy or a local stand-in
+	BaseURL   string
+	Timeout   time.Duration
+	UserAgent string
+
+	// Client replaces the one built from Timeout and UserAgent, it cannot be set from the JSON
+	Client *http.Client `json:"-"`
+	// Transport sends the requests of the client built from Timeout and UserAgent, http.DefaultTransport when nil,
+	// e.g. a recorder replaying fixtures
+	Transport http.RoundTripper `json:"-"`
+	// Limiter throttles the requests of every client returned by HTTPClient, set by the brokers to the limits of their exchange
+	Limiter *Limiter `json:"-"`
+	// Retry resends the idempotent requests failing for a transient reason, set by the brokers from their RetryTimerHTTP
+	Retry RetryPolicy `json:"-"`
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type Alias Config
+	aux := &struct {
+		Timeout string `json:"Timeout"`
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Timeout == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(aux.Timeout)
+	if err != nil {
+		return err
+	}
+	c.Timeout = duration
+	return nil
+}
+
+// HTTPClient returns Client when set, otherwise a client honouring Timeout and UserAgent.
+// Either way, its requests go through Limiter and Retry when they are set.
+func (c Config) HTTPClient() *http.Client {
+	if c.Client != nil {
+		if c.Limiter == nil && c.Retry.MaxAttempts < 2 {
+			return c.Client
+		}
+		client := *c.Client
+		client.Transport = c.transport(client.Transport)
+		return &client
+	}
+
+	client := &http.Client{Timeout: c.Timeout, Transport: c.Transport}
+	if c.UserAgent != "" {
+		client.Transport = &userAgentTransport{userAgent: c.UserAgent, next: c.Transport}
+	}
+	client.Transport = c.transport(client.Transport)
+	return client
+}
+
+// transport wraps next with Limiter, then with Retry so that every attempt of a request goes through Limiter
+func (c Config) transport(next http.RoundTripper) http.RoundTripper {
+	if c.Limiter != nil {
+		next = c.Limiter.Transport(next)
+	}
+	if c.Retry.MaxAttempts > 1 {
+		next = c.Retry.Transport(next)
+	}
+	return next
+}
+
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	// http.DefaultTransport is read at each request, as an http.Client without Transport does
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
@@ -0,0 +1,43 @@
+package httpclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+)
+
+func TestConfig(t *testing.T) {
+	var config httpclient.Config
+	if err := json.Unmarshal([]byte(`{"BaseURL": "http://localhost", "Timeout": "5s", "UserAgent": "arb-bot"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Timeout != 5*time.Second || config.BaseURL != "http://localhost" {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer server.Close()
+
+	client := config.HTTPClient()
+	if client.Timeout != 5*time.Second {
+		t.Fatalf("unexpected timeout %v", client.Timeout)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var userAgent [16]byte
+	n, _ := resp.Body.Read(userAgent[:])
+	if string(userAgent[:n]) != "arb-bot" {
+		t.Fatalf("unexpected user agent %q", userAgent[:n])
+	}
+}
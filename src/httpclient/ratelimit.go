@@ -0,0 +1,297 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned instead of sending a request that would exceed the budget of the exchange,
+// or while the exchange asks to back off
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitRule is a budget of requests, or of request weight, per interval
+type RateLimitRule struct {
+	// Method and PathPrefix select the requests counted by the rule, all of them when empty
+	Method     string
+	PathPrefix string
+	// PerPath gives each path its own budget, for the exchanges limiting each endpoint separately
+	PerPath bool
+	// Weighted counts the weight of the requests instead of their number
+	Weighted bool
+
+	Limit    int
+	Interval time.Duration
+
+	// Remaining reads the budget left as reported by the exchange in the headers of its responses, when it does
+	Remaining func(header http.Header) (int, bool)
+}
+
+func (r RateLimitRule) matches(req *http.Request) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, req.Method) {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, r.PathPrefix)
+}
+
+// RateLimits are the limits of an exchange, a request being counted by every rule it matches
+type RateLimits struct {
+	Rules []RateLimitRule
+	// Weight returns the weight of a request, 1 when nil
+	Weight func(req *http.Request) int
+	// MaxWait is the longest a request waits for the budget, it fails with ErrRateLimited beyond.
+	// Zero waits as long as the context of the request allows.
+	MaxWait time.Duration
+}
+
+// UsedWeightHeader reads the weight used in the current interval from the header name, as Binance sends it
+func UsedWeightHeader(name string, limit int) func(http.Header) (int, bool) {
+	return func(header http.Header) (int, bool) {
+		used, err := strconv.Atoi(header.Get(name))
+		if err != nil {
+			return 0, false
+		}
+		return limit - used, true
+	}
+}
+
+// RemainingHeader reads the number of requests left in the current interval from the header name
+func RemainingHeader(name string) func(http.Header) (int, bool) {
+	return func(header http.Header) (int, bool) {
+		remaining, err := strconv.Atoi(header.Get(name))
+		if err != nil {
+			return 0, false
+		}
+		return remaining, true
+	}
+}
+
+const (
+	// minBackoff and maxBackoff bound the pause after a 429 without Retry-After, doubled at each one in a row
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+	// banBackoff is the pause after a 418, sent by Binance to the IPs banned for ignoring the 429
+	banBackoff = 2 * time.Minute
+)
+
+// Limiter throttles the requests sent to an exchange so that they stay within its limits. It is shared by
+// the clients built by Config.HTTPClient, which all draw on the same budget.
+type Limiter struct {
+	limits RateLimits
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	pausedUntil time.Time
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+func NewLimiter(limits RateLimits) *Limiter {
+	return &Limiter{
+		limits:  limits,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// bucket is a token bucket holding up to limit tokens, refilled at limit tokens per interval. It goes negative
+// when requests are waiting for their turn.
+type bucket struct {
+	tokens   float64
+	limit    float64
+	rate     float64 // tokens per second
+	updateAt time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.updateAt).Seconds() * b.rate
+	if b.tokens > b.limit {
+		b.tokens = b.limit
+	}
+	b.updateAt = now
+}
+
+// wait returns how long a request of weight must wait for the bucket to hold enough tokens
+func (b *bucket) wait(weight float64) time.Duration {
+	if b.tokens >= weight {
+		return 0
+	}
+	return time.Duration((weight - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Wait blocks until req can be sent within the budget, and counts it. It fails with ErrRateLimited when the wait
+// would exceed MaxWait, or with the error of the context of req when it is done first.
+func (l *Limiter) Wait(req *http.Request) error {
+	weight := 1
+	if l.limits.Weight != nil {
+		weight = l.limits.Weight(req)
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	buckets, weights := l.matching(req, weight, now)
+
+	delay := l.pausedUntil.Sub(now)
+	for i, b := range buckets {
+		if wait := b.wait(weights[i]); wait > delay {
+			delay = wait
+		}
+	}
+	if delay > 0 && l.limits.MaxWait > 0 && delay > l.limits.MaxWait {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %v %v would wait %v", ErrRateLimited, req.Method, req.URL.Path, delay)
+	}
+	if deadline, ok := req.Context().Deadline(); ok && now.Add(delay).After(deadline) {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %v %v would wait %v, after the deadline", ErrRateLimited, req.Method, req.URL.Path, delay)
+	}
+
+	// The tokens are taken at once so that the requests arriving meanwhile wait behind this one
+	for i, b := range buckets {
+		b.tokens -= weights[i]
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		l.mu.Lock()
+		for i, b := range buckets {
+			b.tokens += weights[i]
+		}
+		l.mu.Unlock()
+		return req.Context().Err()
+	}
+}
+
+// matching returns the buckets of the rules matched by req, refilled, with the weight taken from each.
+// It must be called with mu held.
+func (l *Limiter) matching(req *http.Request, weight int, now time.Time) ([]*bucket, []float64) {
+	var buckets []*bucket
+	var weights []float64
+	for i, rule := range l.limits.Rules {
+		if !rule.matches(req) {
+			continue
+		}
+		b := l.bucket(i, rule, req.URL.Path, now)
+		b.refill(now)
+		buckets = append(buckets, b)
+		if rule.Weighted {
+			weights = append(weights, float64(weight))
+		} else {
+			weights = append(weights, 1)
+		}
+	}
+	return buckets, weights
+}
+
+// bucket returns the bucket of the rule i for path, created full. It must be called with mu held.
+func (l *Limiter) bucket(i int, rule RateLimitRule, path string, now time.Time) *bucket {
+	key := strconv.Itoa(i)
+	if rule.PerPath {
+		key += " " + path
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens:   float64(rule.Limit),
+			limit:    float64(rule.Limit),
+			rate:     float64(rule.Limit) / rule.Interval.Seconds(),
+			updateAt: now,
+		}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// Observe updates the budget from a response: the headers giving the budget left correct the buckets, and the
+// 429 and 418 statuses pause every request for the time asked by Retry-After.
+func (l *Limiter) Observe(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if resp.Request != nil {
+		for i, rule := range l.limits.Rules {
+			if rule.Remaining == nil || !rule.matches(resp.Request) {
+				continue
+			}
+			remaining, ok := rule.Remaining(resp.Header)
+			if !ok {
+				continue
+			}
+			b := l.bucket(i, rule, resp.Request.URL.Path, now)
+			b.refill(now)
+			if float64(remaining) < b.tokens {
+				b.tokens = float64(remaining)
+			}
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusTeapot:
+		pause := retryAfter(resp.Header)
+		if pause <= 0 {
+			if resp.StatusCode == http.StatusTeapot {
+				pause = banBackoff
+			} else {
+				l.backoff = min(max(l.backoff*2, minBackoff), maxBackoff)
+				pause = l.backoff
+			}
+		}
+		if until := now.Add(pause); until.After(l.pausedUntil) {
+			l.pausedUntil = until
+		}
+	default:
+		l.backoff = 0
+	}
+}
+
+// retryAfter reads the Retry-After header, given in seconds by the exchanges
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Transport returns a RoundTripper sending the requests through next once the limiter allows them,
+// http.DefaultTransport being used when next is nil
+func (l *Limiter) Transport(next http.RoundTripper) http.RoundTripper {
+	return &limitedTransport{limiter: l, next: next}
+}
+
+type limitedTransport struct {
+	limiter *Limiter
+	next    http.RoundTripper
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req); err != nil {
+		return nil, err
+	}
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.limiter.Observe(resp)
+	return resp, nil
+}
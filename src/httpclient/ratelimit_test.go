@@ -0,0 +1,120 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+)
+
+func TestLimiter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/busy" {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Used-Weight", "8")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	limiter := httpclient.NewLimiter(httpclient.RateLimits{
+		Rules: []httpclient.RateLimitRule{
+			{Weighted: true, Limit: 10, Interval: time.Second, Remaining: httpclient.UsedWeightHeader("X-Used-Weight", 10)},
+		},
+		Weight: func(req *http.Request) int {
+			if req.URL.Path == "/heavy" {
+				return 5
+			}
+			return 1
+		},
+		MaxWait: 100 * time.Millisecond,
+	})
+	client := httpclient.Config{Limiter: limiter}.HTTPClient()
+
+	get := func(path string) error {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	// The exchange reports 8 of the 10 used, leaving no room for a request of weight 5
+	if err := get("/light"); err != nil {
+		t.Fatal(err)
+	}
+	if err := get("/heavy"); !errors.Is(err, httpclient.ErrRateLimited) {
+		t.Fatalf("the request over the budget should be rejected: %v", err)
+	}
+	if err := get("/light"); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("the rejected request should not be sent: %v requests", requests.Load())
+	}
+
+	// A 429 pauses every request for the time asked by Retry-After
+	time.Sleep(time.Second)
+	if err := get("/busy"); err != nil {
+		t.Fatal(err)
+	}
+	if err := get("/light"); !errors.Is(err, httpclient.ErrRateLimited) {
+		t.Fatalf("the requests should be paused after a 429: %v", err)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	limiter := httpclient.NewLimiter(httpclient.RateLimits{
+		Rules: []httpclient.RateLimitRule{
+			{PathPrefix: "/api/", PerPath: true, Limit: 2, Interval: 200 * time.Millisecond},
+		},
+	})
+
+	request := func(ctx context.Context, path string) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost"+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(request(ctx, "/api/depth")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("the third request should wait for the budget, it waited %v", elapsed)
+	}
+
+	// Each path has its own budget, and the paths out of the rules are not limited
+	start = time.Now()
+	for _, path := range []string{"/api/ticker", "/other", "/other", "/other"} {
+		if err := limiter.Wait(request(ctx, path)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("the requests should not wait, they waited %v", elapsed)
+	}
+
+	// A request waiting beyond its deadline is rejected at once
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	limiter.Wait(request(context.Background(), "/api/depth"))
+	if err := limiter.Wait(request(ctx, "/api/depth")); !errors.Is(err, httpclient.ErrRateLimited) {
+		t.Fatalf("the request should be rejected: %v", err)
+	}
+}
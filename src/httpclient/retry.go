@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy resends the idempotent requests failing for a transient reason: a timeout, a dropped connection,
+// a 5xx or a 429 status. The other requests, the orders among them, are sent once whatever happens, as nothing
+// tells whether the exchange processed them.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent at most, the first one included. There is no retry below 2.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled at each one up to MaxBackoff when set
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the wait drawn at random, spreading the retries of concurrent requests
+	Jitter float64
+	// SignedWindow bounds the time a signed request is retried for, from its first attempt, when set. A retry is
+	// sent with the timestamp and the signature of the first attempt, which the exchange rejects once older than
+	// its receive window.
+	SignedWindow time.Duration
+}
+
+// wait returns how long to wait before the retry following attempt, 1 being the first one
+func (p RetryPolicy) wait(attempt int) time.Duration {
+	wait := p.Backoff << (attempt - 1)
+	if p.MaxBackoff > 0 && (wait > p.MaxBackoff || wait < p.Backoff) {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 && wait > 0 {
+		jitter := time.Duration(p.Jitter * float64(wait))
+		wait = wait - jitter + time.Duration(rand.Int63n(int64(2*jitter)+1))
+	}
+	return wait
+}
+
+// isIdempotent tells whether a request can be sent again without any side effect
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+// isSigned tells whether a request is signed along with its timestamp, as the private endpoints of the exchanges
+// are: in the signature query parameter on Binance, MEXC and Bitrue, in the SIGN header on Gate and in the
+// validate-signature header on XT
+func isSigned(req *http.Request) bool {
+	return req.URL.Query().Has("signature") || req.Header.Get("SIGN") != "" || req.Header.Get("validate-signature") != ""
+}
+
+// IsRetryable tells whether a request failing with err, or answered with resp, may succeed when sent again
+func IsRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// The budget of the exchange, or the deadline of the caller, would not allow it anyway
+		if errors.Is(err, ErrRateLimited) {
+			return false
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// Transport returns a RoundTripper sending the requests through next, http.DefaultTransport when nil,
+// as many times as the policy allows
+func (p RetryPolicy) Transport(next http.RoundTripper) http.RoundTripper {
+	return &retryTransport{policy: p, next: next}
+}
+
+type retryTransport struct {
+	policy RetryPolicy
+	next   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t.policy.MaxAttempts < 2 || !isIdempotent(req) {
+		return next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resp, err := next.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !IsRetryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.policy.wait(attempt)
+		if resp != nil {
+			if after := retryAfter(resp.Header); after > wait {
+				wait = after
+			}
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+		if t.policy.SignedWindow > 0 && isSigned(req) && time.Since(start)+wait >= t.policy.SignedWindow {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
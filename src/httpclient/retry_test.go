@@ -0,0 +1,107 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/httpclient"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/flaky":
+			if n < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := httpclient.Config{Retry: httpclient.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}}.HTTPClient()
+	send := func(method, path string) int {
+		requests.Store(0)
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method == http.MethodGet {
+			req.Body = http.NoBody
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := send(http.MethodGet, "/flaky"); status != http.StatusOK || requests.Load() != 3 {
+		t.Fatalf("the request should succeed at the third attempt: %v after %v", status, requests.Load())
+	}
+	if status := send(http.MethodGet, "/down"); status != http.StatusServiceUnavailable || requests.Load() != 3 {
+		t.Fatalf("the request should give up after 3 attempts: %v after %v", status, requests.Load())
+	}
+	if status := send(http.MethodGet, "/forbidden"); status != http.StatusForbidden || requests.Load() != 1 {
+		t.Fatalf("a 403 should not be retried: %v after %v", status, requests.Load())
+	}
+	if status := send(http.MethodPost, "/down"); status != http.StatusServiceUnavailable || requests.Load() != 1 {
+		t.Fatalf("a POST should not be retried: %v after %v", status, requests.Load())
+	}
+}
+
+func TestRetryPolicySignedWindow(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := httpclient.Config{Retry: httpclient.RetryPolicy{
+		MaxAttempts:  10,
+		Backoff:      50 * time.Millisecond,
+		MaxBackoff:   50 * time.Millisecond,
+		SignedWindow: 125 * time.Millisecond,
+	}}.HTTPClient()
+	send := func(path string, header http.Header) int32 {
+		requests.Store(0)
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return requests.Load()
+	}
+
+	// The retries at 50ms and 100ms are sent, the one at 150ms would be past the window
+	if attempts := send("/balance?timestamp=1&signature=abc", nil); attempts != 3 {
+		t.Fatalf("a signed request should only be retried within the window: %v attempts", attempts)
+	}
+	if attempts := send("/balance", http.Header{"Sign": {"abc"}}); attempts != 3 {
+		t.Fatalf("a request signed in a header should only be retried within the window: %v attempts", attempts)
+	}
+	if attempts := send("/depth", nil); attempts != 10 {
+		t.Fatalf("an unsigned request should be retried %v times: %v attempts", 10, attempts)
+	}
+}
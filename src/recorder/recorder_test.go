@@ -0,0 +1,125 @@
+package recorder_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/recorder"
+)
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(r.URL.Query().Get("symbol")))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	rec, err := recorder.New(path, recorder.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get(t, &http.Client{Transport: rec}, server.URL+"/depth?symbol=BTCUSDT&timestamp=1&signature=abc&x-cg-pro-api-key=secret")
+
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "abc") {
+		t.Fatalf("the fixture was not redacted: %s", data)
+	}
+
+	rep, err := recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rep}
+
+	// The signature and timestamp differ from the recorded call, but are not part of the match
+	if body := get(t, client, server.URL+"/depth?symbol=BTCUSDT&timestamp=2&signature=def&x-cg-pro-api-key=other"); body != "BTCUSDT" {
+		t.Fatalf("unexpected replayed body %q", body)
+	}
+	if calls != 1 {
+		t.Fatalf("the replay reached the server")
+	}
+
+	if _, err := client.Get(server.URL + "/depth?symbol=BTCUSDT"); err == nil {
+		t.Fatal("expected an error once the interactions are exhausted")
+	}
+}
+
+func TestRecordSignedPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Write([]byte(r.PostForm.Get("symbol")))
+	}))
+	defer server.Close()
+
+	post := func(client *http.Client, body string) string {
+		resp, err := client.Post(server.URL+"/order", "application/x-www-form-urlencoded", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	rec, err := recorder.New(path, recorder.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The exchange still receives the whole signed body
+	if body := post(&http.Client{Transport: rec}, "symbol=BTCUSDT&price=0.00001234&apiKey=secret&timestamp=1&signature=abc"); body != "BTCUSDT" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "abc") || strings.Contains(string(data), "timestamp") {
+		t.Fatalf("the body was not redacted: %s", data)
+	}
+
+	rep, err := recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := post(&http.Client{Transport: rep}, "symbol=BTCUSDT&price=0.00001234&apiKey=other&timestamp=2&signature=def"); body != "BTCUSDT" {
+		t.Fatalf("unexpected replayed body %q", body)
+	}
+}
@@ -0,0 +1,36 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Env reads the secrets from environment variables: the name Binance-main/Key is read from BINANCE_MAIN_KEY,
+// after Prefix
+type Env struct {
+	Prefix string
+}
+
+func (e Env) Lookup(_ context.Context, name string) (string, error) {
+	variable := e.Variable(name)
+	value, ok := os.LookupEnv(variable)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%v: %w", variable, ErrNotFound)
+	}
+	return value, nil
+}
+
+// Variable returns the environment variable holding the secret name
+func (e Env) Variable(name string) string {
+	return e.Prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Exec reads the secrets from a local command, e.g. a password manager: Command is run with the name of the secret
+// as last argument, and prints the secret on its standard output. A command exiting with 2 does not hold it.
+type Exec struct {
+	Command []string
+}
+
+func (e Exec) Lookup(ctx context.Context, name string) (string, error) {
+	args := append(append([]string(nil), e.Command[1:]...), name)
+	cmd := exec.CommandContext(ctx, e.Command[0], args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+			return "", fmt.Errorf("%v: %w", name, ErrNotFound)
+		}
+		// The standard output may hold a part of the secret, only the error output is reported
+		return "", fmt.Errorf("%v failed on %v: %w: %v", e.Command[0], name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	value := strings.TrimRight(stdout.String(), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%v: %w", name, ErrNotFound)
+	}
+	return value, nil
+}
@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The cost of the derivation of the key from the passphrase, as recommended by scrypt for interactive logins
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// sealedFile is the content of an encrypted file: the secrets as a JSON object, sealed by a key derived from the
+// passphrase and Salt
+type sealedFile struct {
+	Salt  []byte
+	Nonce []byte
+	Box   []byte
+}
+
+// File holds the secrets decrypted from a file written by WriteFile
+type File struct {
+	secrets map[string]string
+}
+
+// OpenFile decrypts the secrets of path with passphrase
+func OpenFile(path, passphrase string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sealed sealedFile
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %v: %w", path, err)
+	}
+	if len(sealed.Nonce) != 24 {
+		return nil, fmt.Errorf("invalid secrets file %v: the nonce must be 24 bytes", path)
+	}
+
+	key, err := deriveKey(passphrase, sealed.Salt)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	copy(nonce[:], sealed.Nonce)
+	plain, ok := secretbox.Open(nil, sealed.Box, &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt %v, the passphrase is wrong or the file corrupted", path)
+	}
+
+	f := &File{}
+	if err := json.Unmarshal(plain, &f.secrets); err != nil {
+		return nil, fmt.Errorf("invalid secrets in %v: %w", path, err)
+	}
+	return f, nil
+}
+
+func (f *File) Lookup(_ context.Context, name string) (string, error) {
+	value, ok := f.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%v: %w", name, ErrNotFound)
+	}
+	return value, nil
+}
+
+// WriteFile encrypts secrets, keyed by their name, into path with passphrase. Only the owner can read the file.
+func WriteFile(path, passphrase string, secrets map[string]string) error {
+	if passphrase == "" {
+		return errors.New("the passphrase cannot be empty")
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	sealed := sealedFile{Salt: make([]byte, 16), Nonce: make([]byte, 24)}
+	if _, err := rand.Read(sealed.Salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(sealed.Nonce); err != nil {
+		return err
+	}
+	key, err := deriveKey(passphrase, sealed.Salt)
+	if err != nil {
+		return err
+	}
+	var nonce [24]byte
+	copy(nonce[:], sealed.Nonce)
+	sealed.Box = secretbox.Seal(nil, plain, &nonce, key)
+
+	data, err := json.Marshal(sealed)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func deriveKey(passphrase string, salt []byte) (*[32]byte, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	copy(key[:], derived)
+	return &key, nil
+}
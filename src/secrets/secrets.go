@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotFound is returned by the providers which do not hold the secret asked for
+var ErrNotFound = errors.New("secret not found")
+
+// Redacted replaces the secrets when they are printed
+const Redacted = "REDACTED"
+
+// Provider looks up the secrets, e.g. the API keys of the exchanges, by their name
+type Provider interface {
+	Lookup(ctx context.Context, name string) (string, error)
+}
+
+// DefaultPassphraseEnv is the environment variable holding the passphrase of the file provider
+const DefaultPassphraseEnv = "SECRETS_PASSPHRASE"
+
+// Config selects the provider of the secrets
+type Config struct {
+	// Provider is either env, file or exec
+	Provider string
+	// Prefix is prepended to the names of the environment variables, see Env
+	Prefix string
+	// Path is the file encrypted with WriteFile, its passphrase being read from the PassphraseEnv environment
+	// variable, DefaultPassphraseEnv when empty
+	Path          string
+	PassphraseEnv string
+	// Command is the program run by Exec followed by its arguments
+	Command []string
+}
+
+// New returns the provider set by config
+func New(config Config) (Provider, error) {
+	switch config.Provider {
+	case "env":
+		return Env{Prefix: config.Prefix}, nil
+	case "file":
+		passphraseEnv := config.PassphraseEnv
+		if passphraseEnv == "" {
+			passphraseEnv = DefaultPassphraseEnv
+		}
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("the passphrase of %v must be set in %v", config.Path, passphraseEnv)
+		}
+		return OpenFile(config.Path, passphrase)
+	case "exec":
+		if len(config.Command) == 0 {
+			return nil, fmt.Errorf("the command of the exec provider is not set")
+		}
+		return Exec{Command: config.Command}, nil
+	}
+	return nil, fmt.Errorf("unknown secrets provider %q, expected env, file or exec", config.Provider)
+}
@@ -0,0 +1,94 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ArbitrageCoin/crypto-sdk/src/secrets"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("ARB_BINANCE_MAIN_KEY", "key")
+	provider, err := secrets.New(secrets.Config{Provider: "env", Prefix: "ARB_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if value, err := provider.Lookup(ctx, "Binance-main/Key"); err != nil || value != "key" {
+		t.Fatalf("unexpected secret %q: %v", value, err)
+	}
+	if _, err := provider.Lookup(ctx, "Binance-main/Secret"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Fatalf("expected a secret not found: %v", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	if err := secrets.WriteFile(path, "passphrase", map[string]string{"Gate/Key": "key", "Gate/Secret": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is written in clear
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "Gate") {
+		t.Fatalf("the file is not encrypted: %s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("the file should only be readable by its owner: %v", info.Mode())
+	}
+
+	if _, err := secrets.OpenFile(path, "wrong"); err == nil {
+		t.Fatalf("the file should not be decrypted with a wrong passphrase")
+	}
+
+	t.Setenv("ARB_PASSPHRASE", "passphrase")
+	provider, err := secrets.New(secrets.Config{Provider: "file", Path: path, PassphraseEnv: "ARB_PASSPHRASE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if value, err := provider.Lookup(ctx, "Gate/Secret"); err != nil || value != "secret" {
+		t.Fatalf("unexpected secret %q: %v", value, err)
+	}
+	if _, err := provider.Lookup(ctx, "MEXC/Secret"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Fatalf("expected a secret not found: %v", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+	provider, err := secrets.New(secrets.Config{Provider: "exec", Command: []string{"sh", "-c", `[ "$0" = XT/Key ] || exit 2; echo key`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := provider.Lookup(ctx, "XT/Key"); err != nil || value != "key" {
+		t.Fatalf("unexpected secret %q: %v", value, err)
+	}
+	if _, err := provider.Lookup(ctx, "XT/Secret"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Fatalf("expected a secret not found: %v", err)
+	}
+
+	failing := secrets.Exec{Command: []string{"sh", "-c", "echo leaked; echo locked >&2; exit 1"}}
+	_, err = failing.Lookup(ctx, "XT/Key")
+	if err == nil || !strings.Contains(err.Error(), "locked") || strings.Contains(err.Error(), "leaked") {
+		t.Fatalf("the error should only report the error output: %v", err)
+	}
+
+	for _, config := range []secrets.Config{{Provider: "vault"}, {Provider: "exec"}, {Provider: "file", Path: "secrets.json", PassphraseEnv: "ARB_UNSET"}} {
+		if _, err := secrets.New(config); err == nil {
+			t.Fatalf("the provider %+v should not be created", config)
+		}
+	}
+}
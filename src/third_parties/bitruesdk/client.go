@@ -0,0 +1,38 @@
+package bitruesdk
+
+import (
+	"net/http"
+	"time"
+)
+
+const DefaultBaseURL = "https://www.bitrue.com"
+
+// Client sends the requests to BaseURL through HTTPClient
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Now returns the time the signed requests are timestamped with, the local time when nil
+	Now func() time.Time
+}
+
+// NewClient returns a client of baseURL, or DefaultBaseURL when empty, using http.DefaultClient
+// when httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *Client) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
@@ -0,0 +1,58 @@
+package bitruesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// Level is a [price, quantity] entry of the order book, Bitrue appending an unused third element
+type Level struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if len(values) < 2 {
+		return fmt.Errorf("invalid level: %s", data)
+	}
+
+	if err := json.Unmarshal(values[0], &l.Price); err != nil {
+		return err
+	}
+	return json.Unmarshal(values[1], &l.Quantity)
+}
+
+type ResponseGetDepth struct {
+	LastUpdateID int64   `json:"lastUpdateId"`
+	Bids         []Level `json:"bids"`
+	Asks         []Level `json:"asks"`
+}
+
+func (c *Client) GetDepth(ctx context.Context, symbol string, limit int) (ResponseGetDepth, error) {
+	url := c.BaseURL + "/api/v1/depth?symbol=" + symbol + "&limit=" + strconv.Itoa(limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return ResponseGetDepth{}, err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return ResponseGetDepth{}, err
+	}
+
+	var res ResponseGetDepth
+	if err := json.Unmarshal(body, &res); err != nil {
+		return ResponseGetDepth{}, err
+	}
+
+	return res, nil
+}
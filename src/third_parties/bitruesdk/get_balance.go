@@ -1,11 +1,9 @@
 package bitruesdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"time"
 
 	"crypto/hmac"
 	"crypto/sha256"
@@ -34,18 +32,17 @@ type ResponseGetBalance struct {
 	CanDeposit       bool                `json:"canDeposit"`
 }
 
-func GetBalance(apiKey, secretKey string) (*ResponseGetBalance, error) {
-	url := "https://www.bitrue.com/api/v1/account"
+func (c *Client) GetBalance(ctx context.Context, apiKey, secretKey string) (*ResponseGetBalance, error) {
+	url := c.BaseURL + "/api/v1/account"
 
-	timestamp := time.Now().UnixMilli()
+	timestamp := c.now().UnixMilli()
 	mac := hmac.New(sha256.New, []byte(secretKey))
 	data := "timestamp=" + strconv.FormatInt(timestamp, 10)
 	mac.Write([]byte(data))
 	signature := mac.Sum(nil)
 	signatureStr := hex.EncodeToString(signature)
 
-	req, err := http.NewRequest("GET", url+"?"+data+"&signature="+signatureStr, nil)
-	client := http.Client{}
+	req, err := http.NewRequestWithContext(ctx, "GET", url+"?"+data+"&signature="+signatureStr, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -54,17 +51,10 @@ func GetBalance(apiKey, secretKey string) (*ResponseGetBalance, error) {
 	req.Header.Add("X-MBX-TIMESTAMP", strconv.FormatInt(timestamp, 10))
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := client.Do(req)
+	body, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(body))
 
 	res := ResponseGetBalance{}
 	if err := json.Unmarshal(body, &res); err != nil {
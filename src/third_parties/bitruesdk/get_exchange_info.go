@@ -0,0 +1,80 @@
+package bitruesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+// FilterGetExchangeInfo is a constraint on the orders of a symbol. PRICE_FILTER gives the decimals of the prices
+// in priceScale, LOT_SIZE the ones of the quantities in volumeScale, along with the minimum notional in minVal.
+type FilterGetExchangeInfo struct {
+	FilterType  string          `json:"filterType"`
+	MinPrice    decimal.Decimal `json:"minPrice"`
+	MaxPrice    decimal.Decimal `json:"maxPrice"`
+	PriceScale  int             `json:"priceScale"`
+	MinQty      decimal.Decimal `json:"minQty"`
+	MaxQty      decimal.Decimal `json:"maxQty"`
+	MinVal      decimal.Decimal `json:"minVal"`
+	VolumeScale int             `json:"volumeScale"`
+}
+
+type SymbolGetExchangeInfo struct {
+	Symbol             string                  `json:"symbol"`
+	Status             string                  `json:"status"`
+	BaseAsset          string                  `json:"baseAsset"`
+	BaseAssetPrecision int                     `json:"baseAssetPrecision"`
+	QuoteAsset         string                  `json:"quoteAsset"`
+	QuotePrecision     int                     `json:"quotePrecision"`
+	OrderTypes         []string                `json:"orderTypes"`
+	IcebergAllowed     bool                    `json:"icebergAllowed"`
+	Filters            []FilterGetExchangeInfo `json:"filters"`
+}
+
+type ChainGetExchangeInfo struct {
+	Chain          string          `json:"chain"`
+	EnableWithdraw bool            `json:"enableWithdraw"`
+	EnableDeposit  bool            `json:"enableDeposit"`
+	WithdrawFee    decimal.Decimal `json:"withdrawFee"`
+	MinWithdraw    decimal.Decimal `json:"minWithdraw"`
+	MaxWithdraw    decimal.Decimal `json:"maxWithdraw"`
+}
+
+// CoinGetExchangeInfo is the network configuration of a coin
+type CoinGetExchangeInfo struct {
+	Coin           string                 `json:"coin"`
+	CoinFullName   string                 `json:"coinFulName"`
+	EnableWithdraw bool                   `json:"enableWithdraw"`
+	EnableDeposit  bool                   `json:"enableDeposit"`
+	Chains         []string               `json:"chains"`
+	ChainDetail    []ChainGetExchangeInfo `json:"chainDetail"`
+}
+
+type ResponseGetExchangeInfo struct {
+	Timezone   string                  `json:"timezone"`
+	ServerTime int64                   `json:"serverTime"`
+	Symbols    []SymbolGetExchangeInfo `json:"symbols"`
+	Coins      []CoinGetExchangeInfo   `json:"coins"`
+}
+
+// GetExchangeInfo returns the status of the symbols and the networks of the coins
+func (c *Client) GetExchangeInfo(ctx context.Context) (ResponseGetExchangeInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/api/v1/exchangeInfo", nil)
+	if err != nil {
+		return ResponseGetExchangeInfo{}, err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return ResponseGetExchangeInfo{}, err
+	}
+
+	var res ResponseGetExchangeInfo
+	if err := json.Unmarshal(body, &res); err != nil {
+		return ResponseGetExchangeInfo{}, err
+	}
+
+	return res, nil
+}
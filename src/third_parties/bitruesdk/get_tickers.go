@@ -1,8 +1,8 @@
 package bitruesdk
 
 import (
+	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 
 	"github.com/shopspring/decimal"
@@ -28,15 +28,13 @@ type ResponseGetTickersInformation struct {
 	Data map[string]DataGetTickersInformation `json:"data"`
 }
 
-func GetTickersInformation() (*ResponseGetTickersInformation, error) {
-	url := "https://www.bitrue.com/kline-api/public.json?command=returnTicker"
-	resp, err := http.Get(url)
+func (c *Client) GetTickersInformation(ctx context.Context) (*ResponseGetTickersInformation, error) {
+	url := c.BaseURL + "/kline-api/public.json?command=returnTicker"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,159 @@
+package bitruesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderSide string
+
+const (
+	BUY  OrderSide = "BUY"
+	SELL OrderSide = "SELL"
+)
+
+type TimeInForce string
+
+const (
+	GOOD_TILL_CANCEL    TimeInForce = "GTC"
+	IMMEDIATE_OR_CANCEL TimeInForce = "IOC"
+	FILL_OR_KILL        TimeInForce = "FOK"
+)
+
+// Order is a limit order
+type Order struct {
+	Symbol      string
+	Side        OrderSide
+	TimeInForce TimeInForce
+	Quantity    decimal.Decimal
+	Price       decimal.Decimal
+	// ClientOrderID is optional
+	ClientOrderID string
+}
+
+type PostOrderResponse struct {
+	Symbol        string      `json:"symbol"`
+	OrderID       json.Number `json:"orderId"`
+	ClientOrderID string      `json:"clientOrderId"`
+	TransactTime  int64       `json:"transactTime"`
+}
+
+type GetOrderResult struct {
+	Symbol              string          `json:"symbol"`
+	OrderID             json.Number     `json:"orderId"`
+	ClientOrderID       string          `json:"clientOrderId"`
+	Price               decimal.Decimal `json:"price"`
+	OrigQty             decimal.Decimal `json:"origQty"`
+	ExecutedQty         decimal.Decimal `json:"executedQty"`
+	CummulativeQuoteQty decimal.Decimal `json:"cummulativeQuoteQty"`
+	Status              string          `json:"status"`
+	TimeInForce         string          `json:"timeInForce"`
+	Type                string          `json:"type"`
+	Side                OrderSide       `json:"side"`
+	Time                int64           `json:"time"`
+	UpdateTime          int64           `json:"updateTime"`
+	IsWorking           bool            `json:"isWorking"`
+}
+
+func (c *Client) PostOrder(ctx context.Context, apiKey, secretKey string, order Order) (PostOrderResponse, error) {
+	values := url.Values{}
+	values.Set("symbol", order.Symbol)
+	values.Set("side", string(order.Side))
+	values.Set("type", "LIMIT")
+	values.Set("timeInForce", string(order.TimeInForce))
+	values.Set("quantity", order.Quantity.String())
+	values.Set("price", order.Price.String())
+	if order.ClientOrderID != "" {
+		values.Set("newClientOrderId", order.ClientOrderID)
+	}
+
+	body, err := c.doSigned(ctx, "POST", "/api/v1/order", apiKey, secretKey, values)
+	if err != nil {
+		return PostOrderResponse{}, err
+	}
+
+	var res PostOrderResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return PostOrderResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetOrder(ctx context.Context, apiKey, secretKey, symbol, orderID string) (GetOrderResult, error) {
+	values := url.Values{}
+	values.Set("symbol", symbol)
+	values.Set("orderId", orderID)
+
+	body, err := c.doSigned(ctx, "GET", "/api/v1/order", apiKey, secretKey, values)
+	if err != nil {
+		return GetOrderResult{}, err
+	}
+
+	var res GetOrderResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return GetOrderResult{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) CancelOrder(ctx context.Context, apiKey, secretKey, symbol, orderID string) error {
+	values := url.Values{}
+	values.Set("symbol", symbol)
+	values.Set("orderId", orderID)
+
+	_, err := c.doSigned(ctx, "DELETE", "/api/v1/order", apiKey, secretKey, values)
+	return err
+}
+
+func (c *Client) GetOpenOrders(ctx context.Context, apiKey, secretKey, symbol string) ([]GetOrderResult, error) {
+	values := url.Values{}
+	values.Set("symbol", symbol)
+
+	body, err := c.doSigned(ctx, "GET", "/api/v1/openOrders", apiKey, secretKey, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []GetOrderResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type GetMyTradesResult struct {
+	Symbol          string          `json:"symbol"`
+	ID              json.Number     `json:"id"`
+	OrderID         json.Number     `json:"orderId"`
+	Price           decimal.Decimal `json:"price"`
+	Qty             decimal.Decimal `json:"qty"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commissionAssert"` // sic
+	Time            int64           `json:"time"`
+	IsBuyer         bool            `json:"isBuyer"`
+	IsMaker         bool            `json:"isMaker"`
+}
+
+// GetMyTrades returns the latest trades of the account on symbol, they cannot be filtered by order
+func (c *Client) GetMyTrades(ctx context.Context, apiKey, secretKey, symbol string) ([]GetMyTradesResult, error) {
+	values := url.Values{}
+	values.Set("symbol", symbol)
+
+	body, err := c.doSigned(ctx, "GET", "/api/v2/myTrades", apiKey, secretKey, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []GetMyTradesResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,31 @@
+package bitruesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type ResponseGetServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+// GetServerTime returns the time of the exchange, in milliseconds
+func (c *Client) GetServerTime(ctx context.Context) (ResponseGetServerTime, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/api/v1/time", nil)
+	if err != nil {
+		return ResponseGetServerTime{}, err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return ResponseGetServerTime{}, err
+	}
+
+	var res ResponseGetServerTime
+	if err := json.Unmarshal(body, &res); err != nil {
+		return ResponseGetServerTime{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,118 @@
+package bitruesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// walletResponse is the envelope of the wallet endpoints, code is 200 on success
+type walletResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+type Withdraw struct {
+	Coin      string
+	ChainName string
+	AddressTo string
+	Tag       string // memo of the address, if any
+	Amount    decimal.Decimal
+}
+
+type WithdrawResult struct {
+	WithdrawID json.Number `json:"withdrawId"`
+}
+
+// Transfer is a withdrawal or a deposit. The status of a withdrawal is 0 to 4 while processing,
+// 5 when failed and 6 when completed. The one of a deposit is 0 while confirming, 1 when completed
+// and 2 when failed.
+type Transfer struct {
+	ID            json.Number     `json:"id"`
+	Symbol        string          `json:"symbol"`
+	ChainName     string          `json:"chainName"`
+	Amount        decimal.Decimal `json:"amount"`
+	Fee           decimal.Decimal `json:"fee"`
+	CreatedAt     int64           `json:"createdAt"`
+	UpdatedAt     int64           `json:"updatedAt"`
+	AddressFrom   string          `json:"addressFrom"`
+	AddressTo     string          `json:"addressTo"`
+	Tag           string          `json:"tag"`
+	TxID          string          `json:"txid"`
+	Confirmations int             `json:"confirmations"`
+	Status        int             `json:"status"`
+}
+
+// Withdraw sends amount coin to the address, the fee being taken from the amount
+func (c *Client) Withdraw(ctx context.Context, apiKey, secretKey string, withdraw Withdraw) (WithdrawResult, error) {
+	values := url.Values{}
+	values.Set("coin", withdraw.Coin)
+	values.Set("amount", withdraw.Amount.String())
+	values.Set("addressTo", withdraw.AddressTo)
+	values.Set("chainName", withdraw.ChainName)
+	if withdraw.Tag != "" {
+		values.Set("tag", withdraw.Tag)
+	}
+
+	var res WithdrawResult
+	err := c.wallet(ctx, "POST", "/api/v1/withdraw/commit", apiKey, secretKey, values, &res)
+	return res, err
+}
+
+// HistoryParams selects a page of the withdrawals or the deposits of Coin, the latest first
+type HistoryParams struct {
+	Coin string
+	// Offset is the number of the latest transfers skipped
+	Offset int
+	// Limit is the number of transfers returned, 1000 at most, 500 when zero
+	Limit int
+}
+
+func (p HistoryParams) values() url.Values {
+	values := url.Values{}
+	values.Set("coin", p.Coin)
+	if p.Offset != 0 {
+		values.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return values
+}
+
+// GetWithdrawHistory returns a page of the withdrawals of params.Coin
+func (c *Client) GetWithdrawHistory(ctx context.Context, apiKey, secretKey string, params HistoryParams) ([]Transfer, error) {
+	var res []Transfer
+	err := c.wallet(ctx, "GET", "/api/v1/withdraw/history", apiKey, secretKey, params.values(), &res)
+	return res, err
+}
+
+// GetDepositHistory returns a page of the deposits of params.Coin
+func (c *Client) GetDepositHistory(ctx context.Context, apiKey, secretKey string, params HistoryParams) ([]Transfer, error) {
+	var res []Transfer
+	err := c.wallet(ctx, "GET", "/api/v1/deposit/history", apiKey, secretKey, params.values(), &res)
+	return res, err
+}
+
+// wallet sends a signed request to a wallet endpoint and decodes its data into res
+func (c *Client) wallet(ctx context.Context, method, path, apiKey, secretKey string, params url.Values, res interface{}) error {
+	body, err := c.doSigned(ctx, method, path, apiKey, secretKey, params)
+	if err != nil {
+		return err
+	}
+
+	var response walletResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if response.Code != 200 {
+		return &APIError{StatusCode: http.StatusOK, Code: response.Code, Msg: response.Msg}
+	}
+
+	return json.Unmarshal(response.Data, res)
+}
@@ -0,0 +1,81 @@
+package bitruesdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is an error answered by Bitrue, with the code and the message of its body when it holds them. The wallet
+// endpoints answer theirs with a 200 status.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error: %v: %v (%v)", e.StatusCode, e.Code, e.Msg)
+}
+
+// newAPIError reads the error answered in resp, whose body is body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Msg == "" {
+		apiErr.Msg = strings.TrimSpace(string(body))
+	}
+	if apiErr.Msg == "" {
+		apiErr.Msg = resp.Status
+	}
+	return apiErr
+}
+
+// signQuery adds the timestamp now and the HMAC-SHA256 signature of the query to params
+func signQuery(params url.Values, secretKey string, now time.Time) string {
+	params.Set("timestamp", strconv.FormatInt(now.UnixMilli(), 10))
+	query := params.Encode()
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(query))
+	return query + "&signature=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// doSigned sends a request signed with the API key, the parameters being in the query
+func (c *Client) doSigned(ctx context.Context, method, path, apiKey, secretKey string, params url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path+"?"+signQuery(params, secretKey, c.now()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-MBX-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req)
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,26 @@
+package coingecko
+
+import "net/http"
+
+const DefaultBaseURL = "https://api.coingecko.com"
+
+// Client sends the requests to BaseURL through HTTPClient
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client of baseURL, or DefaultBaseURL when empty, using http.DefaultClient
+// when httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+	}
+}
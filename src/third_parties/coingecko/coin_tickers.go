@@ -1,6 +1,7 @@
 package coingecko
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -46,24 +47,28 @@ type CryptoData struct {
 	Tickers []Ticker `json:"tickers"`
 }
 
-func GetCoinTickers(apiKey, coinID string) (CryptoData, error) {
-	url := "https://api.coingecko.com/api/v3/coins/" + coinID + "/tickers?x-cg-pro-api-key=" + apiKey
+func (c *Client) GetCoinTickers(ctx context.Context, apiKey, coinID string) (CryptoData, error) {
+	url := c.BaseURL + "/api/v3/coins/" + coinID + "/tickers?x-cg-pro-api-key=" + apiKey
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return CryptoData{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return CryptoData{}, fmt.Errorf("Error: %v:%v (%v)", resp.StatusCode, resp.Status, resp.Body)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return CryptoData{}, err
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return CryptoData{}, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return CryptoData{}, fmt.Errorf("Error: %v:%v (%v)", resp.StatusCode, resp.Status, string(body))
+	}
+
 	var ticker CryptoData
 	if err := json.Unmarshal(body, &ticker); err != nil {
 		return CryptoData{}, err
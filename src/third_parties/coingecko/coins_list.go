@@ -1,6 +1,7 @@
 package coingecko
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,23 +14,28 @@ type Coin struct {
 	Name   string `json:"name"`
 }
 
-func GetCoinsList(apiKey string) ([]Coin, error) {
-	url := "https://api.coingecko.com/api/v3/coins/list?x-cg-pro-api-key=" + apiKey
+func (c *Client) GetCoinsList(ctx context.Context, apiKey string) ([]Coin, error) {
+	url := c.BaseURL + "/api/v3/coins/list?x-cg-pro-api-key=" + apiKey
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("Error: %v:%v (%v)", resp.StatusCode, resp.Status, resp.Body)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Error: %v:%v (%v)", resp.StatusCode, resp.Status, string(body))
+	}
+
 	var coins []Coin
 	if err := json.Unmarshal(body, &coins); err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package gateio_v2
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -37,9 +38,13 @@ type ResponseGetMarketInfo struct {
 	Data   []DataGetMarketInfo `json:"data"`
 }
 
-func GetMarketInfo() (*ResponseGetMarketInfo, error) {
+func GetMarketInfo(ctx context.Context) (*ResponseGetMarketInfo, error) {
 	url := "https://data.gateapi.io/api2/1/marketlist"
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
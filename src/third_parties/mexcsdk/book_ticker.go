@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -17,23 +17,24 @@ type ResponseGetBookTickers struct {
 	AskQty   string          `json:"askQty"`
 }
 
-func GetBookTickers() ([]ResponseGetBookTickers, error) {
-	url := "https://api.mexc.com/api/v3/ticker/bookTicker"
-	resp, err := http.Get(url)
+func (c *Client) GetBookTickers(ctx context.Context) ([]ResponseGetBookTickers, error) {
+	url := c.BaseURL + "/api/v3/ticker/bookTicker"
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, resp.Body)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
 	var res []ResponseGetBookTickers
 	if err := json.Unmarshal(body, &res); err != nil {
 		return nil, err
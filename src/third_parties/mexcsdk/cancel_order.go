@@ -0,0 +1,50 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type CancelOrderParams struct {
+	Symbol  string `json:"symbol"`
+	OrderId string `json:"orderId,omitempty"`
+}
+
+// CancelOrder cancels an open order, the result is the state of the order once cancelled
+func (c *Client) CancelOrder(ctx context.Context, apiKey, secretKey string, order CancelOrderParams) (GetOrderResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/order"
+
+	values := url.Values{}
+	values.Set("symbol", order.Symbol)
+	values.Set("orderId", order.OrderId)
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "DELETE", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return GetOrderResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GetOrderResult{}, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return GetOrderResult{}, newAPIError(resp, body)
+	}
+
+	var res GetOrderResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return GetOrderResult{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type DepositAddressResult struct {
+	Coin    string `json:"coin"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Memo    string `json:"memo"`
+}
+
+// GetDepositAddress returns the deposit addresses of coin on network
+func (c *Client) GetDepositAddress(ctx context.Context, apiKey, secretKey, coin, network string) ([]DepositAddressResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/capital/deposit/address"
+
+	values := url.Values{}
+	values.Set("coin", coin)
+	values.Set("network", network)
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var res []DepositAddressResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+// DepositStatus is 1 SMALL, 2 TIME_DELAY, 3 LARGE_DELAY, 4 PENDING, 5 SUCCESS, 6 AUDITING or 7 REJECTED
+type DepositStatus int
+
+const (
+	DepositStatusPending  DepositStatus = 4
+	DepositStatusSuccess  DepositStatus = 5
+	DepositStatusRejected DepositStatus = 7
+)
+
+type DepositHistoryResult struct {
+	Amount     decimal.Decimal `json:"amount"`
+	Coin       string          `json:"coin"`
+	Network    string          `json:"network"`
+	Status     DepositStatus   `json:"status"`
+	Address    string          `json:"address"`
+	Memo       string          `json:"memo"`
+	TxId       string          `json:"txId"`
+	InsertTime int64           `json:"insertTime"`
+}
+
+// GetDepositHistory returns a page of the deposits of params.Coin
+func (c *Client) GetDepositHistory(ctx context.Context, apiKey, secretKey string, params HistoryParams) ([]DepositHistoryResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/capital/deposit/hisrec"
+
+	values := params.values()
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var res []DepositHistoryResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -16,23 +16,24 @@ type ResponseGetDepth struct {
 	Timestamp    int64               `json:"timestamp"`
 }
 
-func GetDepth(symbol string) (ResponseGetDepth, error) {
-	url := "https://api.mexc.com/api/v3/depth?symbol=" + symbol
-	resp, err := http.Get(url)
+func (c *Client) GetDepth(ctx context.Context, symbol string) (ResponseGetDepth, error) {
+	url := c.BaseURL + "/api/v3/depth?symbol=" + symbol
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return ResponseGetDepth{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return ResponseGetDepth{}, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, resp.Body)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return ResponseGetDepth{}, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return ResponseGetDepth{}, newAPIError(resp, body)
+	}
+
 	var res ResponseGetDepth
 	if err := json.Unmarshal(body, &res); err != nil {
 		return ResponseGetDepth{}, err
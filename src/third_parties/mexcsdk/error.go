@@ -0,0 +1,31 @@
+package mexcsdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error answered by MEXC, with the code and the message of its body when it holds them
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error: %v: %v (%v)", e.StatusCode, e.Code, e.Msg)
+}
+
+// newAPIError reads the error answered in resp, whose body is body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Msg == "" {
+		apiErr.Msg = strings.TrimSpace(string(body))
+	}
+	if apiErr.Msg == "" {
+		apiErr.Msg = resp.Status
+	}
+	return apiErr
+}
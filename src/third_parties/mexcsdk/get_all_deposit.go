@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -35,29 +35,28 @@ type GetAllDepositResponse struct {
 	NetworkList []NetworkGetAllDeposit `json:"networkList"`
 }
 
-func GetAllDeposit(apiKey, secretKey string) ([]GetAllDepositResponse, error) {
-	url := "https://api.mexc.com/api/v3/capital/config/getall"
+func (c *Client) GetAllDeposit(ctx context.Context, apiKey, secretKey string) ([]GetAllDepositResponse, error) {
+	url := c.BaseURL + "/api/v3/capital/config/getall"
 
-	client := &http.Client{}
-	finalUrl := sign(url, "", secretKey)
-	req, _ := http.NewRequest("GET", finalUrl, nil)
+	finalUrl := c.sign(url, "", secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
 	req.Header.Set("X-MEXC-APIKEY", apiKey)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, body)
-	}
-
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
 	var res []GetAllDepositResponse
 	if err := json.Unmarshal(body, &res); err != nil {
 		return nil, err
@@ -1,11 +1,12 @@
 package mexcsdk_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ArbitrageCoin/crypto-sdk/src/third_parties/mexcsdk"
 )
 
 func TestGetAllDeposit(t *testing.T) {
-	mexcsdk.GetAllDeposit("...", "...")
+	mexcsdk.NewClient("", nil).GetAllDeposit(context.Background(), "...", "...")
 }
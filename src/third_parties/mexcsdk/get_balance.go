@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -29,29 +29,28 @@ type ResponseGetBalance struct {
 	Permissions      []string            `json:"permissions"`
 }
 
-func GetBalance(apiKey, secretKey string) (ResponseGetBalance, error) {
-	url := "https://api.mexc.com/api/v3/account"
+func (c *Client) GetBalance(ctx context.Context, apiKey, secretKey string) (ResponseGetBalance, error) {
+	url := c.BaseURL + "/api/v3/account"
 
-	finalUrl := sign(url, "", secretKey)
-	client := &http.Client{}
-	req, _ := http.NewRequest("GET", finalUrl, nil)
+	finalUrl := c.sign(url, "", secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
 	req.Header.Set("X-MEXC-APIKEY", apiKey)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return ResponseGetBalance{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return ResponseGetBalance{}, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, body)
-	}
-
 	if err != nil {
 		return ResponseGetBalance{}, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return ResponseGetBalance{}, newAPIError(resp, body)
+	}
+
 	var res ResponseGetBalance
 	if err := json.Unmarshal(body, &res); err != nil {
 		return ResponseGetBalance{}, err
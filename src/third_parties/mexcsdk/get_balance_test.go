@@ -1,6 +1,7 @@
 package mexcsdk_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,7 +9,7 @@ import (
 )
 
 func TestGetBalance(t *testing.T) {
-	resp, err := mexcsdk.GetBalance("...", "...")
+	resp, err := mexcsdk.NewClient("", nil).GetBalance(context.Background(), "...", "...")
 
 	fmt.Println(resp)
 	if err != nil {
@@ -3,7 +3,6 @@ package mexcsdk
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 )
@@ -41,22 +40,23 @@ type ResponseGetExchangeInfo struct {
 	Symbols         []SymbolGetExchangeInfo `json:"symbols"`
 }
 
-func GetExchangeInfo(ctx context.Context) (ResponseGetExchangeInfo, error) {
-	url := "https://api.mexc.com/api/v3/exchangeInfo"
-	resp, err := http.Get(url)
+func (c *Client) GetExchangeInfo(ctx context.Context) (ResponseGetExchangeInfo, error) {
+	url := c.BaseURL + "/api/v3/exchangeInfo"
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return ResponseGetExchangeInfo{}, err
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return ResponseGetExchangeInfo{}, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, body)
-	}
-
 	if err != nil {
 		return ResponseGetExchangeInfo{}, err
 	}
 
+	if resp.StatusCode >= 400 {
+		return ResponseGetExchangeInfo{}, newAPIError(resp, body)
+	}
+
 	var res ResponseGetExchangeInfo
 	if err := json.Unmarshal(body, &res); err != nil {
 		return ResponseGetExchangeInfo{}, err
@@ -8,5 +8,5 @@ import (
 )
 
 func TestGetExchangeInfo(t *testing.T) {
-	mexcsdk.GetExchangeInfo(context.Background())
+	mexcsdk.NewClient("", nil).GetExchangeInfo(context.Background())
 }
@@ -0,0 +1,71 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/shopspring/decimal"
+)
+
+type GetMyTradesParams struct {
+	Symbol  string `json:"symbol"`
+	OrderId string `json:"orderId,omitempty"`
+}
+
+type GetMyTradesResult struct {
+	Symbol          string          `json:"symbol"`
+	Id              string          `json:"id"`
+	OrderId         string          `json:"orderId"`
+	OrderListId     int64           `json:"orderListId"`
+	Price           decimal.Decimal `json:"price"`
+	Qty             decimal.Decimal `json:"qty"`
+	QuoteQty        decimal.Decimal `json:"quoteQty"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commissionAsset"`
+	Time            int64           `json:"time"`
+	IsBuyer         bool            `json:"isBuyer"`
+	IsMaker         bool            `json:"isMaker"`
+	IsBestMatch     bool            `json:"isBestMatch"`
+	IsSelfTrade     bool            `json:"isSelfTrade"`
+	ClientOrderId   string          `json:"clientOrderId"`
+}
+
+func (c *Client) GetMyTrades(ctx context.Context, apiKey, secretKey string, params GetMyTradesParams) ([]GetMyTradesResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/myTrades"
+
+	values := url.Values{}
+	values.Set("symbol", params.Symbol)
+	if params.OrderId != "" {
+		values.Set("orderId", params.OrderId)
+	}
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var res []GetMyTradesResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
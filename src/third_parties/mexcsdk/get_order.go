@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -35,20 +35,19 @@ type GetOrderResult struct {
 	OrigQuoteOrderQty   decimal.Decimal `json:"origQuoteOrderQty"`
 }
 
-func GetOrder(apiKey, secretKey string, order GetOrderParams) (GetOrderResult, error) {
-	baseUrl := "https://api.mexc.com/api/v3/order"
-	client := &http.Client{}
+func (c *Client) GetOrder(ctx context.Context, apiKey, secretKey string, order GetOrderParams) (GetOrderResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/order"
 
 	values := url.Values{}
 	values.Set("symbol", order.Symbol)
 	values.Set("orderId", order.OrderId)
 
-	finalUrl := signQuery(baseUrl, values.Encode(), secretKey)
-	req, _ := http.NewRequest("GET", finalUrl, nil)
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
 	req.Header.Set("X-MEXC-APIKEY", apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return GetOrderResult{}, err
 	}
@@ -60,7 +59,7 @@ func GetOrder(apiKey, secretKey string, order GetOrderParams) (GetOrderResult, e
 	}
 
 	if resp.StatusCode >= 400 {
-		return GetOrderResult{}, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, body)
+		return GetOrderResult{}, newAPIError(resp, body)
 	}
 
 	var res GetOrderResult
@@ -1,6 +1,7 @@
 package mexcsdk_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,7 +9,7 @@ import (
 )
 
 func TestGetOrder(t *testing.T) {
-	resp, err := mexcsdk.GetOrder(
+	resp, err := mexcsdk.NewClient("", nil).GetOrder(context.Background(),
 		"...", "...", mexcsdk.GetOrderParams{
 			Symbol:  "USDCUSDT",
 			OrderId: "...",
@@ -0,0 +1,53 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// GetOpenOrders returns the orders of symbol still in the book
+func (c *Client) GetOpenOrders(ctx context.Context, apiKey, secretKey, symbol string) ([]GetOrderResult, error) {
+	return c.openOrders(ctx, "GET", apiKey, secretKey, symbol)
+}
+
+// CancelOpenOrders cancels every open order of symbol, and returns them once cancelled
+func (c *Client) CancelOpenOrders(ctx context.Context, apiKey, secretKey, symbol string) ([]GetOrderResult, error) {
+	return c.openOrders(ctx, "DELETE", apiKey, secretKey, symbol)
+}
+
+func (c *Client) openOrders(ctx context.Context, method, apiKey, secretKey, symbol string) ([]GetOrderResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/openOrders"
+
+	values := url.Values{}
+	values.Set("symbol", symbol)
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, method, finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var res []GetOrderResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -1,8 +1,8 @@
 package mexcsdk
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -44,15 +44,14 @@ type Order struct {
 	Type     OrderType       `json:"type"`               // Type of the order (LIMIT, MARKET, etc.) (mandatory)
 	Quantity decimal.Decimal `json:"quantity,omitempty"` // Quantity of the order (optional)
 	//QuoteOrderQty    decimal.Decimal `json:"quoteOrderQty,omitempty"`     // Quote order quantity (optional)
-	Price decimal.Decimal `json:"price,omitempty"` // Price of the order (optional)
-	//NewClientOrderId string          `json:"newClientOrderId,omitempty"`  // New client order ID (optional)
+	Price            decimal.Decimal `json:"price,omitempty"`            // Price of the order (optional)
+	NewClientOrderId string          `json:"newClientOrderId,omitempty"` // New client order ID (optional)
 	//RecvWindow       int64           `json:"recvWindow,omitempty"`        // Receive window, max 60000 (optional)
 	//Timestamp        int64           `json:"timestamp"`                   // Timestamp of the order (mandatory)
 }
 
-func PostOrder(apiKey, secretKey string, order Order) (PostOrderResponse, error) {
-	baseUrl := "https://api.mexc.com/api/v3/order"
-	client := &http.Client{}
+func (c *Client) PostOrder(ctx context.Context, apiKey, secretKey string, order Order) (PostOrderResponse, error) {
+	baseUrl := c.BaseURL + "/api/v3/order"
 
 	values := url.Values{}
 	values.Set("symbol", order.Symbol)
@@ -60,13 +59,16 @@ func PostOrder(apiKey, secretKey string, order Order) (PostOrderResponse, error)
 	values.Set("type", string(order.Type))
 	values.Set("quantity", order.Quantity.String())
 	values.Set("price", order.Price.String())
+	if order.NewClientOrderId != "" {
+		values.Set("newClientOrderId", order.NewClientOrderId)
+	}
 
-	finalUrl := signQuery(baseUrl, values.Encode(), secretKey)
-	req, _ := http.NewRequest("POST", finalUrl, nil)
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "POST", finalUrl, nil)
 	req.Header.Set("X-MEXC-APIKEY", apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return PostOrderResponse{}, err
 	}
@@ -78,7 +80,7 @@ func PostOrder(apiKey, secretKey string, order Order) (PostOrderResponse, error)
 	}
 
 	if resp.StatusCode >= 400 {
-		return PostOrderResponse{}, fmt.Errorf("error: %v:%v (%v)", resp.StatusCode, resp.Status, body)
+		return PostOrderResponse{}, newAPIError(resp, body)
 	}
 
 	var res PostOrderResponse
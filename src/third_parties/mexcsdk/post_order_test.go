@@ -1,6 +1,7 @@
 package mexcsdk_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func TestPostOrder(t *testing.T) {
-	resp, err := mexcsdk.PostOrder(
+	resp, err := mexcsdk.NewClient("", nil).PostOrder(context.Background(),
 		"...", "...", mexcsdk.Order{
 			Symbol:   "USDCUSDT",
 			Side:     mexcsdk.BUY,
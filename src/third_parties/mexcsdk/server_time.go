@@ -0,0 +1,39 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type ResponseGetServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+// GetServerTime returns the time of the exchange, in milliseconds
+func (c *Client) GetServerTime(ctx context.Context) (ResponseGetServerTime, error) {
+	url := c.BaseURL + "/api/v3/time"
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return ResponseGetServerTime{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ResponseGetServerTime{}, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return ResponseGetServerTime{}, newAPIError(resp, body)
+	}
+
+	var res ResponseGetServerTime
+	if err := json.Unmarshal(body, &res); err != nil {
+		return ResponseGetServerTime{}, err
+	}
+
+	return res, nil
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-	"time"
 )
 
 func JsonToParamStr(jsonParams string) string {
@@ -44,8 +43,8 @@ func computeHmac256(message string, secKey string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func sign(url, jsonParams, secretKey string) string {
-	timestamp := int64(float64(time.Now().UnixMilli()))
+func (c *Client) sign(url, jsonParams, secretKey string) string {
+	timestamp := c.now().UnixMilli()
 	path := ""
 	if jsonParams == "" {
 		message := fmt.Sprintf("timestamp=%d", timestamp)
@@ -61,8 +60,8 @@ func sign(url, jsonParams, secretKey string) string {
 	return path
 }
 
-func signQuery(url, queryParams, secretKey string) string {
-	timestamp := int64(float64(time.Now().UnixMilli()))
+func (c *Client) signQuery(url, queryParams, secretKey string) string {
+	timestamp := c.now().UnixMilli()
 	message := fmt.Sprintf("%s&timestamp=%d", queryParams, timestamp)
 	sign := computeHmac256(message, secretKey)
 	path := fmt.Sprintf("%s?%s&timestamp=%d&signature=%s", url, queryParams, timestamp, sign)
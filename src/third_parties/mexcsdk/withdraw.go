@@ -0,0 +1,64 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/shopspring/decimal"
+)
+
+type WithdrawParams struct {
+	Coin    string          `json:"coin"`
+	Network string          `json:"network"`
+	Address string          `json:"address"`
+	Memo    string          `json:"memo,omitempty"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+type WithdrawResult struct {
+	Id string `json:"id"`
+}
+
+// Withdraw sends amount coin to address, the fee of the network being taken from the amount
+func (c *Client) Withdraw(ctx context.Context, apiKey, secretKey string, withdraw WithdrawParams) (WithdrawResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/capital/withdraw/apply"
+
+	values := url.Values{}
+	values.Set("coin", withdraw.Coin)
+	values.Set("network", withdraw.Network)
+	values.Set("address", withdraw.Address)
+	values.Set("amount", withdraw.Amount.String())
+	if withdraw.Memo != "" {
+		values.Set("memo", withdraw.Memo)
+	}
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "POST", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return WithdrawResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return WithdrawResult{}, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return WithdrawResult{}, newAPIError(resp, body)
+	}
+
+	var res WithdrawResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return WithdrawResult{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package mexcsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawStatus is 1 APPLY, 2 AUDITING, 3 WAIT, 4 PROCESSING, 5 WAIT_PACKAGING, 6 WAIT_CONFIRM,
+// 7 SUCCESS, 8 FAILED, 9 CANCEL or 10 MANUAL
+type WithdrawStatus int
+
+const (
+	WithdrawStatusSuccess WithdrawStatus = 7
+	WithdrawStatusFailed  WithdrawStatus = 8
+	WithdrawStatusCancel  WithdrawStatus = 9
+)
+
+type WithdrawHistoryResult struct {
+	Id             string          `json:"id"`
+	TxId           string          `json:"txId"`
+	Coin           string          `json:"coin"`
+	Network        string          `json:"network"`
+	Address        string          `json:"address"`
+	Memo           string          `json:"memo"`
+	Amount         decimal.Decimal `json:"amount"`
+	TransactionFee decimal.Decimal `json:"transactionFee"`
+	Status         WithdrawStatus  `json:"status"`
+	ApplyTime      int64           `json:"applyTime"`
+	TransferType   int             `json:"transferType"`
+}
+
+// HistoryParams selects a page of the withdrawals or the deposits of Coin, the latest first
+type HistoryParams struct {
+	Coin string
+	// EndTime is the time in milliseconds of the latest transfer returned, now when zero
+	EndTime int64
+	// Limit is the number of transfers returned, 1000 at most, 1000 when zero
+	Limit int
+}
+
+func (p HistoryParams) values() url.Values {
+	values := url.Values{}
+	values.Set("coin", p.Coin)
+	if p.EndTime != 0 {
+		values.Set("endTime", strconv.FormatInt(p.EndTime, 10))
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return values
+}
+
+// GetWithdrawHistory returns a page of the withdrawals of params.Coin
+func (c *Client) GetWithdrawHistory(ctx context.Context, apiKey, secretKey string, params HistoryParams) ([]WithdrawHistoryResult, error) {
+	baseUrl := c.BaseURL + "/api/v3/capital/withdraw/history"
+
+	values := params.values()
+
+	finalUrl := c.signQuery(baseUrl, values.Encode(), secretKey)
+	req, _ := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
+	req.Header.Set("X-MEXC-APIKEY", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var res []WithdrawHistoryResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -1,48 +1,80 @@
 package xt_com
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 var BaseUrl = "https://sapi.xt.com"
 
+// HttpOption selects where and how the requests are sent, by default to BaseUrl through
+// http.DefaultClient
+type HttpOption struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Now returns the time the signed requests are timestamped with, the local time when nil
+	Now func() time.Time
+}
+
+func (o HttpOption) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+func (o HttpOption) baseURL() string {
+	if o.BaseURL == "" {
+		return BaseUrl
+	}
+	return o.BaseURL
+}
+
 type XTPublicSpotHelper interface {
 	// Public
-	GetServerTime() *APIBody                              // Getting the server time
-	GetCoinsInfo() *APIBody                               // Get the currency information
-	GetMarketConfig(data map[string]interface{}) *APIBody // Get market configuration info
-	GetAllMarketConfig() *APIBody                         // Get configuration information for all pairs
-	GetDepth(data map[string]interface{}) *APIBody        //  Get the market depth
-	GetKline(data map[string]interface{}) *APIBody        // Get k-line information
-	GetTrades(data map[string]interface{}) *APIBody       // Get the most recent transaction
-	GetTicker(data map[string]interface{}) *APIBody       // Get the latest market information
-	GetFullTicker(data map[string]interface{}) *APIBody   // Get the latest market information for all currencies
-	GetBestTicker(data map[string]interface{}) *APIBody   // Get the best market information
-	Get24hTicker(data map[string]interface{}) *APIBody    // Get information about the 24-hour market
+	GetServerTime(ctx context.Context) *APIBody                                // Getting the server time
+	GetCoinsInfo(ctx context.Context) *APIBody                                 // Get the currency information
+	GetSupportCurrency(ctx context.Context) *APIBody                           // Get the networks of each currency
+	GetMarketConfig(ctx context.Context, data map[string]interface{}) *APIBody // Get market configuration info
+	GetAllMarketConfig(ctx context.Context) *APIBody                           // Get configuration information for all pairs
+	GetDepth(ctx context.Context, data map[string]interface{}) *APIBody        //  Get the market depth
+	GetKline(ctx context.Context, data map[string]interface{}) *APIBody        // Get k-line information
+	GetTrades(ctx context.Context, data map[string]interface{}) *APIBody       // Get the most recent transaction
+	GetTicker(ctx context.Context, data map[string]interface{}) *APIBody       // Get the latest market information
+	GetFullTicker(ctx context.Context, data map[string]interface{}) *APIBody   // Get the latest market information for all currencies
+	GetBestTicker(ctx context.Context, data map[string]interface{}) *APIBody   // Get the best market information
+	Get24hTicker(ctx context.Context, data map[string]interface{}) *APIBody    // Get information about the 24-hour market
 }
 
 type XTPrivateSpotHelper interface {
 	// Private
-	GetOrder(data map[string]interface{}) *APIBody         // Query order information
-	GetOrderList(data map[string]interface{}) *APIBody     // Query order information
-	CancelOrder(orderId string) *APIBody                   // Cancel the order
-	SendOrder(data map[string]interface{}) *APIBody        // order
-	GetBatchOrder(data map[string]interface{}) *APIBody    // Get orders in bulk
-	SendBatchOrder(data map[string]interface{}) *APIBody   //  Bulk order
-	BatchCancelOrder(data map[string]interface{}) *APIBody // Batch cancel order
-	GetOpenOrder(data map[string]interface{}) *APIBody     // Get the outstanding order
-	CancelOpenOrder(data map[string]interface{}) *APIBody  // Cancel the outstanding order
-	GetHistoryOrder(data map[string]interface{}) *APIBody  // Get order history
-	GetUserTrade(data map[string]interface{}) *APIBody     // Get account transaction information
-	GetBalance(data map[string]interface{}) *APIBody       // Get the balance
-	GetListenKey() *APIBody                                // get ListenKey
+	GetOrder(ctx context.Context, data map[string]interface{}) *APIBody           // Query order information
+	GetOrderList(ctx context.Context, data map[string]interface{}) *APIBody       // Query order information
+	CancelOrder(ctx context.Context, orderId string) *APIBody                     // Cancel the order
+	SendOrder(ctx context.Context, data map[string]interface{}) *APIBody          // order
+	GetBatchOrder(ctx context.Context, data map[string]interface{}) *APIBody      // Get orders in bulk
+	SendBatchOrder(ctx context.Context, data map[string]interface{}) *APIBody     //  Bulk order
+	BatchCancelOrder(ctx context.Context, data map[string]interface{}) *APIBody   // Batch cancel order
+	GetOpenOrder(ctx context.Context, data map[string]interface{}) *APIBody       // Get the outstanding order
+	CancelOpenOrder(ctx context.Context, data map[string]interface{}) *APIBody    // Cancel the outstanding order
+	GetHistoryOrder(ctx context.Context, data map[string]interface{}) *APIBody    // Get order history
+	GetUserTrade(ctx context.Context, data map[string]interface{}) *APIBody       // Get account transaction information
+	GetBalance(ctx context.Context, data map[string]interface{}) *APIBody         // Get the balance
+	GetListenKey(ctx context.Context) *APIBody                                    // get ListenKey
+	GetDepositAddress(ctx context.Context, data map[string]interface{}) *APIBody  // Get the deposit address
+	Withdraw(ctx context.Context, data map[string]interface{}) *APIBody           // Withdraw
+	GetWithdrawHistory(ctx context.Context, data map[string]interface{}) *APIBody // Get the withdrawal history
+	GetDepositHistory(ctx context.Context, data map[string]interface{}) *APIBody  // Get the deposit history
 }
 
 type SignedHttpAPI struct {
 	Accesskey string
 	Secretkey string
+	HttpOption
 }
 
 func NewSignedHttpAPI(accesskey, secretkey string) *SignedHttpAPI {
@@ -63,20 +95,20 @@ func (s *SignedHttpAPI) SetAuthOption(accesskey, secretkey string) {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetListenKey() *APIBody {
+func (s SignedHttpAPI) GetListenKey(ctx context.Context) *APIBody {
 	path := "/v4/ws-token"
-	uri := BaseUrl + path
+	uri := s.baseURL() + path
 	auth := NewAuth(s, path, "POST")
 	auth.SetUrlencode(true)
 	data := map[string]interface{}{}
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", uri, false)
+		return APIFailure(err, uri)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("POST", uri, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "POST", uri, headers, data)
 
 	return rep
 }
@@ -88,20 +120,44 @@ func (s SignedHttpAPI) GetListenKey() *APIBody {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetOrder(data map[string]interface{}) *APIBody {
+type ResultGetOrder struct {
+	Symbol        string          `json:"symbol"`
+	OrderID       string          `json:"orderId"`
+	ClientOrderID string          `json:"clientOrderId"`
+	Side          string          `json:"side"`
+	Type          string          `json:"type"`
+	TimeInForce   string          `json:"timeInForce"`
+	Price         decimal.Decimal `json:"price"`
+	OrigQty       decimal.Decimal `json:"origQty"`
+	ExecutedQty   decimal.Decimal `json:"executedQty"`
+	LeavingQty    decimal.Decimal `json:"leavingQty"`
+	AvgPrice      decimal.Decimal `json:"avgPrice"`
+	State         string          `json:"state"`
+	Time          int64           `json:"time"`
+	UpdatedTime   int64           `json:"updatedTime"`
+}
+
+type ResponseGetOrder struct {
+	RC     int            `json:"rc"`
+	MC     string         `json:"mc"`
+	MA     []int          `json:"ma"`
+	Result ResultGetOrder `json:"result"`
+}
+
+func (s SignedHttpAPI) GetOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 
 	path := "/v4/order"
-	uri := BaseUrl + path
+	uri := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", uri, false)
+		return APIFailure(err, uri)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", uri, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", uri, headers, data)
 
 	return rep
 }
@@ -114,19 +170,19 @@ func (s SignedHttpAPI) GetOrder(data map[string]interface{}) *APIBody {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetOrderList(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) GetOrderList(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/order"
-	uri := BaseUrl + path
+	uri := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", uri, false)
+		return APIFailure(err, uri)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", uri, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", uri, headers, data)
 
 	return rep
 }
@@ -147,19 +203,30 @@ func (s SignedHttpAPI) GetOrderList(data map[string]interface{}) *APIBody {
  *			}
  *		}
 **/
-func (s SignedHttpAPI) CancelOrder(orderId string) *APIBody {
+type ResultCancelOrder struct {
+	CancelID string `json:"cancelId"`
+}
+
+type ResponseCancelOrder struct {
+	RC     int               `json:"rc"`
+	MC     string            `json:"mc"`
+	MA     []int             `json:"ma"`
+	Result ResultCancelOrder `json:"result"`
+}
+
+func (s SignedHttpAPI) CancelOrder(ctx context.Context, orderId string) *APIBody {
 	path := "/v4/order"
 	uri := fmt.Sprintf("%s/%s", path, orderId)
-	url := BaseUrl + uri
+	url := s.baseURL() + uri
 	auth := NewAuth(s, uri, "DELETE")
 
 	headers, err := auth.createPayload(map[string]interface{}{})
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", uri, false)
+		return APIFailure(err, uri)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesJson("DELETE", url, headers, map[string]interface{}{})
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "DELETE", url, headers, map[string]interface{}{})
 
 	return rep
 }
@@ -188,18 +255,29 @@ func (s SignedHttpAPI) CancelOrder(orderId string) *APIBody {
  *			}
  *		}
 **/
-func (s SignedHttpAPI) SendOrder(data map[string]interface{}) *APIBody {
+type ResultSendOrder struct {
+	OrderID string `json:"orderId"`
+}
+
+type ResponseSendOrder struct {
+	RC     int             `json:"rc"`
+	MC     string          `json:"mc"`
+	MA     []int           `json:"ma"`
+	Result ResultSendOrder `json:"result"`
+}
+
+func (s SignedHttpAPI) SendOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "POST")
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesJson("POST", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "POST", url, headers, data)
 
 	return rep
 }
@@ -220,19 +298,19 @@ func (s SignedHttpAPI) SendOrder(data map[string]interface{}) *APIBody {
  *			}
  *		}
 **/
-func (s SignedHttpAPI) GetBatchOrder(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) GetBatchOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/batch-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
 
 	return rep
 }
@@ -269,18 +347,18 @@ func (s SignedHttpAPI) GetBatchOrder(data map[string]interface{}) *APIBody {
  *			]
  *		}
 **/
-func (s SignedHttpAPI) SendBatchOrder(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) SendBatchOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/batch-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "POST")
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesJson("POST", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "POST", url, headers, data)
 
 	return rep
 }
@@ -300,18 +378,18 @@ func (s SignedHttpAPI) SendBatchOrder(data map[string]interface{}) *APIBody {
  *		"result": {}
  *		}
 **/
-func (s SignedHttpAPI) BatchCancelOrder(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) BatchCancelOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/batch-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "DELETE")
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesJson("DELETE", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "DELETE", url, headers, data)
 
 	return rep
 }
@@ -325,19 +403,26 @@ func (s SignedHttpAPI) BatchCancelOrder(data map[string]interface{}) *APIBody {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetOpenOrder(data map[string]interface{}) *APIBody {
+type ResponseGetOpenOrder struct {
+	RC     int              `json:"rc"`
+	MC     string           `json:"mc"`
+	MA     []int            `json:"ma"`
+	Result []ResultGetOrder `json:"result"`
+}
+
+func (s SignedHttpAPI) GetOpenOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/open-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
 
 	return rep
 }
@@ -358,18 +443,18 @@ func (s SignedHttpAPI) GetOpenOrder(data map[string]interface{}) *APIBody {
  *		"result": {}
  *		}
 **/
-func (s SignedHttpAPI) CancelOpenOrder(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) CancelOpenOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/open-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "DELETE")
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesJson("DELETE", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "DELETE", url, headers, data)
 
 	return rep
 }
@@ -391,19 +476,19 @@ func (s SignedHttpAPI) CancelOpenOrder(data map[string]interface{}) *APIBody {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetHistoryOrder(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) GetHistoryOrder(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/history-order"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
 
 	return rep
 }
@@ -425,23 +510,54 @@ func (s SignedHttpAPI) GetHistoryOrder(data map[string]interface{}) *APIBody {
  *	@Return
  *		See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (s SignedHttpAPI) GetUserTrade(data map[string]interface{}) *APIBody {
+func (s SignedHttpAPI) GetUserTrade(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/trade"
-	url := BaseUrl + path
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
 
 	return rep
 }
 
+type ItemGetUserTrade struct {
+	Symbol        string          `json:"symbol"`
+	TradeID       string          `json:"tradeId"`
+	OrderID       string          `json:"orderId"`
+	OrderSide     string          `json:"orderSide"`
+	OrderType     string          `json:"orderType"`
+	BizType       string          `json:"bizType"`
+	Time          int64           `json:"time"`
+	Price         decimal.Decimal `json:"price"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	QuoteQty      decimal.Decimal `json:"quoteQty"`
+	BaseCurrency  string          `json:"baseCurrency"`
+	QuoteCurrency string          `json:"quoteCurrency"`
+	Fee           decimal.Decimal `json:"fee"`
+	FeeCurrency   string          `json:"feeCurrency"`
+	TakerMaker    string          `json:"takerMaker"`
+}
+
+type ResultGetUserTrade struct {
+	HasPrev bool               `json:"hasPrev"`
+	HasNext bool               `json:"hasNext"`
+	Items   []ItemGetUserTrade `json:"items"`
+}
+
+type ResponseGetUserTrade struct {
+	RC     int                `json:"rc"`
+	MC     string             `json:"mc"`
+	MA     []int              `json:"ma"`
+	Result ResultGetUserTrade `json:"result"`
+}
+
 /**
  *	@Param:
  *		@Desc     Parameter	    Type	    mandatory    Default	    Description
@@ -484,24 +600,215 @@ type ResultGetBalance struct {
 	Assets          []AssetGetBalance `json:"assets"`
 }
 
-func (s SignedHttpAPI) GetBalance(data map[string]interface{}) *APIBody {
+type ResponseGetBalance struct {
+	RC     int              `json:"rc"`
+	MC     string           `json:"mc"`
+	MA     []int            `json:"ma"`
+	Result ResultGetBalance `json:"result"`
+}
+
+func (s SignedHttpAPI) GetBalance(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/balances"
-	url := BaseUrl + path
+	url := s.baseURL() + path
+	auth := NewAuth(s, path, "GET")
+	auth.SetUrlencode(true)
+
+	headers, err := auth.createPayload(data)
+	if err != nil {
+		return APIFailure(err, url)
+	}
+
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
+
+	return rep
+}
+
+/**
+ *	@Param:
+ *		@Desc     Parameter	    Type	    mandatory    Default	    Description
+ *		::param : chain    	    string	    true		                eg:Tron
+ *		::param : currency    	string	    true		                eg:usdt
+ *	@Return
+ *		{
+ *		"rc": 0,
+ *		"mc": "string",
+ *		"ma": [
+ *			{}
+ *		],
+ *		"result": {
+ *				"address": "TYeB5ab2dsV6wVdKvQnXGWHbLhFDbuEqyj",
+ *				"memo": ""
+ *			}
+ *		}
+**/
+type ResultGetDepositAddress struct {
+	Address string `json:"address"`
+	Memo    string `json:"memo"`
+}
+
+type ResponseGetDepositAddress struct {
+	RC     int                     `json:"rc"`
+	MC     string                  `json:"mc"`
+	MA     []int                   `json:"ma"`
+	Result ResultGetDepositAddress `json:"result"`
+}
+
+func (s SignedHttpAPI) GetDepositAddress(ctx context.Context, data map[string]interface{}) *APIBody {
+	path := "/v4/deposit/address"
+	url := s.baseURL() + path
 	auth := NewAuth(s, path, "GET")
 	auth.SetUrlencode(true)
 
 	headers, err := auth.createPayload(data)
 	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	requestPerpare := NewRequestPerpare()
-	rep := requestPerpare.RequesParam("GET", url, headers, data)
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
+
+	return rep
+}
+
+/**
+ *	@Param:
+ *		@Desc     Parameter	    Type	    mandatory    Default	    Description
+ *		::param : currency    	string	    true		                eg:usdt
+ *		::param : chain    	    string	    true		                eg:Tron
+ *		::param : amount    	number	    true		                Withdrawal amount, including the fee
+ *		::param : address    	string	    true
+ *		::param : memo    	    string	    false
+ *	@Return
+ *		{
+ *		"rc": 0,
+ *		"mc": "string",
+ *		"ma": [
+ *			{}
+ *		],
+ *		"result": {
+ *				"id": 123456
+ *			}
+ *		}
+**/
+type ResultWithdraw struct {
+	ID int64 `json:"id"`
+}
+
+type ResponseWithdraw struct {
+	RC     int            `json:"rc"`
+	MC     string         `json:"mc"`
+	MA     []int          `json:"ma"`
+	Result ResultWithdraw `json:"result"`
+}
+
+func (s SignedHttpAPI) Withdraw(ctx context.Context, data map[string]interface{}) *APIBody {
+	path := "/v4/withdraw"
+	url := s.baseURL() + path
+	auth := NewAuth(s, path, "POST")
+
+	headers, err := auth.createPayload(data)
+	if err != nil {
+		return APIFailure(err, url)
+	}
+
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesJson(ctx, "POST", url, headers, data)
+
+	return rep
+}
+
+/**
+ *	@Param:
+ *		@Desc     Parameter	    Type	    mandatory    Default	    Description
+ *		::param : currency    	string	    true		                eg:usdt
+ *		::param : chain    	    string	    true		                eg:Tron
+ *		::param : status    	string	    false		                SUBMIT, REVIEW, AUDITED, PENDING, SUCCESS, FAIL, CANCEL
+ *		::param : fromId    	number	    false		                start id
+ *		::param : direction     string	    false		                query direction:PREV, NEXT
+ *		::param : limit         number	    false		 10             Limit number, max 200
+ *		::param : startTime     number	    false		                eg:1657682804112
+ *		::param : endTime       number	    false
+ *	@Return
+ *		See: https://doc.xt.com/#deposit_withdrawalwithdrawHistory
+**/
+type ItemGetTransferHistory struct {
+	ID            int64           `json:"id"`
+	Currency      string          `json:"currency"`
+	Chain         string          `json:"chain"`
+	Address       string          `json:"address"`
+	Memo          string          `json:"memo"`
+	Status        string          `json:"status"`
+	Amount        decimal.Decimal `json:"amount"`
+	Fee           decimal.Decimal `json:"fee"`
+	ConfirmTimes  int             `json:"confirmTimes"`
+	TransactionID string          `json:"transactionId"`
+	CreatedTime   int64           `json:"createdTime"`
+}
+
+type ResultGetTransferHistory struct {
+	HasPrev bool                     `json:"hasPrev"`
+	HasNext bool                     `json:"hasNext"`
+	Items   []ItemGetTransferHistory `json:"items"`
+}
+
+type ResponseGetTransferHistory struct {
+	RC     int                      `json:"rc"`
+	MC     string                   `json:"mc"`
+	MA     []int                    `json:"ma"`
+	Result ResultGetTransferHistory `json:"result"`
+}
+
+func (s SignedHttpAPI) GetWithdrawHistory(ctx context.Context, data map[string]interface{}) *APIBody {
+	path := "/v4/withdraw/history"
+	url := s.baseURL() + path
+	auth := NewAuth(s, path, "GET")
+	auth.SetUrlencode(true)
+
+	headers, err := auth.createPayload(data)
+	if err != nil {
+		return APIFailure(err, url)
+	}
+
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
+
+	return rep
+}
+
+/**
+ *	@Param:
+ *		@Desc     Parameter	    Type	    mandatory    Default	    Description
+ *		::param : currency    	string	    true		                eg:usdt
+ *		::param : chain    	    string	    true		                eg:Tron
+ *		::param : status    	string	    false		                SUBMIT, REVIEW, AUDITED, PENDING, SUCCESS, FAIL, CANCEL
+ *		::param : fromId    	number	    false		                start id
+ *		::param : direction     string	    false		                query direction:PREV, NEXT
+ *		::param : limit         number	    false		 10             Limit number, max 200
+ *		::param : startTime     number	    false		                eg:1657682804112
+ *		::param : endTime       number	    false
+ *	@Return
+ *		Same as GetWithdrawHistory, see ResponseGetTransferHistory
+**/
+func (s SignedHttpAPI) GetDepositHistory(ctx context.Context, data map[string]interface{}) *APIBody {
+	path := "/v4/deposit/history"
+	url := s.baseURL() + path
+	auth := NewAuth(s, path, "GET")
+	auth.SetUrlencode(true)
+
+	headers, err := auth.createPayload(data)
+	if err != nil {
+		return APIFailure(err, url)
+	}
+
+	requestPerpare := NewRequestPerpare(s.HTTPClient)
+	rep := requestPerpare.RequesParam(ctx, "GET", url, headers, data)
 
 	return rep
 }
 
 type PublicHttpAPI struct {
+	HttpOption
 }
 
 /**
@@ -516,11 +823,22 @@ type PublicHttpAPI struct {
  *    }
  *  }
  */
-func (p PublicHttpAPI) GetServerTime() *APIBody {
+type ResultGetServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+type ResponseGetServerTime struct {
+	RC     int                 `json:"rc"`
+	MC     string              `json:"mc"`
+	MA     []int               `json:"ma"`
+	Result ResultGetServerTime `json:"result"`
+}
+
+func (p PublicHttpAPI) GetServerTime(ctx context.Context) *APIBody {
 	path := "/v4/public/time"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers, data := map[string]string{}, map[string]interface{}{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -583,11 +901,67 @@ type ResponseGetCoinsInfo struct {
 	Result ResultGetCoinsInfo `json:"result"`
 }
 
-func (p PublicHttpAPI) GetCoinsInfo() *APIBody {
+func (p PublicHttpAPI) GetCoinsInfo(ctx context.Context) *APIBody {
 	path := "/v4/public/currencies"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers, data := map[string]string{}, map[string]interface{}{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
+
+	return rep
+}
+
+/**
+* @Param: None
+* @Return
+* 	{
+* 		"rc": 0,
+* 		"mc": "SUCCESS",
+* 		"ma": [],
+* 		"result": [
+* 			{
+* 			"currency": "usdt",
+* 			"supportChains": [
+* 				{
+* 				"chain": "Tron",
+* 				"depositEnabled": true,
+* 				"withdrawEnabled": true,
+* 				"withdrawFeeAmount": 1,
+* 				"withdrawFeeCurrency": "usdt",
+* 				"withdrawMinAmount": 10,
+* 				"depositFeeRate": 0
+* 				}
+* 			]
+* 			}
+* 		]
+* 	}
+**/
+type ChainGetSupportCurrency struct {
+	Chain               string          `json:"chain"`
+	DepositEnabled      bool            `json:"depositEnabled"`
+	WithdrawEnabled     bool            `json:"withdrawEnabled"`
+	WithdrawFeeAmount   decimal.Decimal `json:"withdrawFeeAmount"`
+	WithdrawFeeCurrency string          `json:"withdrawFeeCurrency"`
+	WithdrawMinAmount   decimal.Decimal `json:"withdrawMinAmount"`
+	DepositFeeRate      decimal.Decimal `json:"depositFeeRate"`
+}
+
+type CurrencyGetSupportCurrency struct {
+	Currency      string                    `json:"currency"`
+	SupportChains []ChainGetSupportCurrency `json:"supportChains"`
+}
+
+type ResponseGetSupportCurrency struct {
+	RC     int                          `json:"rc"`
+	MC     string                       `json:"mc"`
+	MA     []int                        `json:"ma"`
+	Result []CurrencyGetSupportCurrency `json:"result"`
+}
+
+func (p PublicHttpAPI) GetSupportCurrency(ctx context.Context) *APIBody {
+	path := "/v4/public/wallet/support/currency"
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
+	headers, data := map[string]string{}, map[string]interface{}{}
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -597,11 +971,11 @@ func (p PublicHttpAPI) GetCoinsInfo() *APIBody {
 * @Return
 * 	See: https://xt-com.github.io/xt4-api/#market_cn2symbol
 **/
-func (p PublicHttpAPI) GetAllMarketConfig() *APIBody {
-	path := "/future/market/v1/public/symbol/list"
-	requestPerpare := NewRequestPerpare()
+func (p PublicHttpAPI) GetAllMarketConfig(ctx context.Context) *APIBody {
+	path := "/v4/public/symbol"
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers, data := map[string]string{}, map[string]interface{}{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -645,6 +1019,8 @@ type SymbolGetMarketConfig struct {
 	Filters                []struct {
 		Filter           string `json:"filter"`
 		Min              string `json:"min,omitempty"`
+		Max              string `json:"max,omitempty"`
+		TickSize         string `json:"tickSize,omitempty"`
 		BuyMaxDeviation  string `json:"buyMaxDeviation,omitempty"`
 		SellMaxDeviation string `json:"sellMaxDeviation,omitempty"`
 		MaxDeviation     string `json:"maxDeviation,omitempty"`
@@ -664,11 +1040,11 @@ type ResponseGetMarketConfig struct {
 	Result ResultGetMarketConfig `json:"result"`
 }
 
-func (p PublicHttpAPI) GetMarketConfig(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetMarketConfig(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/symbol"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -711,11 +1087,11 @@ type ResponseGetDepth struct {
 	Result ResultGetDepth `json:"result"`
 }
 
-func (p PublicHttpAPI) GetDepth(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetDepth(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/depth"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -747,11 +1123,11 @@ func (p PublicHttpAPI) GetDepth(data map[string]interface{}) *APIBody {
  *			]
  *		}
 **/
-func (p PublicHttpAPI) GetKline(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetKline(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/kline"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -780,11 +1156,11 @@ func (p PublicHttpAPI) GetKline(data map[string]interface{}) *APIBody {
  *         ]
  *     }
 **/
-func (p PublicHttpAPI) GetTrades(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetTrades(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/trade/recent"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -808,11 +1184,11 @@ func (p PublicHttpAPI) GetTrades(data map[string]interface{}) *APIBody {
  *         ]
  *     }
 **/
-func (p PublicHttpAPI) GetTicker(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetTicker(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/ticker/price"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -863,11 +1239,11 @@ type ResponseGetFullTicker struct {
 	Result []ResultGetFullTicker `json:"result"`
 }
 
-func (p PublicHttpAPI) GetFullTicker(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetFullTicker(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/ticker"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -893,11 +1269,11 @@ func (p PublicHttpAPI) GetFullTicker(data map[string]interface{}) *APIBody {
  *			]
  *		}
 **/
-func (p PublicHttpAPI) GetBestTicker(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) GetBestTicker(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/ticker/book"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
@@ -927,11 +1303,11 @@ func (p PublicHttpAPI) GetBestTicker(data map[string]interface{}) *APIBody {
  *			]
  *		}
 **/
-func (p PublicHttpAPI) Get24hTicker(data map[string]interface{}) *APIBody {
+func (p PublicHttpAPI) Get24hTicker(ctx context.Context, data map[string]interface{}) *APIBody {
 	path := "/v4/public/ticker/24h"
-	requestPerpare := NewRequestPerpare()
+	requestPerpare := NewRequestPerpare(p.HTTPClient)
 	headers := map[string]string{}
-	rep := requestPerpare.RequesParam("GET", BaseUrl+path, headers, data)
+	rep := requestPerpare.RequesParam(ctx, "GET", p.baseURL()+path, headers, data)
 
 	return rep
 }
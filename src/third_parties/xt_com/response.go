@@ -6,6 +6,9 @@ type APIBody struct {
 	Msg     string `json:"msg"`
 	Path    string `json:"path"`
 	Service string `json:"service"`
+	// StatusCode is the HTTP status of the response, and Err the error of the request when it failed to be sent
+	StatusCode int   `json:"-"`
+	Err        error `json:"-"`
 }
 
 func APIResponse(data, msg, path string, status bool) *APIBody {
@@ -16,3 +19,10 @@ func APIResponse(data, msg, path string, status bool) *APIBody {
 		Path:   path,
 	}
 }
+
+// APIFailure returns the response of a request failing with err
+func APIFailure(err error, path string) *APIBody {
+	resp := APIResponse(err.Error(), "Failed", path, false)
+	resp.Err = err
+	return resp
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
-	"time"
 )
 
 const (
@@ -77,7 +76,7 @@ func (a *Auth) createHeader() url.Values {
 	u.Set("validate-algorithms", VALIDATE_ALGORITHMS)
 	u.Set("validate-appkey", a.signed.Accesskey)
 	u.Set("validate-recvwindow", VALIDATE_RECVWINDOW)
-	nt := time.Now().UnixMilli()
+	nt := a.signed.now().UnixMilli()
 	value := strconv.FormatInt(nt, 10)
 	u.Set("validate-timestamp", value)
 
@@ -1,12 +1,14 @@
 package xt_com
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
 	"strconv"
 	"time"
-
-	"github.com/parnurzeal/gorequest"
-	r "github.com/solos/requests"
 )
 
 const (
@@ -14,16 +16,16 @@ const (
 )
 
 type RequestPerpare struct {
-	request *r.Request
+	client *http.Client
 }
 
-func NewRequestPerpare() *RequestPerpare {
+// NewRequestPerpare sends the requests through client, or http.DefaultClient when nil
+func NewRequestPerpare(client *http.Client) *RequestPerpare {
+	if client == nil {
+		client = http.DefaultClient
+	}
 	return &RequestPerpare{
-		request: &r.Request{
-			Args: r.M{
-				"timeout": TIMEOUT,
-			},
-		},
+		client: client,
 	}
 }
 
@@ -33,36 +35,58 @@ func NewRequestPerpare() *RequestPerpare {
  * @param {*} queryVal
  * @return {*}
  */
-func (rp *RequestPerpare) queryStruct(s *gorequest.SuperAgent, content interface{}) (err error) {
-	if marshalContent, err := json.Marshal(content); err != nil {
+func (rp *RequestPerpare) queryStruct(query url.Values, content interface{}) (err error) {
+	marshalContent, err := json.Marshal(content)
+	if err != nil {
 		return err
-	} else {
-		var val map[string]interface{}
-		if err := json.Unmarshal(marshalContent, &val); err != nil {
-			s.Errors = append(s.Errors, err)
-		} else {
-			for k, v := range val {
-				var queryVal string
-				switch t := v.(type) {
-				case string:
-					queryVal = t
-				case float64:
-					queryVal = strconv.FormatFloat(t, 'f', -1, 64)
-				case time.Time:
-					queryVal = t.Format(time.RFC3339)
-				default:
-					j, err := json.Marshal(v)
-					if err != nil {
-						continue
-					}
-					queryVal = string(j)
-				}
-				s.QueryData.Add(k, queryVal)
+	}
+
+	var val map[string]interface{}
+	if err := json.Unmarshal(marshalContent, &val); err != nil {
+		return err
+	}
+
+	for k, v := range val {
+		var queryVal string
+		switch t := v.(type) {
+		case string:
+			queryVal = t
+		case float64:
+			queryVal = strconv.FormatFloat(t, 'f', -1, 64)
+		case time.Time:
+			queryVal = t.Format(time.RFC3339)
+		default:
+			j, err := json.Marshal(v)
+			if err != nil {
+				continue
 			}
+			queryVal = string(j)
 		}
+		query.Add(k, queryVal)
+	}
+
+	return nil
+}
+
+func (rp *RequestPerpare) do(req *http.Request, url string, headers map[string]string) *APIBody {
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+
+	resp, err := rp.client.Do(req)
+	if err != nil {
+		return APIFailure(err, url)
 	}
+	defer resp.Body.Close()
 
-	return
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return APIFailure(err, url)
+	}
+
+	res := APIResponse(string(body), "Success", url, true)
+	res.StatusCode = resp.StatusCode
+	return res
 }
 
 // Make the request QueryString
@@ -75,36 +99,28 @@ func (rp *RequestPerpare) queryStruct(s *gorequest.SuperAgent, content interface
  * @return {*}
  */
 func (rp *RequestPerpare) RequesParam(
+	ctx context.Context,
 	method, url string,
 	headers map[string]string,
 	data map[string]interface{},
 ) *APIBody {
 
-	request := gorequest.New()
-
-	switch method {
-	case "GET":
-		request = request.Get(url)
-	case "POST":
-		request = request.Post(url)
-	default:
-		request = request.Get(url)
-	}
-	err := rp.queryStruct(request, data)
-	if err != nil {
-		return APIResponse(err.Error(), "Failed", url, false)
+	if method != "POST" {
+		method = "GET"
 	}
 
-	for k, v := range headers {
-		request.AppendHeader(k, v)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return APIFailure(err, url)
 	}
 
-	_, body, errs := request.End()
-	if errs != nil {
-		return APIResponse(errs[0].Error(), "Failed", url, false)
+	query := req.URL.Query()
+	if err := rp.queryStruct(query, data); err != nil {
+		return APIFailure(err, url)
 	}
+	req.URL.RawQuery = query.Encode()
 
-	return APIResponse(body, "Success", url, true)
+	return rp.do(req, url, headers)
 }
 
 // Make a request to JsonBody
@@ -117,31 +133,25 @@ func (rp *RequestPerpare) RequesParam(
  * @return {*}
  */
 func (rp *RequestPerpare) RequesJson(
+	ctx context.Context,
 	method, url string,
 	headers map[string]string,
 	data map[string]interface{},
 ) *APIBody {
 
-	request := gorequest.New()
-
-	switch method {
-	case "POST":
-		request = request.Post(url).Send(data)
-	case "DELETE":
-		request = request.Delete(url).Send(data)
-	default:
-		request = request.Post(url).Send(data)
+	if method != "DELETE" {
+		method = "POST"
 	}
 
-	for k, v := range headers {
-		request.AppendHeader(k, v)
+	content, err := json.Marshal(data)
+	if err != nil {
+		return APIFailure(err, url)
 	}
 
-	_, body, err := request.End()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(content))
 	if err != nil {
-		return APIResponse(body, "Failed", url, false)
+		return APIFailure(err, url)
 	}
 
-	return APIResponse(body, "Success", url, true)
-
+	return rp.do(req, url, headers)
 }